@@ -0,0 +1,38 @@
+package domain
+
+// AgeBand is the per 90 output of every player of a position while their age was in [MinAge, MaxAge]
+type AgeBand struct {
+	MinAge      int                `json:"min_age"`
+	MaxAge      int                `json:"max_age"`
+	Players     int                `json:"players"`
+	Appearances int                `json:"appearances"`
+	Minutes     int                `json:"minutes"`
+	Per90       map[string]float64 `json:"per_90"`
+	Output      float64            `json:"output"` // per 90 output relative to the position average, 1.0 is average
+}
+
+type AgeCurve struct {
+	Position string     `json:"position"`
+	PeakBand *AgeBand   `json:"peak_band"`
+	Bands    []*AgeBand `json:"bands"`
+}
+
+// CareerPhase is where a player is on the age curve of their position
+type CareerPhase string
+
+const (
+	CareerDevelopment CareerPhase = "development"
+	CareerPeak        CareerPhase = "peak"
+	CareerDecline     CareerPhase = "decline"
+)
+
+type PlayerCareerPhase struct {
+	PlayerID string             `json:"player_id"`
+	Position string             `json:"position"`
+	Age      int                `json:"age"`
+	Phase    CareerPhase        `json:"phase"`
+	Per90    map[string]float64 `json:"per_90"`   // output over the last 365 days
+	Expected map[string]float64 `json:"expected"` // output of the position at the player's age
+	// RelativeIndex is the player's output against the expected output at their age, 1.0 is on the curve
+	RelativeIndex float64 `json:"relative_index"`
+}
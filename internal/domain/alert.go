@@ -0,0 +1,108 @@
+package domain
+
+import (
+	"time"
+)
+
+// AlertMetric is the value an alert rule watches
+type AlertMetric string
+
+const (
+	// AlertRollingRatingChange percent change of the overall rating of the last Window matches against the Window matches before
+	AlertRollingRatingChange AlertMetric = "rolling_rating_change"
+	// AlertYellowCards yellow cards counting towards the next accumulation ban in the competition of the match
+	AlertYellowCards AlertMetric = "yellow_cards"
+	// AlertWorkloadRatio acute to chronic workload ratio at the match date
+	AlertWorkloadRatio AlertMetric = "workload_ratio"
+)
+
+// AlertOperator compares the watched value with the threshold of a rule
+type AlertOperator string
+
+const (
+	AlertAbove   AlertOperator = ">"
+	AlertAtLeast AlertOperator = ">="
+	AlertBelow   AlertOperator = "<"
+	AlertAtMost  AlertOperator = "<="
+)
+
+// Compare tell if value against threshold satisfies the operator
+func (o AlertOperator) Compare(value, threshold float64) bool {
+	switch o {
+	case AlertAbove:
+		return value > threshold
+	case AlertAtLeast:
+		return value >= threshold
+	case AlertBelow:
+		return value < threshold
+	case AlertAtMost:
+		return value <= threshold
+	}
+	return false
+}
+
+// AlertRule is a user defined condition on a player metric evaluated after new stats are recorded,
+// a rule without player and team applies to every player
+type AlertRule struct {
+	ID        string        `json:"id"`
+	Name      string        `json:"name"`
+	Metric    AlertMetric   `json:"metric"`
+	Operator  AlertOperator `json:"operator"`
+	Threshold float64       `json:"threshold"`
+	Window    int           `json:"window"` // matches of the rolling metrics, default 5
+	PlayerID  string        `json:"player_id"`
+	TeamID    string        `json:"team_id"`
+	Notifiers []string      `json:"notifiers"` // names of the notifiers to deliver through, empty for all
+	Disabled  bool          `json:"disabled"`
+	CreatedAt time.Time     `json:"created_at"`
+	UpdatedAt time.Time     `json:"updated_at"`
+}
+
+// AlertState is the handling state of a triggered alert
+type AlertState string
+
+const (
+	AlertOpen         AlertState = "open"
+	AlertAcknowledged AlertState = "acknowledged"
+)
+
+// Alert is a triggered alert rule
+type Alert struct {
+	ID             string      `json:"id"`
+	RuleID         string      `json:"rule_id"`
+	RuleName       string      `json:"rule_name"`
+	PlayerID       string      `json:"player_id"`
+	MatchID        string      `json:"match_id"` // match whose stats triggered the alert
+	Metric         AlertMetric `json:"metric"`
+	Value          float64     `json:"value"`
+	Threshold      float64     `json:"threshold"`
+	Message        string      `json:"message"`
+	State          AlertState  `json:"state"`
+	TriggeredAt    time.Time   `json:"triggered_at"`
+	AcknowledgedAt *time.Time  `json:"acknowledged_at,omitempty"`
+}
+
+// AlertFilter selects alerts, empty fields match every alert
+type AlertFilter struct {
+	RuleID   string     `json:"rule_id"`
+	PlayerID string     `json:"player_id"`
+	State    AlertState `json:"state"`
+}
+
+type AlertRepository interface {
+	SaveRule(rule *AlertRule) error
+	GetRule(id string) (*AlertRule, error)
+	ListRules() ([]*AlertRule, error)
+	DeleteRule(id string) error
+	CreateAlert(alert *Alert) error
+	GetAlert(id string) (*Alert, error)
+	UpdateAlert(alert *Alert) error
+	// ListAlerts alerts of the filter, latest first
+	ListAlerts(filter AlertFilter) ([]*Alert, error)
+}
+
+// Notifier delivers triggered alerts
+type Notifier interface {
+	Name() string
+	Notify(alert *Alert) error
+}
@@ -1,5 +1,9 @@
 package domain
 
+import (
+	"context"
+)
+
 type PerformanceMetrics struct {
 	PlayerID           string  `json:"player_id"`
 	GoalsPerMinute     float64 `json:"goals_per_minute"`
@@ -11,9 +15,31 @@ type PerformanceMetrics struct {
 	OverallRating      float64 `json:"overall_rating"`
 }
 
+// AnalyticsService timeRange arguments are written in the syntax of ParseTimeRange
 type AnalyticsService interface {
 	CalculatePlayerPerformance(playerID string, timeRange string) (*PerformanceMetrics, error)
+	ExplainPlayerRating(playerID string, timeRange string) (*RatingBreakdown, error)
 	ComparePlayerPerformance(playerIDs []string) (map[string]*PerformanceMetrics, error)
+	CalculatePlayersPerformance(ctx context.Context, playerIDs []string, opts BatchOptions) (*BatchResult, error)
+	ComparePlayersWithConfidence(playerIDs []string, opts ComparisonOptions) (*PlayerComparison, error)
 	GetPlayerProgressOverTime(playerID string, startDate, endDate string) ([]*PerformanceMetrics, error)
+	GetPlayerProgressSeries(playerID string, startDate, endDate string, opts ProgressOptions) ([]*ProgressPoint, error)
 	GetTeamPerformanceByPosition(teamID string) (map[string][]*PerformanceMetrics, error)
+	CalculateTeamPerformance(teamID string, timeRange string) (*TeamMetrics, error)
+	GetTeamMatchMetrics(teamID string, timeRange string) ([]*TeamMatchMetrics, error)
+	GetTeamProgressSeries(teamID string, startDate, endDate string, opts ProgressOptions) ([]*TeamProgressPoint, error)
+	CompareTeamPerformance(teamIDs []string, timeRange string) (map[string]*TeamMetrics, error)
+	GetPlayerContribution(playerID string, timeRange string) (*PlayerContribution, error)
+	GetSquadContributions(teamID string, timeRange string) (*SquadContributions, error)
+	FindSimilarPlayers(query SimilarityQuery) ([]*SimilarPlayer, error)
+	PredictMatchOutcome(homeTeamID, awayTeamID string) (*MatchPrediction, error)
+	PredictScheduledMatches(startDate, endDate string) ([]*MatchPrediction, error)
+	GetAgeCurve(position string) (*AgeCurve, error)
+	GetPlayerCareerPhase(playerID string) (*PlayerCareerPhase, error)
+	GetPlayerSplits(playerID string, timeRange string, dimensions []SplitDimension) (*PerformanceSplits, error)
+	GetTeamSplits(teamID string, timeRange string, dimensions []SplitDimension) (*PerformanceSplits, error)
+	CalculateAdjustedPerformance(playerID string, timeRange string, source StrengthSource) (*AdjustedPerformance, error)
+	CalculateAdjustedTeamPerformance(teamID string, timeRange string, source StrengthSource) (*AdjustedPerformance, error)
+	ClusterPlayerRoles(opts RoleOptions) (*RoleModel, error)
+	GetPlayerRole(playerID string, season string) (*PlayerRole, error)
 } 
\ No newline at end of file
@@ -0,0 +1,46 @@
+package domain
+
+import (
+	"time"
+)
+
+// MatchPredictor is a match outcome model that can be refitted on the matches known at a point in time
+type MatchPredictor interface {
+	Name() string
+	Fit(history []*Match, asOf time.Time) error
+	Predict(homeTeamID, awayTeamID string) (*MatchPrediction, error)
+}
+
+type BacktestOptions struct {
+	StartDate   time.Time `json:"start_date"`
+	EndDate     time.Time `json:"end_date"`
+	Competition string    `json:"competition"` // empty means every competition
+}
+
+// CalibrationBucket compares predicted probabilities in [Lower, Upper) with how often the outcome happened
+type CalibrationBucket struct {
+	Lower         float64 `json:"lower"`
+	Upper         float64 `json:"upper"`
+	Predictions   int     `json:"predictions"`
+	MeanPredicted float64 `json:"mean_predicted"`
+	ObservedRate  float64 `json:"observed_rate"`
+}
+
+type BacktestResult struct {
+	Model       string               `json:"model"`
+	Matches     int                  `json:"matches"`
+	Matchdays   int                  `json:"matchdays"`
+	LogLoss     float64              `json:"log_loss"`
+	BrierScore  float64              `json:"brier_score"`
+	Accuracy    float64              `json:"accuracy"`
+	Calibration []*CalibrationBucket `json:"calibration"`
+}
+
+// BacktestReport results of every model on the same matches, best log loss first
+type BacktestReport struct {
+	StartDate   time.Time         `json:"start_date"`
+	EndDate     time.Time         `json:"end_date"`
+	Competition string            `json:"competition"`
+	Results     []*BacktestResult `json:"results"`
+	CreatedAt   time.Time         `json:"created_at"`
+}
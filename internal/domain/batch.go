@@ -0,0 +1,19 @@
+package domain
+
+type BatchOptions struct {
+	TimeRange string `json:"time_range"` // in the syntax of ParseTimeRange, default season
+	Workers   int    `json:"workers"`    // concurrent players, default 8
+}
+
+// BatchFailure is a player whose metrics could not be calculated in a batch
+type BatchFailure struct {
+	PlayerID string `json:"player_id"`
+	Err      error  `json:"-"`
+	Message  string `json:"error"`
+}
+
+// BatchResult is the metrics of every player of a batch that succeeded and the players that failed
+type BatchResult struct {
+	Metrics  map[string]*PerformanceMetrics `json:"metrics"`
+	Failures []*BatchFailure                `json:"failures"`
+}
@@ -0,0 +1,24 @@
+package domain
+
+import (
+	"time"
+)
+
+// Clock tell the current time, analytics read "now" from it so "as of" queries are reproducible
+type Clock interface {
+	Now() time.Time
+}
+
+// SystemClock is the wall clock
+type SystemClock struct{}
+
+func (SystemClock) Now() time.Time {
+	return time.Now()
+}
+
+// FixedClock always tells the same time
+type FixedClock time.Time
+
+func (c FixedClock) Now() time.Time {
+	return time.Time(c)
+}
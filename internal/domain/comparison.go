@@ -0,0 +1,49 @@
+package domain
+
+// ComparisonMetrics names of the PerformanceMetrics compared with confidence intervals, higher is better for all of them
+var ComparisonMetrics = []string{
+	"goals_per_minute",
+	"assists_per_minute",
+	"pass_accuracy",
+	"shot_accuracy",
+	"defensive_efficiency",
+	"stamina",
+	"overall_rating",
+}
+
+type ComparisonOptions struct {
+	TimeRange  string  `json:"time_range"` // in the syntax of ParseTimeRange, default season
+	Samples    int     `json:"samples"`    // bootstrap resamples, default 2000
+	Confidence float64 `json:"confidence"` // interval coverage, default 0.9
+	Seed       int64   `json:"seed"`       // same seed and data give the same intervals
+}
+
+// MetricEstimate is a point estimate of a metric with its bootstrap confidence interval
+type MetricEstimate struct {
+	Value float64 `json:"value"`
+	Lower float64 `json:"lower"`
+	Upper float64 `json:"upper"`
+}
+
+type PlayerEstimate struct {
+	PlayerID string                     `json:"player_id"`
+	Matches  int                        `json:"matches"`
+	Metrics  map[string]*MetricEstimate `json:"metrics"`
+}
+
+// MetricProbability is the probability that player A is better than player B on a metric,
+// values near 0.5 mean the difference is noise
+type MetricProbability struct {
+	PlayerA     string  `json:"player_a"`
+	PlayerB     string  `json:"player_b"`
+	Metric      string  `json:"metric"`
+	Probability float64 `json:"probability"`
+}
+
+type PlayerComparison struct {
+	TimeRange  string               `json:"time_range"`
+	Samples    int                  `json:"samples"`
+	Confidence float64              `json:"confidence"`
+	Players    []*PlayerEstimate    `json:"players"`
+	Pairwise   []*MetricProbability `json:"pairwise"`
+}
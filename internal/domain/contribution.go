@@ -0,0 +1,40 @@
+package domain
+
+// TeamTotals is the output of a team over its completed matches, goals are the team's score and
+// the other totals are summed over the stats of its players
+type TeamTotals struct {
+	Matches          int `json:"matches"`
+	Goals            int `json:"goals"`
+	Assists          int `json:"assists"`
+	Shots            int `json:"shots"`
+	Minutes          int `json:"minutes"`
+	DefensiveActions int `json:"defensive_actions"` // tackles and interceptions
+}
+
+// PlayerContribution is a player's share of the output of their team, shares are in percent
+type PlayerContribution struct {
+	PlayerID         string      `json:"player_id"`
+	Name             string      `json:"name"`
+	TeamIDs          []string    `json:"team_ids"` // teams the player played for in the range
+	Appearances      int         `json:"appearances"`
+	Goals            int         `json:"goals"`
+	Assists          int         `json:"assists"`
+	Shots            int         `json:"shots"`
+	Minutes          int         `json:"minutes"`
+	DefensiveActions int         `json:"defensive_actions"`
+	GoalShare        float64     `json:"goal_share"`
+	AssistShare      float64     `json:"assist_share"`
+	ShotShare        float64     `json:"shot_share"`
+	MinuteShare      float64     `json:"minute_share"` // minutes played per 90 minutes of every team match
+	DefensiveShare   float64     `json:"defensive_share"`
+	GoalInvolvement  float64     `json:"goal_involvement"` // goals and assists per team goal
+	Team             *TeamTotals `json:"team"`             // totals the shares are relative to
+}
+
+// SquadContributions is the contribution of every player who played for a team, highest goal involvement first
+type SquadContributions struct {
+	TeamID    string                `json:"team_id"`
+	TimeRange string                `json:"time_range"`
+	Totals    *TeamTotals           `json:"totals"`
+	Players   []*PlayerContribution `json:"players"`
+}
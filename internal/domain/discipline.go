@@ -0,0 +1,68 @@
+package domain
+
+import (
+	"time"
+)
+
+// DisciplineRule is the card accumulation and ban policy of a competition
+type DisciplineRule struct {
+	ID                     string    `json:"id"`
+	Competition            string    `json:"competition"`
+	YellowThreshold        int       `json:"yellow_threshold"`          // every multiple of this many yellows triggers a ban
+	YellowBanMatches       int       `json:"yellow_ban_matches"`        // matches banned for yellow accumulation
+	StraightRedBanMatches  int       `json:"straight_red_ban_matches"`  // matches banned for a straight red card
+	SecondYellowBanMatches int       `json:"second_yellow_ban_matches"` // matches banned for a red card from two yellows
+	ResetRound             int       `json:"reset_round"`               // yellow totals reset from this round on, 0 means never
+	CreatedAt              time.Time `json:"created_at"`
+	UpdatedAt              time.Time `json:"updated_at"`
+}
+
+// SuspensionReason is the cause of a suspension
+type SuspensionReason string
+
+const (
+	SuspensionYellowAccumulation SuspensionReason = "yellow_accumulation"
+	SuspensionStraightRed        SuspensionReason = "straight_red"
+	SuspensionSecondYellow       SuspensionReason = "second_yellow"
+)
+
+type Suspension struct {
+	ID             string           `json:"id"`
+	PlayerID       string           `json:"player_id"`
+	Competition    string           `json:"competition"`
+	TriggerMatchID string           `json:"trigger_match_id"`
+	TriggerDate    time.Time        `json:"trigger_date"`
+	Reason         SuspensionReason `json:"reason"`
+	Matches        int              `json:"matches"` // length of the ban in matches
+	Served         int              `json:"served"`  // matches of the ban already served
+	CreatedAt      time.Time        `json:"created_at"`
+	UpdatedAt      time.Time        `json:"updated_at"`
+}
+
+type PlayerDisciplineStatus struct {
+	PlayerID         string        `json:"player_id"`
+	Name             string        `json:"name"`
+	TeamID           string        `json:"team_id"`
+	Competition      string        `json:"competition"`
+	YellowCards      int           `json:"yellow_cards"` // yellows counting towards the next accumulation ban
+	Suspended        bool          `json:"suspended"`
+	RemainingMatches int           `json:"remaining_matches"`
+	OneCardFromBan   bool          `json:"one_card_from_ban"`
+	Suspensions      []*Suspension `json:"suspensions"`
+}
+
+// MatchDisciplineReport players of both teams that miss a match or are one yellow card from a ban
+type MatchDisciplineReport struct {
+	MatchID     string                    `json:"match_id"`
+	Competition string                    `json:"competition"`
+	Suspended   []*PlayerDisciplineStatus `json:"suspended"`
+	AtRisk      []*PlayerDisciplineStatus `json:"at_risk"`
+}
+
+type DisciplineRepository interface {
+	SaveRule(rule *DisciplineRule) error
+	GetRule(competition string) (*DisciplineRule, error)
+	CreateSuspension(suspension *Suspension) error
+	ListSuspensions(playerID, competition string) ([]*Suspension, error)
+	RecordServed(suspensionID, matchID string) error
+}
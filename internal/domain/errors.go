@@ -0,0 +1,19 @@
+package domain
+
+import (
+	"errors"
+)
+
+var (
+	// ErrNoAppearances is returned when a player has no appearances to analyze
+	ErrNoAppearances = errors.New("player has no appearances in the time range")
+
+	// ErrNotFound is returned by repositories when the requested record does not exist
+	ErrNotFound = errors.New("record not found")
+
+	// ErrDuplicate is returned by repositories when a write conflicts with a unique record
+	ErrDuplicate = errors.New("record already exists")
+
+	// ErrInvalidTimeRange is returned when a time range can not be parsed or is inconsistent
+	ErrInvalidTimeRange = errors.New("invalid time range")
+)
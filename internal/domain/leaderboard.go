@@ -0,0 +1,62 @@
+package domain
+
+import (
+	"time"
+)
+
+// LeaderboardMetrics metrics a leaderboard can rank by, true for counting metrics that can be expressed per 90 minutes
+var LeaderboardMetrics = map[string]bool{
+	"matches_played":       true,
+	"minutes_played":       true,
+	"goals":                true,
+	"assists":              true,
+	"passes":               true,
+	"shots":                true,
+	"shots_on_target":      true,
+	"tackles":              true,
+	"interceptions":        true,
+	"fouls":                true,
+	"yellow_cards":         true,
+	"red_cards":            true,
+	"distance_covered":     true,
+	"pass_accuracy":        false,
+	"shot_accuracy":        false,
+	"tackles_per_game":     false,
+	"goals_per_minute":     false,
+	"assists_per_minute":   false,
+	"defensive_efficiency": false,
+	"stamina":              false,
+	"overall_rating":       false,
+}
+
+type LeaderboardQuery struct {
+	Metric      string    `json:"metric"`
+	Per90       bool      `json:"per_90"` // only for counting metrics
+	Competition string    `json:"competition"`
+	League      string    `json:"league"`
+	Season      string    `json:"season"` // e.g. "2024/25", sets StartDate and EndDate and reads the season rollups
+	StartDate   time.Time `json:"start_date"`
+	EndDate     time.Time `json:"end_date"`
+	TeamID      string    `json:"team_id"`
+	Position    string    `json:"position"`
+	MinAge      int       `json:"min_age"`
+	MaxAge      int       `json:"max_age"`
+	AsOf        time.Time `json:"as_of"` // ages of ranges without an end are taken at this time, set from the service clock
+	MinMinutes  int       `json:"min_minutes"`
+	Limit       int       `json:"limit"` // players tied with the last rank are included
+}
+
+type LeaderboardEntry struct {
+	Rank          int     `json:"rank"` // tied players share a rank
+	PlayerID      string  `json:"player_id"`
+	Name          string  `json:"name"`
+	Position      string  `json:"position"`
+	TeamID        string  `json:"team_id"`
+	Value         float64 `json:"value"`
+	MatchesPlayed int     `json:"matches_played"`
+	MinutesPlayed int     `json:"minutes_played"`
+}
+
+type LeaderboardRepository interface {
+	Top(query LeaderboardQuery) ([]*LeaderboardEntry, error)
+}
@@ -0,0 +1,93 @@
+package domain
+
+import (
+	"time"
+)
+
+// FormationSlot is one starting position of a formation, Positions are the player positions
+// that can fill it with the natural position first
+type FormationSlot struct {
+	Name      string   `json:"name"`
+	Positions []string `json:"positions"`
+}
+
+type Formation struct {
+	Name  string          `json:"name"`
+	Slots []FormationSlot `json:"slots"`
+}
+
+func goalkeeperSlot(name string) FormationSlot {
+	return FormationSlot{Name: name, Positions: []string{"Goalkeeper"}}
+}
+
+func defenderSlot(name string) FormationSlot {
+	return FormationSlot{Name: name, Positions: []string{"Defender", "Midfielder"}}
+}
+
+func midfielderSlot(name string) FormationSlot {
+	return FormationSlot{Name: name, Positions: []string{"Midfielder", "Defender", "Forward"}}
+}
+
+func forwardSlot(name string) FormationSlot {
+	return FormationSlot{Name: name, Positions: []string{"Forward", "Midfielder"}}
+}
+
+// Formations templates available to the lineup optimizer
+var Formations = map[string]Formation{
+	"4-4-2": {Name: "4-4-2", Slots: []FormationSlot{
+		goalkeeperSlot("GK"),
+		defenderSlot("LB"), defenderSlot("LCB"), defenderSlot("RCB"), defenderSlot("RB"),
+		midfielderSlot("LM"), midfielderSlot("LCM"), midfielderSlot("RCM"), midfielderSlot("RM"),
+		forwardSlot("LST"), forwardSlot("RST"),
+	}},
+	"4-3-3": {Name: "4-3-3", Slots: []FormationSlot{
+		goalkeeperSlot("GK"),
+		defenderSlot("LB"), defenderSlot("LCB"), defenderSlot("RCB"), defenderSlot("RB"),
+		midfielderSlot("LCM"), midfielderSlot("CM"), midfielderSlot("RCM"),
+		forwardSlot("LW"), forwardSlot("ST"), forwardSlot("RW"),
+	}},
+	"3-5-2": {Name: "3-5-2", Slots: []FormationSlot{
+		goalkeeperSlot("GK"),
+		defenderSlot("LCB"), defenderSlot("CB"), defenderSlot("RCB"),
+		midfielderSlot("LWB"), midfielderSlot("LCM"), midfielderSlot("CM"), midfielderSlot("RCM"), midfielderSlot("RWB"),
+		forwardSlot("LST"), forwardSlot("RST"),
+	}},
+}
+
+type LineupRequest struct {
+	TeamID           string         `json:"team_id"`
+	Formation        string         `json:"formation"`
+	Date             time.Time      `json:"date"`
+	Unavailable      []string       `json:"unavailable"`        // injured or otherwise unavailable players
+	MaxMinutes       map[string]int `json:"max_minutes"`        // minutes limit of workload-limited players, under 60 they do not start, above it has no effect
+	MaxWorkloadRatio float64        `json:"max_workload_ratio"` // players above this acute:chronic ratio are rested, 0 means no limit
+	RequiredSlots    []string       `json:"required_slots"`     // slots that must be filled, empty means every slot
+}
+
+type LineupCandidate struct {
+	PlayerID string  `json:"player_id"`
+	Name     string  `json:"name"`
+	Position string  `json:"position"`
+	Rating   float64 `json:"rating"` // rating of the player at the slot
+}
+
+type LineupSlot struct {
+	Slot         string             `json:"slot"`
+	Player       *LineupCandidate   `json:"player"` // nil if no eligible player is left
+	Alternatives []*LineupCandidate `json:"alternatives"`
+}
+
+type ExcludedPlayer struct {
+	PlayerID string `json:"player_id"`
+	Name     string `json:"name"`
+	Reason   string `json:"reason"`
+}
+
+type Lineup struct {
+	TeamID      string            `json:"team_id"`
+	Formation   string            `json:"formation"`
+	Date        time.Time         `json:"date"`
+	Slots       []*LineupSlot     `json:"slots"`
+	TotalRating float64           `json:"total_rating"`
+	Excluded    []*ExcludedPlayer `json:"excluded"`
+}
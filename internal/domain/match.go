@@ -5,17 +5,23 @@ import (
 )
 
 type Match struct {
-	ID           string    `json:"id"`
-	HomeTeamID   string    `json:"home_team_id"`
-	AwayTeamID   string    `json:"away_team_id"`
-	Date         time.Time `json:"date"`
-	Venue        string    `json:"venue"`
-	Competition  string    `json:"competition"`
-	HomeScore    int       `json:"home_score"`
-	AwayScore    int       `json:"away_score"`
-	Status       string    `json:"status"` // scheduled, ongoing, completed, cancelled
-	CreatedAt    time.Time `json:"created_at"`
-	UpdatedAt    time.Time `json:"updated_at"`
+	ID             string    `json:"id"`
+	HomeTeamID     string    `json:"home_team_id"`
+	AwayTeamID     string    `json:"away_team_id"`
+	Date           time.Time `json:"date"`
+	Venue          string    `json:"venue"`
+	Competition    string    `json:"competition"`
+	SeasonID       string    `json:"season_id"` // season of the competition the match belongs to, empty if not linked yet
+	Round          int       `json:"round"`     // matchday within the competition, 0 if unknown
+	HomeScore      int       `json:"home_score"`
+	AwayScore      int       `json:"away_score"`
+	HomePossession *float64  `json:"home_possession"` // share of ball possession in percent, nil if not recorded
+	AwayPossession *float64  `json:"away_possession"`
+	HomeCoach      string    `json:"home_coach"` // empty if not recorded
+	AwayCoach      string    `json:"away_coach"`
+	Status         string    `json:"status"` // scheduled, ongoing, completed, cancelled
+	CreatedAt      time.Time `json:"created_at"`
+	UpdatedAt      time.Time `json:"updated_at"`
 }
 
 type MatchRepository interface {
@@ -26,4 +32,4 @@ type MatchRepository interface {
 	List() ([]*Match, error)
 	ListByTeamID(teamID string) ([]*Match, error)
 	ListByDateRange(start, end time.Time) ([]*Match, error)
-} 
\ No newline at end of file
+}
@@ -0,0 +1,35 @@
+package domain
+
+import (
+	"time"
+)
+
+// MatchEventType is the kind of a match event
+type MatchEventType string
+
+const (
+	MatchEventGoal         MatchEventType = "goal"         // own goals are recorded for the team they count for
+	MatchEventSubstitution MatchEventType = "substitution" // player comes on for the related player
+)
+
+// MatchEvent is a timed event of a match
+type MatchEvent struct {
+	ID              string         `json:"id"`
+	MatchID         string         `json:"match_id"`
+	TeamID          string         `json:"team_id"`
+	Type            MatchEventType `json:"type"`
+	Minute          int            `json:"minute"`            // minute of play, stoppage time counts as the last minute of the half
+	PlayerID        string         `json:"player_id"`         // scorer, or the player coming on
+	RelatedPlayerID string         `json:"related_player_id"` // assisting player, or the player going off
+	StatsID         string         `json:"stats_id"`          // stats row of the player in the match, empty if not recorded
+	CreatedAt       time.Time      `json:"created_at"`
+	UpdatedAt       time.Time      `json:"updated_at"`
+}
+
+type MatchEventRepository interface {
+	Create(event *MatchEvent) error
+	GetByID(id string) (*MatchEvent, error)
+	Delete(id string) error
+	// ListByMatchIDs events of the matches ordered by match and minute
+	ListByMatchIDs(matchIDs []string) ([]*MatchEvent, error)
+}
@@ -0,0 +1,13 @@
+package domain
+
+// StatsObserver is notified after a PlayerMatchStats row is written with the row as it was
+// before and as it is after, old is nil for a created row and new is nil for a deleted row
+type StatsObserver interface {
+	StatsChanged(old, new *PlayerMatchStats)
+}
+
+// MatchObserver is notified after a Match row is written with the row as it was before and
+// as it is after, old is nil for a created row and new is nil for a deleted row
+type MatchObserver interface {
+	MatchChanged(old, new *Match)
+}
@@ -0,0 +1,49 @@
+package domain
+
+import (
+	"time"
+)
+
+// TeamStrength is the attack and defence rating of a team in a match model,
+// 1.0 is league average, attack above 1 scores more and defence above 1 concedes more
+type TeamStrength struct {
+	TeamID  string  `json:"team_id"`
+	Attack  float64 `json:"attack"`
+	Defence float64 `json:"defence"`
+}
+
+// MatchModel is a fitted Dixon-Coles model of match scores
+type MatchModel struct {
+	ID            string                   `json:"id"`
+	Version       string                   `json:"version"`
+	HomeAdvantage float64                  `json:"home_advantage"` // goal rate multiplier of the home team
+	Rho           float64                  `json:"rho"`            // low score dependence
+	DecayRate     float64                  `json:"decay_rate"`     // weight decay per day of match age
+	Strengths     map[string]*TeamStrength `json:"strengths"`
+	TrainedUntil  time.Time                `json:"trained_until"`
+	Matches       int                      `json:"matches"`
+	CreatedAt     time.Time                `json:"created_at"`
+}
+
+type MatchModelOptions struct {
+	DecayRate float64 `json:"decay_rate"` // default 0.0019, a half-life of about one year
+}
+
+type MatchPrediction struct {
+	MatchID           string      `json:"match_id,omitempty"`
+	HomeTeamID        string      `json:"home_team_id"`
+	AwayTeamID        string      `json:"away_team_id"`
+	ModelVersion      string      `json:"model_version"`
+	ExpectedHomeGoals float64     `json:"expected_home_goals"`
+	ExpectedAwayGoals float64     `json:"expected_away_goals"`
+	HomeWin           float64     `json:"home_win"`
+	Draw              float64     `json:"draw"`
+	AwayWin           float64     `json:"away_win"`
+	ScoreGrid         [][]float64 `json:"score_grid"` // probability of [home goals][away goals]
+}
+
+type MatchModelRepository interface {
+	Save(model *MatchModel) error
+	GetLatest() (*MatchModel, error)
+	GetByVersion(version string) (*MatchModel, error)
+}
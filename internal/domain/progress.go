@@ -0,0 +1,30 @@
+package domain
+
+import (
+	"time"
+)
+
+// ProgressWindow is the way player appearances are grouped into a progress series
+type ProgressWindow string
+
+const (
+	ProgressRolling ProgressWindow = "rolling" // last N appearances, one point per appearance
+	ProgressBlock   ProgressWindow = "block"   // consecutive non-overlapping groups of N appearances
+	ProgressWeek    ProgressWindow = "week"    // calendar week, starting on Monday
+	ProgressMonth   ProgressWindow = "month"   // calendar month
+	ProgressSeason  ProgressWindow = "season"  // football season
+	ProgressEWMA    ProgressWindow = "ewma"    // exponentially weighted moving average, one point per appearance
+)
+
+type ProgressOptions struct {
+	Window ProgressWindow `json:"window"`
+	Size   int            `json:"size"`  // appearances per window for rolling and block, default 5
+	Alpha  float64        `json:"alpha"` // smoothing factor for ewma in (0, 1], default 0.3
+}
+
+type ProgressPoint struct {
+	WindowStart time.Time           `json:"window_start"`
+	WindowEnd   time.Time           `json:"window_end"`
+	Appearances int                 `json:"appearances"`
+	Metrics     *PerformanceMetrics `json:"metrics"`
+}
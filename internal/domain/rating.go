@@ -0,0 +1,30 @@
+package domain
+
+// RatingComponent is one metric of the overall rating, the contributions of all components add up to it
+type RatingComponent struct {
+	Metric       string  `json:"metric"`
+	Raw          float64 `json:"raw"`          // value of the metric
+	Normalized   float64 `json:"normalized"`   // raw value brought to the common rating scale
+	Weight       float64 `json:"weight"`       // share of the normalized value in the rating
+	Contribution float64 `json:"contribution"` // normalized value times weight
+}
+
+// RatingDriver is a component that moves the rating away from the position average
+type RatingDriver struct {
+	Metric          string  `json:"metric"`
+	Contribution    float64 `json:"contribution"`
+	PositionAverage float64 `json:"position_average"` // average contribution of the players of the position
+	Difference      float64 `json:"difference"`       // contribution minus position average
+}
+
+// RatingBreakdown explain the overall rating of a player in a time range
+type RatingBreakdown struct {
+	PlayerID        string             `json:"player_id"`
+	Position        string             `json:"position"`
+	TimeRange       string             `json:"time_range"`
+	OverallRating   float64            `json:"overall_rating"`
+	PositionAverage float64            `json:"position_average"` // average overall rating of the players of the position
+	Components      []*RatingComponent `json:"components"`
+	PositiveDrivers []*RatingDriver    `json:"positive_drivers"` // largest difference first
+	NegativeDrivers []*RatingDriver    `json:"negative_drivers"` // most negative difference first
+}
@@ -0,0 +1,49 @@
+package domain
+
+import (
+	"time"
+)
+
+// RoleCluster is a data-driven playing role, a group of players with a similar per 90 profile
+type RoleCluster struct {
+	ID       string             `json:"id"`       // stable across re-runs, matched by centroid
+	Label    string             `json:"label"`    // generated from the strongest centroid features
+	Position string             `json:"position"` // most common position in the cluster
+	Centroid map[string]float64 `json:"centroid"` // z-score of every profile metric
+	Per90    map[string]float64 `json:"per90"`    // mean per 90 output of the members
+	Players  int                `json:"players"`
+}
+
+// PlayerRole is the role a player is assigned to in a role model
+type PlayerRole struct {
+	PlayerID   string  `json:"player_id"`
+	Name       string  `json:"name"`
+	Position   string  `json:"position"`
+	RoleID     string  `json:"role_id"`
+	Label      string  `json:"label"`
+	Confidence float64 `json:"confidence"` // 0 to 1, how clearly the player belongs to the role over the others
+	Minutes    int     `json:"minutes"`
+}
+
+// RoleModel is one clustering run of player profiles over a season
+type RoleModel struct {
+	ID          string         `json:"id"`
+	Season      string         `json:"season"`
+	K           int            `json:"k"`
+	Inertia     float64        `json:"inertia"` // sum of squared distances of players to their centroid
+	Clusters    []*RoleCluster `json:"clusters"`
+	Assignments []*PlayerRole  `json:"assignments"`
+	CreatedAt   time.Time      `json:"created_at"`
+}
+
+type RoleOptions struct {
+	Season     string `json:"season"`      // like "2024/25"
+	K          int    `json:"k"`           // number of roles, default 8
+	MinMinutes int    `json:"min_minutes"` // players with less minutes in the season are not clustered, default 900
+}
+
+type RoleRepository interface {
+	Save(model *RoleModel) error
+	GetLatest() (*RoleModel, error)
+	GetLatestBySeason(season string) (*RoleModel, error)
+}
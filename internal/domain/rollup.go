@@ -0,0 +1,49 @@
+package domain
+
+import (
+	"time"
+)
+
+// PlayerSeasonRollup is the running totals of a player in one competition of a season
+type PlayerSeasonRollup struct {
+	PlayerID        string    `json:"player_id"`
+	Season          string    `json:"season"` // like "2024/25"
+	Competition     string    `json:"competition"`
+	MatchesPlayed   int       `json:"matches_played"`
+	MinutesPlayed   int       `json:"minutes_played"`
+	Goals           int       `json:"goals"`
+	Assists         int       `json:"assists"`
+	Passes          int       `json:"passes"`
+	PassAccuracySum float64   `json:"pass_accuracy_sum"` // sum of the per match pass accuracy
+	Shots           int       `json:"shots"`
+	ShotsOnTarget   int       `json:"shots_on_target"`
+	Tackles         int       `json:"tackles"`
+	Interceptions   int       `json:"interceptions"`
+	Fouls           int       `json:"fouls"`
+	YellowCards     int       `json:"yellow_cards"`
+	RedCards        int       `json:"red_cards"`
+	DistanceCovered float64   `json:"distance_covered"`
+	UpdatedAt       time.Time `json:"updated_at"`
+}
+
+// RollupDrift is a rollup row that does not match the raw stats, a nil side means the row is missing
+type RollupDrift struct {
+	Expected *PlayerSeasonRollup `json:"expected"`
+	Actual   *PlayerSeasonRollup `json:"actual"`
+}
+
+type RollupReport struct {
+	Checked  int            `json:"checked"`
+	Drifted  []*RollupDrift `json:"drifted"`
+	Repaired bool           `json:"repaired"`
+}
+
+type RollupRepository interface {
+	// Apply add the totals of delta to its row, rows left without matches are removed
+	Apply(delta *PlayerSeasonRollup) error
+	List() ([]*PlayerSeasonRollup, error)
+	// ComputeFromRaw aggregate every rollup row from the raw player match stats
+	ComputeFromRaw() ([]*PlayerSeasonRollup, error)
+	Replace(rollup *PlayerSeasonRollup) error
+	Delete(playerID, season, competition string) error
+}
@@ -0,0 +1,29 @@
+package domain
+
+import (
+	"time"
+)
+
+// Season is one edition of a competition, matches between StartDate and EndDate (exclusive) belong to it
+type Season struct {
+	ID          string    `json:"id"`
+	Competition string    `json:"competition"`
+	Label       string    `json:"label"` // like "2024/25" for split-year seasons or "2025" for calendar-year ones
+	StartDate   time.Time `json:"start_date"`
+	EndDate     time.Time `json:"end_date"`
+	CreatedAt   time.Time `json:"created_at"`
+	UpdatedAt   time.Time `json:"updated_at"`
+}
+
+// Contains report whether t falls in the season
+func (s *Season) Contains(t time.Time) bool {
+	return !t.Before(s.StartDate) && t.Before(s.EndDate)
+}
+
+type SeasonRepository interface {
+	Create(season *Season) error
+	GetByID(id string) (*Season, error)
+	Update(season *Season) error
+	List() ([]*Season, error)
+	ListByCompetition(competition string) ([]*Season, error)
+}
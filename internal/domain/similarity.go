@@ -0,0 +1,44 @@
+package domain
+
+import (
+	"time"
+)
+
+// SimilarityMeasure is the way two metric vectors are compared
+type SimilarityMeasure string
+
+const (
+	SimilarityCosine    SimilarityMeasure = "cosine"
+	SimilarityEuclidean SimilarityMeasure = "euclidean"
+)
+
+type SimilarityQuery struct {
+	PlayerID   string            `json:"player_id"`
+	StartDate  time.Time         `json:"start_date"` // required
+	EndDate    time.Time         `json:"end_date"`   // required, not before the start date
+	Measure    SimilarityMeasure `json:"measure"`
+	TopK       int               `json:"top_k"`
+	Positions  []string          `json:"positions"`   // empty means any position
+	Leagues    []string          `json:"leagues"`     // empty means any league
+	MinAge     int               `json:"min_age"`     // 0 means no lower bound
+	MaxAge     int               `json:"max_age"`     // 0 means no upper bound
+	MinMinutes int               `json:"min_minutes"` // minimum minutes played in the range
+}
+
+type SimilarPlayer struct {
+	PlayerID string                `json:"player_id"`
+	Name     string                `json:"name"`
+	Position string                `json:"position"`
+	Score    float64               `json:"score"` // higher is more similar
+	Minutes  int                   `json:"minutes"`
+	Drivers  []*MetricContribution `json:"drivers"`
+}
+
+// MetricContribution is how much one per 90 metric makes two players alike,
+// a higher contribution means the metric drives the similarity more
+type MetricContribution struct {
+	Metric         string  `json:"metric"`
+	PlayerValue    float64 `json:"player_value"`
+	CandidateValue float64 `json:"candidate_value"`
+	Contribution   float64 `json:"contribution"`
+}
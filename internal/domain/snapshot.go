@@ -0,0 +1,56 @@
+package domain
+
+import (
+	"time"
+)
+
+// MetricsSnapshot is the performance of a player as it was calculated at the end of a match date
+type MetricsSnapshot struct {
+	ID           string              `json:"id"`
+	PlayerID     string              `json:"player_id"`
+	Date         time.Time           `json:"date"`          // match date, the metrics include every match up to its end
+	TimeRange    string              `json:"time_range"`    // range the metrics were calculated over, like "season"
+	ModelVersion string              `json:"model_version"` // version of the metric formulas
+	Metrics      *PerformanceMetrics `json:"metrics"`
+	CreatedAt    time.Time           `json:"created_at"`
+}
+
+// MetricsTrend is the snapshots of a player over a period with the direction of the rating
+type MetricsTrend struct {
+	PlayerID      string             `json:"player_id"`
+	TimeRange     string             `json:"time_range"`
+	ModelVersion  string             `json:"model_version"`
+	Snapshots     []*MetricsSnapshot `json:"snapshots"`       // ordered by date
+	RatingChange  float64            `json:"rating_change"`   // last minus first overall rating
+	SlopePerMonth float64            `json:"slope_per_month"` // least squares rating change per 30 days
+}
+
+type TrendQuery struct {
+	TimeRange string    `json:"time_range"` // range of the snapshots, default season
+	StartDate time.Time `json:"start_date"`
+	EndDate   time.Time `json:"end_date"` // zero for no end
+}
+
+// RatingChange is the change of a player's rating between the snapshots in effect at two dates
+type RatingChange struct {
+	PlayerID string           `json:"player_id"`
+	From     *MetricsSnapshot `json:"from"`
+	To       *MetricsSnapshot `json:"to"`
+	Change   float64          `json:"change"`
+}
+
+type RatingChangeQuery struct {
+	From      time.Time `json:"from"`
+	To        time.Time `json:"to"`
+	Threshold float64   `json:"threshold"`  // smallest absolute rating change reported, default 1
+	TimeRange string    `json:"time_range"` // range of the snapshots, default season
+}
+
+type SnapshotRepository interface {
+	// Save add the snapshot or replace the one of the same player, date, time range and model version
+	Save(snapshot *MetricsSnapshot) error
+	// ListByPlayer snapshots of the player from start to end (both included, zero end for no end), ordered by date
+	ListByPlayer(playerID, timeRange, modelVersion string, start, end time.Time) ([]*MetricsSnapshot, error)
+	// ListLatest latest snapshot of every player on or before date
+	ListLatest(date time.Time, timeRange, modelVersion string) ([]*MetricsSnapshot, error)
+}
@@ -0,0 +1,25 @@
+package domain
+
+// SplitDimension is the way matches are split into buckets
+type SplitDimension string
+
+const (
+	SplitVenue        SplitDimension = "venue"         // home, away
+	SplitCompetition  SplitDimension = "competition"   // competition of the match
+	SplitResult       SplitDimension = "result"        // win, draw, loss
+	SplitOpponentTier SplitDimension = "opponent_tier" // top, middle, bottom third of the opponent's competition table
+)
+
+type SplitBucket struct {
+	Dimension SplitDimension      `json:"dimension"`
+	Key       string              `json:"key"`
+	Matches   int                 `json:"matches"`
+	Metrics   *PerformanceMetrics `json:"metrics"` // for team splits PlayerID holds the team ID
+}
+
+// PerformanceSplits is a table of metrics per split bucket of a player or a team
+type PerformanceSplits struct {
+	SubjectID string         `json:"subject_id"`
+	TimeRange string         `json:"time_range"`
+	Buckets   []*SplitBucket `json:"buckets"`
+}
@@ -8,6 +8,7 @@ type PlayerMatchStats struct {
 	ID              string    `json:"id"`
 	PlayerID        string    `json:"player_id"`
 	MatchID         string    `json:"match_id"`
+	TeamID          string    `json:"team_id"` // team the player played for, empty if not recorded
 	MinutesPlayed   int       `json:"minutes_played"`
 	Goals           int       `json:"goals"`
 	Assists         int       `json:"assists"`
@@ -32,7 +33,16 @@ type PlayerMatchStatsRepository interface {
 	Delete(id string) error
 	ListByPlayerID(playerID string) ([]*PlayerMatchStats, error)
 	ListByMatchID(matchID string) ([]*PlayerMatchStats, error)
+	ListByMatchIDs(matchIDs []string) ([]*PlayerMatchStats, error)
 	GetPlayerSeasonStats(playerID string, season string) (*PlayerSeasonStats, error)
+	ListByPlayerAndDateRange(playerID string, start, end time.Time) ([]*PlayerAppearance, error)
+	ListByPlayersAndDateRange(playerIDs []string, start, end time.Time) ([]*PlayerAppearance, error)
+}
+
+// PlayerAppearance is a stats row joined with the match it was recorded in
+type PlayerAppearance struct {
+	Match *Match            `json:"match"`
+	Stats *PlayerMatchStats `json:"stats"`
 }
 
 type PlayerSeasonStats struct {
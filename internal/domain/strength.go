@@ -0,0 +1,22 @@
+package domain
+
+import (
+	"time"
+)
+
+// StrengthSource rates how strong a team was at a point in time, 1.0 is an average team
+type StrengthSource interface {
+	Name() string
+	Strength(teamID string, at time.Time) (float64, error)
+}
+
+// AdjustedPerformance raw metrics of a player or team next to the same metrics weighted by opponent strength
+type AdjustedPerformance struct {
+	SubjectID string              `json:"subject_id"`
+	Source    string              `json:"source"`
+	Matches   int                 `json:"matches"`
+	Raw       *PerformanceMetrics `json:"raw"`
+	Adjusted  *PerformanceMetrics `json:"adjusted"`
+	// StrengthOfSchedule is the average strength of the opponents faced, 1.0 is an average schedule
+	StrengthOfSchedule float64 `json:"strength_of_schedule"`
+}
@@ -0,0 +1,66 @@
+package domain
+
+import (
+	"time"
+)
+
+// SubstitutionImpact is a substitution with the goals from it to full time
+type SubstitutionImpact struct {
+	MatchID           string    `json:"match_id"`
+	Date              time.Time `json:"date"`
+	TeamID            string    `json:"team_id"`
+	Coach             string    `json:"coach"`
+	Minute            int       `json:"minute"`
+	PlayerOnID        string    `json:"player_on_id"`
+	PlayerOffID       string    `json:"player_off_id"`
+	GoalsForAfter     int       `json:"goals_for_after"`
+	GoalsAgainstAfter int       `json:"goals_against_after"`
+}
+
+// CoachSubstitutionTiming is the typical substitution timing of a coach
+type CoachSubstitutionTiming struct {
+	Coach              string  `json:"coach"` // empty for matches without a recorded coach
+	Matches            int     `json:"matches"`
+	Substitutions      int     `json:"substitutions"`
+	AverageMinute      float64 `json:"average_minute"`
+	MedianMinute       float64 `json:"median_minute"`
+	AverageFirstMinute float64 `json:"average_first_minute"` // of the first substitution of each match
+}
+
+// TeamSubstitutionReport is the impact of a team's substitutions over its completed matches with
+// recorded events, before and after split each match at the team's first substitution
+type TeamSubstitutionReport struct {
+	TeamID              string                     `json:"team_id"`
+	TimeRange           string                     `json:"time_range"`
+	Matches             int                        `json:"matches"`
+	IncompleteMatches   int                        `json:"incomplete_matches"` // left out, their goal events do not add up to the score
+	Substitutions       int                        `json:"substitutions"`
+	GoalsForBefore      int                        `json:"goals_for_before"`
+	GoalsAgainstBefore  int                        `json:"goals_against_before"`
+	MinutesBefore       int                        `json:"minutes_before"`
+	GoalsForAfter       int                        `json:"goals_for_after"`
+	GoalsAgainstAfter   int                        `json:"goals_against_after"`
+	MinutesAfter        int                        `json:"minutes_after"`
+	GoalDiffPer90Before float64                    `json:"goal_diff_per_90_before"`
+	GoalDiffPer90After  float64                    `json:"goal_diff_per_90_after"`
+	SubGoals            int                        `json:"sub_goals"`   // scored by players who came on
+	SubAssists          int                        `json:"sub_assists"` // by players who came on
+	Coaches             []*CoachSubstitutionTiming `json:"coaches"`
+	Details             []*SubstitutionImpact      `json:"details"`
+}
+
+// PlayerSubstitutionReport is the output of a player coming off the bench against their output as a starter
+type PlayerSubstitutionReport struct {
+	PlayerID            string             `json:"player_id"`
+	TimeRange           string             `json:"time_range"`
+	Starts              int                `json:"starts"`
+	SubAppearances      int                `json:"sub_appearances"`
+	SubbedOff           int                `json:"subbed_off"`
+	AverageMinuteOn     float64            `json:"average_minute_on"`
+	GoalsForAfterOn     int                `json:"goals_for_after_on"`     // team goals from coming on to full time
+	GoalsAgainstAfterOn int                `json:"goals_against_after_on"` // team goals conceded from coming on to full time
+	StarterMinutes      int                `json:"starter_minutes"`
+	SubMinutes          int                `json:"sub_minutes"`
+	StarterPer90        map[string]float64 `json:"starter_per_90"`
+	SubPer90            map[string]float64 `json:"sub_per_90"`
+}
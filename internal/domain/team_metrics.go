@@ -0,0 +1,51 @@
+package domain
+
+import (
+	"time"
+)
+
+// TeamMatchMetrics is the output of a team in one completed match
+type TeamMatchMetrics struct {
+	MatchID              string    `json:"match_id"`
+	TeamID               string    `json:"team_id"`
+	OpponentID           string    `json:"opponent_id"`
+	Date                 time.Time `json:"date"`
+	Home                 bool      `json:"home"`
+	GoalsFor             int       `json:"goals_for"`
+	GoalsAgainst         int       `json:"goals_against"`
+	Points               int       `json:"points"`
+	StatsRecorded        bool      `json:"stats_recorded"` // player stats of both teams were recorded, shots are zero otherwise
+	ShotsFor             int       `json:"shots_for"`
+	ShotsOnTargetFor     int       `json:"shots_on_target_for"`
+	ShotsAgainst         int       `json:"shots_against"`
+	ShotsOnTargetAgainst int       `json:"shots_on_target_against"`
+	Possession           *float64  `json:"possession"` // in percent, nil if not recorded
+}
+
+// TeamMetrics is the performance of a team over its completed matches, shot metrics only count the
+// matches with recorded player stats and possession the matches with recorded possession
+type TeamMetrics struct {
+	TeamID               string   `json:"team_id"`
+	Matches              int      `json:"matches"`
+	Wins                 int      `json:"wins"`
+	Draws                int      `json:"draws"`
+	Losses               int      `json:"losses"`
+	GoalsForPerMatch     float64  `json:"goals_for_per_match"`
+	GoalsAgainstPerMatch float64  `json:"goals_against_per_match"`
+	PointsPerGame        float64  `json:"points_per_game"`
+	CleanSheetRate       float64  `json:"clean_sheet_rate"`
+	ShotMatches          int      `json:"shot_matches"`
+	ShotsForPerMatch     float64  `json:"shots_for_per_match"`
+	ShotsAgainstPerMatch float64  `json:"shots_against_per_match"`
+	ShotAccuracyFor      float64  `json:"shot_accuracy_for"`
+	ShotAccuracyAgainst  float64  `json:"shot_accuracy_against"`
+	PossessionMatches    int      `json:"possession_matches"`
+	Possession           *float64 `json:"possession"` // average in percent, nil without recorded possession
+}
+
+type TeamProgressPoint struct {
+	WindowStart time.Time    `json:"window_start"`
+	WindowEnd   time.Time    `json:"window_end"`
+	Matches     int          `json:"matches"`
+	Metrics     *TeamMetrics `json:"metrics"`
+}
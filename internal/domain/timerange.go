@@ -0,0 +1,208 @@
+package domain
+
+import (
+	"errors"
+	"fmt"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// TimeRangeKind is the way a TimeRange selects matches
+type TimeRangeKind string
+
+const (
+	TimeRangeAll      TimeRangeKind = "all"
+	TimeRangeRelative TimeRangeKind = "relative" // the Days and Months before now
+	TimeRangeDates    TimeRangeKind = "dates"    // from Start to End, either may be open
+	TimeRangeSeason   TimeRangeKind = "season"   // the season labelled Season, the current one if empty
+	TimeRangeMatches  TimeRangeKind = "matches"  // the last Matches matches up to now
+)
+
+// DateLayout is the layout of the dates of a time range
+const DateLayout = "2006-01-02"
+
+// TimeRange select the matches an analysis covers, optionally of one competition only
+type TimeRange struct {
+	Kind        TimeRangeKind `json:"kind"`
+	Days        int           `json:"days,omitempty"` // relative ranges go back either days or months
+	Months      int           `json:"months,omitempty"`
+	Start       time.Time     `json:"start,omitempty"`
+	End         time.Time     `json:"end,omitempty"` // exclusive, the day after the last date of the range
+	Season      string        `json:"season,omitempty"`
+	Matches     int           `json:"matches,omitempty"`
+	Competition string        `json:"competition,omitempty"`
+}
+
+// ParseTimeRange parse the compact time range syntax, a range optionally followed by a competition filter:
+//
+//	all | week | month | season | season:2024/25 | last:10 | 30d | 6w | 3m | 1y
+//	2024-08-01..2025-05-31 | 2024-08-01.. | ..2025-05-31
+//	season,competition:Premier League
+//
+// date ranges include both dates, week and month are 7d and 1m
+func ParseTimeRange(s string) (TimeRange, error) {
+	terms := strings.Split(s, ",")
+
+	tr, err := parseRangeTerm(strings.TrimSpace(terms[0]))
+	if err != nil {
+		return TimeRange{}, err
+	}
+
+	for _, term := range terms[1:] {
+		term = strings.TrimSpace(term)
+		name, value, ok := strings.Cut(term, ":")
+		if !ok || name != "competition" {
+			return TimeRange{}, fmt.Errorf("%w %q: unknown filter %q", ErrInvalidTimeRange, s, term)
+		}
+		if tr.Competition != "" {
+			return TimeRange{}, fmt.Errorf("%w %q: competition given twice", ErrInvalidTimeRange, s)
+		}
+		tr.Competition = strings.TrimSpace(value)
+		if tr.Competition == "" {
+			return TimeRange{}, fmt.Errorf("%w %q: empty competition", ErrInvalidTimeRange, s)
+		}
+	}
+
+	if err := tr.Validate(); err != nil {
+		return TimeRange{}, fmt.Errorf("%w %q: %v", ErrInvalidTimeRange, s, err)
+	}
+
+	return tr, nil
+}
+
+// DateRange time range of the days from start to end, both formatted like 2006-01-02 and included
+func DateRange(start, end string) (TimeRange, error) {
+	return ParseTimeRange(start + ".." + end)
+}
+
+func parseRangeTerm(term string) (TimeRange, error) {
+	switch term {
+	case "all":
+		return TimeRange{Kind: TimeRangeAll}, nil
+	case "week":
+		return TimeRange{Kind: TimeRangeRelative, Days: 7}, nil
+	case "month":
+		return TimeRange{Kind: TimeRangeRelative, Months: 1}, nil
+	case "season":
+		return TimeRange{Kind: TimeRangeSeason}, nil
+	}
+
+	if label, ok := strings.CutPrefix(term, "season:"); ok {
+		if label == "" {
+			return TimeRange{}, fmt.Errorf("%w %q: empty season", ErrInvalidTimeRange, term)
+		}
+		return TimeRange{Kind: TimeRangeSeason, Season: label}, nil
+	}
+
+	if count, ok := strings.CutPrefix(term, "last:"); ok {
+		n, err := parseCount(count)
+		if err != nil {
+			return TimeRange{}, fmt.Errorf("%w %q: match count must be a number", ErrInvalidTimeRange, term)
+		}
+		return TimeRange{Kind: TimeRangeMatches, Matches: n}, nil
+	}
+
+	if start, end, ok := strings.Cut(term, ".."); ok {
+		tr := TimeRange{Kind: TimeRangeDates}
+		var err error
+		if start != "" {
+			if tr.Start, err = time.Parse(DateLayout, start); err != nil {
+				return TimeRange{}, fmt.Errorf("%w %q: start date must be like %s", ErrInvalidTimeRange, term, DateLayout)
+			}
+		}
+		if end != "" {
+			if tr.End, err = time.Parse(DateLayout, end); err != nil {
+				return TimeRange{}, fmt.Errorf("%w %q: end date must be like %s", ErrInvalidTimeRange, term, DateLayout)
+			}
+			tr.End = tr.End.AddDate(0, 0, 1)
+		}
+		return tr, nil
+	}
+
+	if len(term) >= 2 {
+		if n, err := parseCount(term[:len(term)-1]); err == nil {
+			switch term[len(term)-1] {
+			case 'd':
+				return TimeRange{Kind: TimeRangeRelative, Days: n}, nil
+			case 'w':
+				return TimeRange{Kind: TimeRangeRelative, Days: 7 * n}, nil
+			case 'm':
+				return TimeRange{Kind: TimeRangeRelative, Months: n}, nil
+			case 'y':
+				return TimeRange{Kind: TimeRangeRelative, Months: 12 * n}, nil
+			}
+		}
+	}
+
+	return TimeRange{}, fmt.Errorf("%w %q", ErrInvalidTimeRange, term)
+}
+
+// parseCount parse an unsigned count, strconv.Atoi alone would take a sign like in +5d
+func parseCount(s string) (int, error) {
+	if s == "" || s[0] < '0' || s[0] > '9' {
+		return 0, fmt.Errorf("invalid count %q", s)
+	}
+	return strconv.Atoi(s)
+}
+
+// Validate check the fields used by the kind of the range
+func (tr TimeRange) Validate() error {
+	switch tr.Kind {
+	case TimeRangeAll, TimeRangeSeason:
+	case TimeRangeRelative:
+		if tr.Days < 0 || tr.Months < 0 || (tr.Days > 0) == (tr.Months > 0) {
+			return errors.New("relative range goes back a positive number of either days or months")
+		}
+	case TimeRangeDates:
+		if tr.Start.IsZero() && tr.End.IsZero() {
+			return errors.New("date range needs a start or an end")
+		}
+		if !tr.Start.IsZero() && !tr.End.IsZero() && !tr.Start.Before(tr.End) {
+			return errors.New("date range ends before it starts")
+		}
+	case TimeRangeMatches:
+		if tr.Matches <= 0 {
+			return errors.New("match count must be positive")
+		}
+	default:
+		return fmt.Errorf("unknown kind %q", tr.Kind)
+	}
+
+	return nil
+}
+
+// String format the range in the compact syntax read by ParseTimeRange
+func (tr TimeRange) String() string {
+	var s string
+	switch tr.Kind {
+	case TimeRangeRelative:
+		if tr.Months == 0 {
+			s = fmt.Sprintf("%dd", tr.Days)
+		} else {
+			s = fmt.Sprintf("%dm", tr.Months)
+		}
+	case TimeRangeDates:
+		if !tr.Start.IsZero() {
+			s = tr.Start.Format(DateLayout)
+		}
+		s += ".."
+		if !tr.End.IsZero() {
+			s += tr.End.AddDate(0, 0, -1).Format(DateLayout)
+		}
+	case TimeRangeSeason:
+		s = "season"
+		if tr.Season != "" {
+			s += ":" + tr.Season
+		}
+	case TimeRangeMatches:
+		s = fmt.Sprintf("last:%d", tr.Matches)
+	default:
+		s = string(tr.Kind)
+	}
+
+	if tr.Competition != "" {
+		s += ",competition:" + tr.Competition
+	}
+	return s
+}
@@ -0,0 +1,68 @@
+package domain
+
+import (
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestParseTimeRange(t *testing.T) {
+	tests := []struct {
+		input string
+		want  TimeRange
+	}{
+		{"all", TimeRange{Kind: TimeRangeAll}},
+		{"week", TimeRange{Kind: TimeRangeRelative, Days: 7}},
+		{"3m", TimeRange{Kind: TimeRangeRelative, Months: 3}},
+		{"1y", TimeRange{Kind: TimeRangeRelative, Months: 12}},
+		{"season:2024/25", TimeRange{Kind: TimeRangeSeason, Season: "2024/25"}},
+		{"last:10,competition:MLS", TimeRange{Kind: TimeRangeMatches, Matches: 10, Competition: "MLS"}},
+		{"2024-08-01..2024-08-31", TimeRange{
+			Kind:  TimeRangeDates,
+			Start: time.Date(2024, 8, 1, 0, 0, 0, 0, time.UTC),
+			End:   time.Date(2024, 9, 1, 0, 0, 0, 0, time.UTC),
+		}},
+		{"..2024-08-31", TimeRange{Kind: TimeRangeDates, End: time.Date(2024, 9, 1, 0, 0, 0, 0, time.UTC)}},
+	}
+
+	for _, tt := range tests {
+		got, err := ParseTimeRange(tt.input)
+		if assert.NoError(t, err, tt.input) {
+			assert.Equal(t, tt.want, got, tt.input)
+		}
+	}
+}
+
+func TestParseTimeRangeInvalid(t *testing.T) {
+	for _, input := range []string{
+		"",
+		"seasn",
+		"season:",
+		"last:0",
+		"last:ten",
+		"last:+10",
+		"+5d",
+		"-1m",
+		"0d",
+		"2024-08-31..2024-08-01",
+		"2024-13-01..",
+		"..",
+		"all,competition:",
+		"all,venue:home",
+		"all,competition:MLS,competition:NWSL",
+	} {
+		_, err := ParseTimeRange(input)
+		assert.True(t, errors.Is(err, ErrInvalidTimeRange), "%q: %v", input, err)
+	}
+}
+
+func TestTimeRangeStringRoundTrip(t *testing.T) {
+	for _, input := range []string{"all", "7d", "3m", "season", "season:2025,competition:MLS", "last:5", "2024-08-01..2025-05-31", "2024-08-01.."} {
+		tr, err := ParseTimeRange(input)
+		if assert.NoError(t, err) {
+			assert.Equal(t, input, tr.String())
+		}
+	}
+}
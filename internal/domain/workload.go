@@ -0,0 +1,41 @@
+package domain
+
+import (
+	"time"
+)
+
+// WorkloadFlag marks a workload pattern that needs attention of the sports science staff
+type WorkloadFlag string
+
+const (
+	FlagMatchesWithin72h  WorkloadFlag = "matches_within_72h"  // two appearances less than 72 hours apart in the last 7 days
+	FlagThreeMatchesIn7d  WorkloadFlag = "three_matches_in_7d" // three or more appearances in the last 7 days
+	FlagHighWorkloadRatio WorkloadFlag = "high_workload_ratio" // acute:chronic ratio above 1.5
+	FlagLowWorkloadRatio  WorkloadFlag = "low_workload_ratio"  // acute:chronic ratio below 0.8
+)
+
+type PlayerWorkload struct {
+	PlayerID      string         `json:"player_id"`
+	Name          string         `json:"name"`
+	Position      string         `json:"position"`
+	Date          time.Time      `json:"date"`
+	Minutes7d     int            `json:"minutes_7d"`
+	Minutes14d    int            `json:"minutes_14d"`
+	Minutes28d    int            `json:"minutes_28d"`
+	Matches7d     int            `json:"matches_7d"`
+	Matches28d    int            `json:"matches_28d"`
+	Distance7d    float64        `json:"distance_7d"`
+	Distance28d   float64        `json:"distance_28d"`
+	AcuteLoad     float64        `json:"acute_load"`      // minutes in the last 7 days
+	ChronicLoad   float64        `json:"chronic_load"`    // average weekly minutes in the last 28 days
+	WorkloadRatio float64        `json:"workload_ratio"`  // acute:chronic workload ratio of minutes
+	DistanceRatio float64        `json:"distance_ratio"`  // acute:chronic workload ratio of distance covered
+	LastMatchDate time.Time      `json:"last_match_date"` // zero if no appearance in the last 28 days
+	Flags         []WorkloadFlag `json:"flags"`
+}
+
+type TeamWorkloadReport struct {
+	TeamID  string            `json:"team_id"`
+	Date    time.Time         `json:"date"`
+	Players []*PlayerWorkload `json:"players"`
+}
@@ -0,0 +1,44 @@
+package notifier
+
+import (
+	"encoding/json"
+	"football-analytics/internal/domain"
+	"os"
+	"sync"
+)
+
+type fileNotifier struct {
+	path string
+	mu   sync.Mutex
+}
+
+// NewFileNotifier create a Notifier appending every alert as a line of JSON to the file at path
+func NewFileNotifier(path string) domain.Notifier {
+	return &fileNotifier{path: path}
+}
+
+func (n *fileNotifier) Name() string {
+	return "file"
+}
+
+func (n *fileNotifier) Notify(alert *domain.Alert) error {
+	line, err := json.Marshal(alert)
+	if err != nil {
+		return err
+	}
+
+	n.mu.Lock()
+	defer n.mu.Unlock()
+
+	file, err := os.OpenFile(n.path, os.O_APPEND|os.O_CREATE|os.O_WRONLY, 0o644)
+	if err != nil {
+		return err
+	}
+
+	if _, err := file.Write(append(line, '\n')); err != nil {
+		file.Close()
+		return err
+	}
+
+	return file.Close()
+}
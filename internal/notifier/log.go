@@ -0,0 +1,27 @@
+package notifier
+
+import (
+	"football-analytics/internal/domain"
+	"log"
+)
+
+type logNotifier struct {
+	logger *log.Logger
+}
+
+// NewLogNotifier create a Notifier writing alerts to the logger, a nil logger is the standard logger
+func NewLogNotifier(logger *log.Logger) domain.Notifier {
+	if logger == nil {
+		logger = log.Default()
+	}
+	return &logNotifier{logger: logger}
+}
+
+func (n *logNotifier) Name() string {
+	return "log"
+}
+
+func (n *logNotifier) Notify(alert *domain.Alert) error {
+	n.logger.Printf("%s (player %s, value %.2f, threshold %.2f)", subject(alert), alert.PlayerID, alert.Value, alert.Threshold)
+	return nil
+}
@@ -0,0 +1,12 @@
+// Package notifier delivers triggered alerts to logs, files, webhooks and mail servers.
+package notifier
+
+import (
+	"fmt"
+	"football-analytics/internal/domain"
+)
+
+// subject one line summary of an alert
+func subject(alert *domain.Alert) string {
+	return fmt.Sprintf("alert %s: %s", alert.RuleName, alert.Message)
+}
@@ -0,0 +1,70 @@
+package notifier
+
+import (
+	"bufio"
+	"encoding/json"
+	"football-analytics/internal/domain"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func testAlert() *domain.Alert {
+	return &domain.Alert{
+		ID:       "a1",
+		RuleID:   "r1",
+		RuleName: "rating drop",
+		PlayerID: "p1",
+		Metric:   domain.AlertRollingRatingChange,
+		Value:    -25,
+		Message:  "rolling 5 match rating changed -25.0%",
+		State:    domain.AlertOpen,
+	}
+}
+
+func TestFileNotifier(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "alerts.jsonl")
+	notifier := NewFileNotifier(path)
+
+	assert.NoError(t, notifier.Notify(testAlert()))
+	assert.NoError(t, notifier.Notify(testAlert()))
+
+	file, err := os.Open(path)
+	if !assert.NoError(t, err) {
+		return
+	}
+	defer file.Close()
+
+	lines := 0
+	scanner := bufio.NewScanner(file)
+	for scanner.Scan() {
+		var alert domain.Alert
+		assert.NoError(t, json.Unmarshal(scanner.Bytes(), &alert))
+		assert.Equal(t, "a1", alert.ID)
+		lines++
+	}
+	assert.Equal(t, 2, lines)
+}
+
+func TestWebhookNotifier(t *testing.T) {
+	var received domain.Alert
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		assert.Equal(t, "application/json", r.Header.Get("Content-Type"))
+		assert.NoError(t, json.NewDecoder(r.Body).Decode(&received))
+	}))
+	defer server.Close()
+
+	assert.NoError(t, NewWebhookNotifier(server.URL, nil).Notify(testAlert()))
+	assert.Equal(t, "r1", received.RuleID)
+
+	failing := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.WriteHeader(http.StatusInternalServerError)
+	}))
+	defer failing.Close()
+
+	assert.Error(t, NewWebhookNotifier(failing.URL, nil).Notify(testAlert()))
+}
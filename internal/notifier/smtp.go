@@ -0,0 +1,47 @@
+package notifier
+
+import (
+	"fmt"
+	"football-analytics/internal/domain"
+	"net/smtp"
+	"strings"
+	"time"
+)
+
+type smtpNotifier struct {
+	addr string
+	from string
+	to   []string
+	auth smtp.Auth
+}
+
+// NewSMTPNotifier create a Notifier mailing every alert through the SMTP server at addr (host:port),
+// a nil auth sends without authentication as a local test server expects
+func NewSMTPNotifier(addr, from string, to []string, auth smtp.Auth) domain.Notifier {
+	return &smtpNotifier{addr: addr, from: from, to: to, auth: auth}
+}
+
+func (n *smtpNotifier) Name() string {
+	return "smtp"
+}
+
+func (n *smtpNotifier) Notify(alert *domain.Alert) error {
+	return smtp.SendMail(n.addr, n.auth, n.from, n.to, mailMessage(n.from, n.to, alert))
+}
+
+// mailMessage plain text mail of an alert
+func mailMessage(from string, to []string, alert *domain.Alert) []byte {
+	var b strings.Builder
+	fmt.Fprintf(&b, "From: %s\r\n", from)
+	fmt.Fprintf(&b, "To: %s\r\n", strings.Join(to, ", "))
+	fmt.Fprintf(&b, "Subject: %s\r\n", subject(alert))
+	fmt.Fprintf(&b, "Date: %s\r\n", alert.TriggeredAt.Format(time.RFC1123Z))
+	b.WriteString("Content-Type: text/plain; charset=utf-8\r\n\r\n")
+	fmt.Fprintf(&b, "%s\r\n\r\n", alert.Message)
+	fmt.Fprintf(&b, "Rule:      %s\r\n", alert.RuleName)
+	fmt.Fprintf(&b, "Player:    %s\r\n", alert.PlayerID)
+	fmt.Fprintf(&b, "Metric:    %s\r\n", alert.Metric)
+	fmt.Fprintf(&b, "Value:     %.2f\r\n", alert.Value)
+	fmt.Fprintf(&b, "Threshold: %.2f\r\n", alert.Threshold)
+	return []byte(b.String())
+}
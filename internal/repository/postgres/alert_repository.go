@@ -0,0 +1,265 @@
+package postgres
+
+import (
+	"database/sql"
+	"errors"
+	"fmt"
+	"football-analytics/internal/domain"
+	"strings"
+
+	"github.com/jmoiron/sqlx"
+	"github.com/lib/pq"
+)
+
+// alertRuleColumns columns of alert_rules in alertRuleDest order
+const alertRuleColumns = `id, name, metric, operator, threshold, window_matches, COALESCE(player_id::text, ''),
+	COALESCE(team_id::text, ''), notifiers, disabled, created_at, updated_at`
+
+// alertColumns columns of alerts in alertDest order
+const alertColumns = `id, rule_id, rule_name, player_id, COALESCE(match_id::text, ''), metric, value, threshold,
+	message, state, triggered_at, acknowledged_at`
+
+type alertRepository struct {
+	db *sqlx.DB
+}
+
+// NewAlertRepository create repository for alert rules and triggered alerts
+func NewAlertRepository(db *sqlx.DB) domain.AlertRepository {
+	return &alertRepository{
+		db: db,
+	}
+}
+
+// SaveRule add or replace the rule of the same id
+func (r *alertRepository) SaveRule(rule *domain.AlertRule) error {
+	query := `
+		INSERT INTO alert_rules (id, name, metric, operator, threshold, window_matches, player_id, team_id,
+			notifiers, disabled, created_at, updated_at)
+		VALUES ($1, $2, $3, $4, $5, $6, NULLIF($7, '')::uuid, NULLIF($8, '')::uuid, $9, $10, $11, $12)
+		ON CONFLICT (id) DO UPDATE
+		SET name = EXCLUDED.name, metric = EXCLUDED.metric, operator = EXCLUDED.operator,
+			threshold = EXCLUDED.threshold, window_matches = EXCLUDED.window_matches,
+			player_id = EXCLUDED.player_id, team_id = EXCLUDED.team_id, notifiers = EXCLUDED.notifiers,
+			disabled = EXCLUDED.disabled, updated_at = EXCLUDED.updated_at
+	`
+
+	_, err := r.db.Exec(
+		query,
+		rule.ID,
+		rule.Name,
+		rule.Metric,
+		rule.Operator,
+		rule.Threshold,
+		rule.Window,
+		rule.PlayerID,
+		rule.TeamID,
+		pq.Array(rule.Notifiers),
+		rule.Disabled,
+		rule.CreatedAt,
+		rule.UpdatedAt,
+	)
+
+	return err
+}
+
+func (r *alertRepository) GetRule(id string) (*domain.AlertRule, error) {
+	query := `SELECT ` + alertRuleColumns + ` FROM alert_rules WHERE id = $1`
+
+	var rule domain.AlertRule
+	err := r.db.QueryRowx(query, id).Scan(alertRuleDest(&rule)...)
+	if errors.Is(err, sql.ErrNoRows) {
+		return nil, domain.ErrNotFound
+	}
+	if err != nil {
+		return nil, err
+	}
+
+	return &rule, nil
+}
+
+func (r *alertRepository) ListRules() ([]*domain.AlertRule, error) {
+	query := `SELECT ` + alertRuleColumns + ` FROM alert_rules ORDER BY created_at`
+
+	rows, err := r.db.Queryx(query)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	var rules []*domain.AlertRule
+	for rows.Next() {
+		var rule domain.AlertRule
+		if err := rows.Scan(alertRuleDest(&rule)...); err != nil {
+			return nil, err
+		}
+		rules = append(rules, &rule)
+	}
+
+	return rules, rows.Err()
+}
+
+// DeleteRule delete the rule together with its alerts
+func (r *alertRepository) DeleteRule(id string) error {
+	result, err := r.db.Exec(`DELETE FROM alert_rules WHERE id = $1`, id)
+	if err != nil {
+		return err
+	}
+
+	affected, err := result.RowsAffected()
+	if err != nil {
+		return err
+	}
+	if affected == 0 {
+		return domain.ErrNotFound
+	}
+
+	return nil
+}
+
+// CreateAlert add a triggered alert, domain.ErrDuplicate if its rule already has an open alert for the player
+func (r *alertRepository) CreateAlert(alert *domain.Alert) error {
+	query := `
+		INSERT INTO alerts (id, rule_id, rule_name, player_id, match_id, metric, value, threshold, message, state,
+			triggered_at, acknowledged_at)
+		VALUES ($1, $2, $3, $4, NULLIF($5, '')::uuid, $6, $7, $8, $9, $10, $11, $12)
+	`
+
+	_, err := r.db.Exec(
+		query,
+		alert.ID,
+		alert.RuleID,
+		alert.RuleName,
+		alert.PlayerID,
+		alert.MatchID,
+		alert.Metric,
+		alert.Value,
+		alert.Threshold,
+		alert.Message,
+		alert.State,
+		alert.TriggeredAt,
+		alert.AcknowledgedAt,
+	)
+	var pqErr *pq.Error
+	if errors.As(err, &pqErr) && pqErr.Code == "23505" {
+		return domain.ErrDuplicate
+	}
+
+	return err
+}
+
+func (r *alertRepository) GetAlert(id string) (*domain.Alert, error) {
+	query := `SELECT ` + alertColumns + ` FROM alerts WHERE id = $1`
+
+	var alert domain.Alert
+	err := r.db.QueryRowx(query, id).Scan(alertDest(&alert)...)
+	if errors.Is(err, sql.ErrNoRows) {
+		return nil, domain.ErrNotFound
+	}
+	if err != nil {
+		return nil, err
+	}
+
+	return &alert, nil
+}
+
+// UpdateAlert update the handling state of the alert
+func (r *alertRepository) UpdateAlert(alert *domain.Alert) error {
+	result, err := r.db.Exec(
+		`UPDATE alerts SET state = $2, acknowledged_at = $3 WHERE id = $1`,
+		alert.ID,
+		alert.State,
+		alert.AcknowledgedAt,
+	)
+	if err != nil {
+		return err
+	}
+
+	affected, err := result.RowsAffected()
+	if err != nil {
+		return err
+	}
+	if affected == 0 {
+		return domain.ErrNotFound
+	}
+
+	return nil
+}
+
+func (r *alertRepository) ListAlerts(filter domain.AlertFilter) ([]*domain.Alert, error) {
+	var conditions []string
+	var args []interface{}
+	add := func(condition string, value interface{}) {
+		args = append(args, value)
+		conditions = append(conditions, fmt.Sprintf(condition, len(args)))
+	}
+
+	if filter.RuleID != "" {
+		add("rule_id = $%d", filter.RuleID)
+	}
+	if filter.PlayerID != "" {
+		add("player_id = $%d", filter.PlayerID)
+	}
+	if filter.State != "" {
+		add("state = $%d", filter.State)
+	}
+
+	where := ""
+	if len(conditions) > 0 {
+		where = "WHERE " + strings.Join(conditions, " AND ")
+	}
+
+	query := `SELECT ` + alertColumns + ` FROM alerts ` + where + ` ORDER BY triggered_at DESC`
+
+	rows, err := r.db.Queryx(query, args...)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	var alerts []*domain.Alert
+	for rows.Next() {
+		var alert domain.Alert
+		if err := rows.Scan(alertDest(&alert)...); err != nil {
+			return nil, err
+		}
+		alerts = append(alerts, &alert)
+	}
+
+	return alerts, rows.Err()
+}
+
+// alertRuleDest scan destinations of alertRuleColumns
+func alertRuleDest(rule *domain.AlertRule) []interface{} {
+	return []interface{}{
+		&rule.ID,
+		&rule.Name,
+		&rule.Metric,
+		&rule.Operator,
+		&rule.Threshold,
+		&rule.Window,
+		&rule.PlayerID,
+		&rule.TeamID,
+		pq.Array(&rule.Notifiers),
+		&rule.Disabled,
+		&rule.CreatedAt,
+		&rule.UpdatedAt,
+	}
+}
+
+// alertDest scan destinations of alertColumns
+func alertDest(alert *domain.Alert) []interface{} {
+	return []interface{}{
+		&alert.ID,
+		&alert.RuleID,
+		&alert.RuleName,
+		&alert.PlayerID,
+		&alert.MatchID,
+		&alert.Metric,
+		&alert.Value,
+		&alert.Threshold,
+		&alert.Message,
+		&alert.State,
+		&alert.TriggeredAt,
+		&alert.AcknowledgedAt,
+	}
+}
@@ -0,0 +1,180 @@
+package postgres
+
+import (
+	"database/sql"
+	"errors"
+	"football-analytics/internal/domain"
+	"time"
+
+	"github.com/jmoiron/sqlx"
+)
+
+type disciplineRepository struct {
+	db *sqlx.DB
+}
+
+// NewDisciplineRepository create repository for discipline rules and suspensions
+func NewDisciplineRepository(db *sqlx.DB) domain.DisciplineRepository {
+	return &disciplineRepository{
+		db: db,
+	}
+}
+
+// SaveRule add or replace the rule of the competition
+func (r *disciplineRepository) SaveRule(rule *domain.DisciplineRule) error {
+	query := `
+		INSERT INTO discipline_rules (id, competition, yellow_threshold, yellow_ban_matches, straight_red_ban_matches,
+			second_yellow_ban_matches, reset_round, created_at, updated_at)
+		VALUES ($1, $2, $3, $4, $5, $6, $7, $8, $9)
+		ON CONFLICT (competition) DO UPDATE
+		SET yellow_threshold = EXCLUDED.yellow_threshold, yellow_ban_matches = EXCLUDED.yellow_ban_matches,
+			straight_red_ban_matches = EXCLUDED.straight_red_ban_matches,
+			second_yellow_ban_matches = EXCLUDED.second_yellow_ban_matches,
+			reset_round = EXCLUDED.reset_round, updated_at = EXCLUDED.updated_at
+	`
+
+	_, err := r.db.Exec(
+		query,
+		rule.ID,
+		rule.Competition,
+		rule.YellowThreshold,
+		rule.YellowBanMatches,
+		rule.StraightRedBanMatches,
+		rule.SecondYellowBanMatches,
+		rule.ResetRound,
+		rule.CreatedAt,
+		rule.UpdatedAt,
+	)
+
+	return err
+}
+
+func (r *disciplineRepository) GetRule(competition string) (*domain.DisciplineRule, error) {
+	query := `
+		SELECT id, competition, yellow_threshold, yellow_ban_matches, straight_red_ban_matches,
+			second_yellow_ban_matches, reset_round, created_at, updated_at
+		FROM discipline_rules
+		WHERE competition = $1
+	`
+
+	var rule domain.DisciplineRule
+	err := r.db.QueryRowx(query, competition).Scan(
+		&rule.ID,
+		&rule.Competition,
+		&rule.YellowThreshold,
+		&rule.YellowBanMatches,
+		&rule.StraightRedBanMatches,
+		&rule.SecondYellowBanMatches,
+		&rule.ResetRound,
+		&rule.CreatedAt,
+		&rule.UpdatedAt,
+	)
+	if errors.Is(err, sql.ErrNoRows) {
+		return nil, domain.ErrNotFound
+	}
+	if err != nil {
+		return nil, err
+	}
+
+	return &rule, nil
+}
+
+// CreateSuspension add new suspension, a suspension already stored for the same trigger is kept
+func (r *disciplineRepository) CreateSuspension(suspension *domain.Suspension) error {
+	query := `
+		INSERT INTO suspensions (id, player_id, competition, trigger_match_id, trigger_date, reason, matches, served, created_at, updated_at)
+		VALUES ($1, $2, $3, $4, $5, $6, $7, $8, $9, $10)
+		ON CONFLICT (player_id, trigger_match_id, reason) DO NOTHING
+	`
+
+	_, err := r.db.Exec(
+		query,
+		suspension.ID,
+		suspension.PlayerID,
+		suspension.Competition,
+		suspension.TriggerMatchID,
+		suspension.TriggerDate,
+		suspension.Reason,
+		suspension.Matches,
+		suspension.Served,
+		suspension.CreatedAt,
+		suspension.UpdatedAt,
+	)
+
+	return err
+}
+
+func (r *disciplineRepository) ListSuspensions(playerID, competition string) ([]*domain.Suspension, error) {
+	query := `
+		SELECT id, player_id, competition, trigger_match_id, trigger_date, reason, matches, served, created_at, updated_at
+		FROM suspensions
+		WHERE player_id = $1 AND competition = $2
+		ORDER BY trigger_date
+	`
+
+	rows, err := r.db.Queryx(query, playerID, competition)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	var suspensions []*domain.Suspension
+	for rows.Next() {
+		var suspension domain.Suspension
+		err := rows.Scan(
+			&suspension.ID,
+			&suspension.PlayerID,
+			&suspension.Competition,
+			&suspension.TriggerMatchID,
+			&suspension.TriggerDate,
+			&suspension.Reason,
+			&suspension.Matches,
+			&suspension.Served,
+			&suspension.CreatedAt,
+			&suspension.UpdatedAt,
+		)
+		if err != nil {
+			return nil, err
+		}
+		suspensions = append(suspensions, &suspension)
+	}
+
+	return suspensions, rows.Err()
+}
+
+// RecordServed count the match towards the suspension, recording the same match twice has no effect
+func (r *disciplineRepository) RecordServed(suspensionID, matchID string) error {
+	tx, err := r.db.Beginx()
+	if err != nil {
+		return err
+	}
+	defer tx.Rollback()
+
+	result, err := tx.Exec(`
+		INSERT INTO suspension_served_matches (suspension_id, match_id)
+		VALUES ($1, $2)
+		ON CONFLICT DO NOTHING
+	`, suspensionID, matchID)
+	if err != nil {
+		return err
+	}
+
+	inserted, err := result.RowsAffected()
+	if err != nil {
+		return err
+	}
+	if inserted == 0 {
+		return nil
+	}
+
+	_, err = tx.Exec(`
+		UPDATE suspensions
+		SET served = served + 1, updated_at = $1
+		WHERE id = $2 AND served < matches
+	`, time.Now(), suspensionID)
+	if err != nil {
+		return err
+	}
+
+	return tx.Commit()
+}
@@ -0,0 +1,219 @@
+package postgres
+
+import (
+	"fmt"
+	"football-analytics/internal/domain"
+	"strings"
+
+	"github.com/jmoiron/sqlx"
+)
+
+// leaderboardSource is a table leaderboards aggregate, the raw player_match_stats or the season rollups
+type leaderboardSource struct {
+	from        string                     // FROM clause, the source is aliased s and joined with players p and teams t
+	team        string                     // team a row was played for, empty if the source does not know it
+	matches     string                     // aggregate of matches played
+	sum         func(column string) string // aggregate of a player_match_stats column
+	expressions map[string]string          // aggregate of each leaderboard metric
+}
+
+// rows recorded before stats had a team fall back to the player's current team
+var rawLeaderboardSource = newLeaderboardSource(
+	`player_match_stats s
+			JOIN matches m ON m.id = s.match_id
+			JOIN players p ON p.id = s.player_id
+			LEFT JOIN teams t ON t.id = COALESCE(s.team_id, p.team_id)`,
+	`COALESCE(s.team_id, p.team_id)`,
+	`COUNT(*)`,
+	func(column string) string { return fmt.Sprintf("SUM(s.%s)", column) },
+)
+
+var rollupLeaderboardSource = newLeaderboardSource(
+	`player_season_rollups s
+			JOIN players p ON p.id = s.player_id
+			LEFT JOIN teams t ON t.id = p.team_id`,
+	``,
+	`SUM(s.matches_played)`,
+	func(column string) string {
+		if column == "pass_accuracy" {
+			column = "pass_accuracy_sum"
+		}
+		return fmt.Sprintf("SUM(s.%s)", column)
+	},
+)
+
+// newLeaderboardSource build the metric aggregates of a source from its matches and column sums
+func newLeaderboardSource(from, team, matches string, sum func(column string) string) *leaderboardSource {
+	expressions := map[string]string{
+		"matches_played":       matches,
+		"minutes_played":       sum("minutes_played"),
+		"goals":                sum("goals"),
+		"assists":              sum("assists"),
+		"passes":               sum("passes"),
+		"shots":                sum("shots"),
+		"shots_on_target":      sum("shots_on_target"),
+		"tackles":              sum("tackles"),
+		"interceptions":        sum("interceptions"),
+		"fouls":                sum("fouls"),
+		"yellow_cards":         sum("yellow_cards"),
+		"red_cards":            sum("red_cards"),
+		"distance_covered":     sum("distance_covered"),
+		"pass_accuracy":        fmt.Sprintf(`%s::float / NULLIF(%s, 0)`, sum("pass_accuracy"), matches),
+		"shot_accuracy":        fmt.Sprintf(`%s::float / NULLIF(%s, 0)`, sum("shots_on_target"), sum("shots")),
+		"tackles_per_game":     fmt.Sprintf(`%s::float / NULLIF(%s, 0)`, sum("tackles"), matches),
+		"goals_per_minute":     fmt.Sprintf(`%s::float / NULLIF(%s, 0)`, sum("goals"), sum("minutes_played")),
+		"assists_per_minute":   fmt.Sprintf(`%s::float / NULLIF(%s, 0)`, sum("assists"), sum("minutes_played")),
+		"defensive_efficiency": fmt.Sprintf(`(%s + %s)::float / NULLIF(%s, 0)`, sum("tackles"), sum("interceptions"), matches),
+		"stamina":              fmt.Sprintf(`%s::float / NULLIF(%s, 0)`, sum("distance_covered"), matches),
+	}
+
+	// same formula as the overall rating of the analytics service
+	expressions["overall_rating"] = fmt.Sprintf(`(
+		COALESCE(%s, 0) * 100 +
+		COALESCE(%s, 0) * 50 +
+		COALESCE(%s, 0) * 0.3 +
+		COALESCE(%s, 0) * 0.2 +
+		COALESCE(%s, 0) * 0.1 +
+		COALESCE(%s, 0) * 0.1) / 6`,
+		expressions["goals_per_minute"],
+		expressions["assists_per_minute"],
+		expressions["pass_accuracy"],
+		expressions["shot_accuracy"],
+		expressions["defensive_efficiency"],
+		expressions["stamina"],
+	)
+
+	return &leaderboardSource{from: from, team: team, matches: matches, sum: sum, expressions: expressions}
+}
+
+type leaderboardRepository struct {
+	db *sqlx.DB
+}
+
+// NewLeaderboardRepository create repository for metric leaderboards
+func NewLeaderboardRepository(db *sqlx.DB) domain.LeaderboardRepository {
+	return &leaderboardRepository{
+		db: db,
+	}
+}
+
+// Top rank players by the query metric in a single query, players tied with the last rank are included,
+// whole seasons and all time leaderboards are read from the season rollups unless they are filtered by
+// team or league, the rollups do not know which team a player played for
+func (r *leaderboardRepository) Top(query domain.LeaderboardQuery) ([]*domain.LeaderboardEntry, error) {
+	source := rawLeaderboardSource
+	if query.TeamID == "" && query.League == "" && (query.Season != "" || (query.StartDate.IsZero() && query.EndDate.IsZero())) {
+		source = rollupLeaderboardSource
+	}
+
+	expression, ok := source.expressions[query.Metric]
+	if !ok {
+		return nil, fmt.Errorf("unknown leaderboard metric %q", query.Metric)
+	}
+	if query.Per90 && domain.LeaderboardMetrics[query.Metric] {
+		expression = fmt.Sprintf(`(%s)::float * 90 / NULLIF(%s, 0)`, expression, source.sum("minutes_played"))
+	}
+
+	var conditions []string
+	var args []interface{}
+	arg := func(value interface{}) string {
+		args = append(args, value)
+		return fmt.Sprintf("$%d", len(args))
+	}
+
+	if query.Competition != "" {
+		if source == rollupLeaderboardSource {
+			conditions = append(conditions, "s.competition = "+arg(query.Competition))
+		} else {
+			conditions = append(conditions, "m.competition = "+arg(query.Competition))
+		}
+	}
+	if query.League != "" {
+		conditions = append(conditions, "t.league = "+arg(query.League))
+	}
+	if source == rollupLeaderboardSource {
+		if query.Season != "" {
+			conditions = append(conditions, "s.season = "+arg(query.Season))
+		}
+	} else {
+		if !query.StartDate.IsZero() {
+			conditions = append(conditions, "m.date >= "+arg(query.StartDate))
+		}
+		if !query.EndDate.IsZero() {
+			conditions = append(conditions, "m.date < "+arg(query.EndDate))
+		}
+	}
+	if query.TeamID != "" {
+		conditions = append(conditions, source.team+" = "+arg(query.TeamID))
+	}
+	if query.Position != "" {
+		conditions = append(conditions, "p.position = "+arg(query.Position))
+	}
+	if query.MinAge > 0 || query.MaxAge > 0 {
+		// age at the end of the range, or as of the query time for open ranges
+		ageAt := query.EndDate
+		if ageAt.IsZero() {
+			ageAt = query.AsOf
+		}
+		age := fmt.Sprintf("DATE_PART('year', AGE(%s, p.birthday))", arg(ageAt))
+		if query.MinAge > 0 {
+			conditions = append(conditions, age+" >= "+arg(query.MinAge))
+		}
+		if query.MaxAge > 0 {
+			conditions = append(conditions, age+" <= "+arg(query.MaxAge))
+		}
+	}
+
+	where := ""
+	if len(conditions) > 0 {
+		where = "WHERE " + strings.Join(conditions, " AND ")
+	}
+
+	sql := fmt.Sprintf(`
+		WITH totals AS (
+			SELECT s.player_id, %s AS matches_played, %s AS minutes_played, %s AS value
+			FROM %s
+			%s
+			GROUP BY s.player_id
+			HAVING %s >= %s
+		), ranked AS (
+			SELECT RANK() OVER (ORDER BY totals.value DESC) AS rank, totals.*
+			FROM totals
+			WHERE totals.value IS NOT NULL
+		)
+		SELECT ranked.rank, p.id, p.name, p.position, COALESCE(p.team_id::text, ''), ranked.value,
+			ranked.matches_played, ranked.minutes_played
+		FROM ranked
+		JOIN players p ON p.id = ranked.player_id
+		WHERE ranked.rank <= %s
+		ORDER BY ranked.rank, p.name
+	`, source.matches, source.sum("minutes_played"), expression, source.from, where,
+		source.sum("minutes_played"), arg(query.MinMinutes), arg(query.Limit))
+
+	rows, err := r.db.Queryx(sql, args...)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	var entries []*domain.LeaderboardEntry
+	for rows.Next() {
+		var entry domain.LeaderboardEntry
+		err := rows.Scan(
+			&entry.Rank,
+			&entry.PlayerID,
+			&entry.Name,
+			&entry.Position,
+			&entry.TeamID,
+			&entry.Value,
+			&entry.MatchesPlayed,
+			&entry.MinutesPlayed,
+		)
+		if err != nil {
+			return nil, err
+		}
+		entries = append(entries, &entry)
+	}
+
+	return entries, rows.Err()
+}
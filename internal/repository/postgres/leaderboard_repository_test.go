@@ -0,0 +1,151 @@
+package postgres
+
+import (
+	"football-analytics/internal/domain"
+	"testing"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/jmoiron/sqlx"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/suite"
+)
+
+type LeaderboardRepositoryTestSuite struct {
+	suite.Suite
+	db         *sqlx.DB
+	repository domain.LeaderboardRepository
+	teams      map[string]string
+	players    map[string]string
+}
+
+// SetupSuite September 2024 goals: ann 3 and bea 3 for the first team, cid 2 for the second team,
+// dan 1 for the first team before moving to the second and eve 1 in a row recorded without a team,
+// each team plays in a league named after it
+func (s *LeaderboardRepositoryTestSuite) SetupSuite() {
+	db, err := NewConnection(testDatabaseURL)
+	assert.NoError(s.T(), err)
+	s.db = db
+	s.repository = NewLeaderboardRepository(s.db)
+
+	s.teams = make(map[string]string)
+	for _, name := range []string{"first", "second"} {
+		s.teams[name] = uuid.New().String()
+		_, err := s.db.Exec(`INSERT INTO teams (id, name, country, league) VALUES ($1, $2, $3, $4)`,
+			s.teams[name], name, "Test Country", name+" league")
+		assert.NoError(s.T(), err)
+	}
+
+	matchID := uuid.New().String()
+	_, err = s.db.Exec(`
+		INSERT INTO matches (id, home_team_id, away_team_id, date, competition, status)
+		VALUES ($1, $2, $3, $4, $5, $6)
+	`, matchID, s.teams["first"], s.teams["second"], time.Date(2024, 9, 14, 15, 0, 0, 0, time.UTC), "League", "completed")
+	assert.NoError(s.T(), err)
+
+	s.players = make(map[string]string)
+	for _, row := range []struct {
+		name, team, statsTeam string
+		goals                 int
+	}{
+		{"ann", "first", "first", 3},
+		{"bea", "first", "first", 3},
+		{"cid", "second", "second", 2},
+		{"dan", "second", "first", 1},
+		{"eve", "first", "", 1},
+	} {
+		s.players[row.name] = uuid.New().String()
+		_, err := s.db.Exec(`INSERT INTO players (id, name, position, team_id) VALUES ($1, $2, $3, $4)`,
+			s.players[row.name], row.name, "Forward", s.teams[row.team])
+		assert.NoError(s.T(), err)
+
+		var statsTeam interface{}
+		if row.statsTeam != "" {
+			statsTeam = s.teams[row.statsTeam]
+		}
+		_, err = s.db.Exec(`
+			INSERT INTO player_match_stats (id, player_id, match_id, team_id, minutes_played, goals)
+			VALUES ($1, $2, $3, $4, $5, $6)
+		`, uuid.New().String(), s.players[row.name], matchID, statsTeam, 90, row.goals)
+		assert.NoError(s.T(), err)
+	}
+}
+
+func (s *LeaderboardRepositoryTestSuite) TearDownSuite() {
+	for _, table := range []string{"player_match_stats", "matches", "players", "teams"} {
+		_, err := s.db.Exec("DELETE FROM " + table)
+		assert.NoError(s.T(), err)
+	}
+	s.db.Close()
+}
+
+func (s *LeaderboardRepositoryTestSuite) top(limit int, teamID string) ([]string, []int) {
+	entries, err := s.repository.Top(domain.LeaderboardQuery{
+		Metric:    "goals",
+		StartDate: time.Date(2024, 9, 1, 0, 0, 0, 0, time.UTC),
+		EndDate:   time.Date(2024, 10, 1, 0, 0, 0, 0, time.UTC),
+		TeamID:    teamID,
+		Limit:     limit,
+	})
+	assert.NoError(s.T(), err)
+
+	var names []string
+	var ranks []int
+	for _, entry := range entries {
+		names = append(names, entry.Name)
+		ranks = append(ranks, entry.Rank)
+	}
+	return names, ranks
+}
+
+func (s *LeaderboardRepositoryTestSuite) TestTiesShareRank() {
+	names, ranks := s.top(3, "")
+	assert.Equal(s.T(), []string{"ann", "bea", "cid"}, names)
+	// RANK leaves a gap after a tie
+	assert.Equal(s.T(), []int{1, 1, 3}, ranks)
+
+	names, _ = s.top(1, "")
+	assert.Equal(s.T(), []string{"ann", "bea"}, names)
+}
+
+func (s *LeaderboardRepositoryTestSuite) TestTiesWithLastRankIncluded() {
+	names, ranks := s.top(4, "")
+	assert.Equal(s.T(), []string{"ann", "bea", "cid", "dan", "eve"}, names)
+	assert.Equal(s.T(), []int{1, 1, 3, 4, 4}, ranks)
+}
+
+func (s *LeaderboardRepositoryTestSuite) TestTeamOfStatsRow() {
+	// dan scored for the first team, eve's row falls back to her current team
+	names, _ := s.top(10, s.teams["first"])
+	assert.Equal(s.T(), []string{"ann", "bea", "dan", "eve"}, names)
+
+	names, _ = s.top(10, s.teams["second"])
+	assert.Equal(s.T(), []string{"cid"}, names)
+}
+
+func (s *LeaderboardRepositoryTestSuite) TestLeagueOfStatsRow() {
+	// all time league leaderboards count dan's goal for the league he scored it in
+	for league, want := range map[string][]string{
+		"first league":  {"ann", "bea", "dan", "eve"},
+		"second league": {"cid"},
+	} {
+		entries, err := s.repository.Top(domain.LeaderboardQuery{Metric: "goals", League: league, Limit: 10})
+		assert.NoError(s.T(), err)
+
+		var names []string
+		for _, entry := range entries {
+			names = append(names, entry.Name)
+		}
+		assert.Equal(s.T(), want, names, league)
+	}
+}
+
+func TestLeaderboardRepositorySuite(t *testing.T) {
+	db, err := NewConnection(testDatabaseURL)
+	if err != nil {
+		t.Skipf("database not available: %v", err)
+	}
+	db.Close()
+
+	suite.Run(t, new(LeaderboardRepositoryTestSuite))
+}
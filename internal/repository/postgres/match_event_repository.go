@@ -0,0 +1,124 @@
+package postgres
+
+import (
+	"database/sql"
+	"errors"
+	"football-analytics/internal/domain"
+
+	"github.com/jmoiron/sqlx"
+	"github.com/lib/pq"
+)
+
+// matchEventColumns columns of match_events in matchEventDest order
+const matchEventColumns = `id, match_id, team_id, type, minute, player_id, COALESCE(related_player_id::text, ''),
+	COALESCE(stats_id::text, ''), created_at, updated_at`
+
+type matchEventRepository struct {
+	db *sqlx.DB
+}
+
+// NewMatchEventRepository create repository for timed match events
+func NewMatchEventRepository(db *sqlx.DB) domain.MatchEventRepository {
+	return &matchEventRepository{
+		db: db,
+	}
+}
+
+func (r *matchEventRepository) Create(event *domain.MatchEvent) error {
+	query := `
+		INSERT INTO match_events (id, match_id, team_id, type, minute, player_id, related_player_id, stats_id,
+			created_at, updated_at)
+		VALUES ($1, $2, $3, $4, $5, $6, NULLIF($7, '')::uuid, NULLIF($8, '')::uuid, $9, $10)
+	`
+
+	_, err := r.db.Exec(
+		query,
+		event.ID,
+		event.MatchID,
+		event.TeamID,
+		event.Type,
+		event.Minute,
+		event.PlayerID,
+		event.RelatedPlayerID,
+		event.StatsID,
+		event.CreatedAt,
+		event.UpdatedAt,
+	)
+
+	return err
+}
+
+func (r *matchEventRepository) GetByID(id string) (*domain.MatchEvent, error) {
+	query := `SELECT ` + matchEventColumns + ` FROM match_events WHERE id = $1`
+
+	var event domain.MatchEvent
+	err := r.db.QueryRowx(query, id).Scan(matchEventDest(&event)...)
+	if errors.Is(err, sql.ErrNoRows) {
+		return nil, domain.ErrNotFound
+	}
+	if err != nil {
+		return nil, err
+	}
+
+	return &event, nil
+}
+
+func (r *matchEventRepository) Delete(id string) error {
+	result, err := r.db.Exec(`DELETE FROM match_events WHERE id = $1`, id)
+	if err != nil {
+		return err
+	}
+
+	affected, err := result.RowsAffected()
+	if err != nil {
+		return err
+	}
+	if affected == 0 {
+		return domain.ErrNotFound
+	}
+
+	return nil
+}
+
+// ListByMatchIDs events of the matches ordered by match and minute
+func (r *matchEventRepository) ListByMatchIDs(matchIDs []string) ([]*domain.MatchEvent, error) {
+	query := `
+		SELECT ` + matchEventColumns + `
+		FROM match_events
+		WHERE match_id = ANY($1)
+		ORDER BY match_id, minute, created_at
+	`
+
+	rows, err := r.db.Queryx(query, pq.Array(matchIDs))
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	var events []*domain.MatchEvent
+	for rows.Next() {
+		var event domain.MatchEvent
+		if err := rows.Scan(matchEventDest(&event)...); err != nil {
+			return nil, err
+		}
+		events = append(events, &event)
+	}
+
+	return events, rows.Err()
+}
+
+// matchEventDest scan destinations of matchEventColumns
+func matchEventDest(event *domain.MatchEvent) []interface{} {
+	return []interface{}{
+		&event.ID,
+		&event.MatchID,
+		&event.TeamID,
+		&event.Type,
+		&event.Minute,
+		&event.PlayerID,
+		&event.RelatedPlayerID,
+		&event.StatsID,
+		&event.CreatedAt,
+		&event.UpdatedAt,
+	}
+}
@@ -0,0 +1,99 @@
+package postgres
+
+import (
+	"database/sql"
+	"encoding/json"
+	"errors"
+	"football-analytics/internal/domain"
+
+	"github.com/jmoiron/sqlx"
+)
+
+type matchModelRepository struct {
+	db *sqlx.DB
+}
+
+// NewMatchModelRepository create repository for MatchModel data
+func NewMatchModelRepository(db *sqlx.DB) domain.MatchModelRepository {
+	return &matchModelRepository{
+		db: db,
+	}
+}
+
+// Save add new MatchModel version
+func (r *matchModelRepository) Save(model *domain.MatchModel) error {
+	strengths, err := json.Marshal(model.Strengths)
+	if err != nil {
+		return err
+	}
+
+	query := `
+		INSERT INTO match_models (id, version, home_advantage, rho, decay_rate, strengths, trained_until, matches, created_at)
+		VALUES ($1, $2, $3, $4, $5, $6, $7, $8, $9)
+	`
+
+	_, err = r.db.Exec(
+		query,
+		model.ID,
+		model.Version,
+		model.HomeAdvantage,
+		model.Rho,
+		model.DecayRate,
+		strengths,
+		model.TrainedUntil,
+		model.Matches,
+		model.CreatedAt,
+	)
+
+	return err
+}
+
+func (r *matchModelRepository) GetLatest() (*domain.MatchModel, error) {
+	query := `
+		SELECT id, version, home_advantage, rho, decay_rate, strengths, trained_until, matches, created_at
+		FROM match_models
+		ORDER BY created_at DESC
+		LIMIT 1
+	`
+
+	return r.get(query)
+}
+
+func (r *matchModelRepository) GetByVersion(version string) (*domain.MatchModel, error) {
+	query := `
+		SELECT id, version, home_advantage, rho, decay_rate, strengths, trained_until, matches, created_at
+		FROM match_models
+		WHERE version = $1
+	`
+
+	return r.get(query, version)
+}
+
+func (r *matchModelRepository) get(query string, args ...interface{}) (*domain.MatchModel, error) {
+	var model domain.MatchModel
+	var strengths []byte
+
+	err := r.db.QueryRowx(query, args...).Scan(
+		&model.ID,
+		&model.Version,
+		&model.HomeAdvantage,
+		&model.Rho,
+		&model.DecayRate,
+		&strengths,
+		&model.TrainedUntil,
+		&model.Matches,
+		&model.CreatedAt,
+	)
+	if errors.Is(err, sql.ErrNoRows) {
+		return nil, domain.ErrNotFound
+	}
+	if err != nil {
+		return nil, err
+	}
+
+	if err := json.Unmarshal(strengths, &model.Strengths); err != nil {
+		return nil, err
+	}
+
+	return &model, nil
+}
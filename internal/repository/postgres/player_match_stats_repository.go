@@ -0,0 +1,321 @@
+package postgres
+
+import (
+	"database/sql"
+	"errors"
+	"football-analytics/internal/domain"
+	"time"
+
+	"github.com/jmoiron/sqlx"
+	"github.com/lib/pq"
+)
+
+// statsColumns columns of player_match_stats in scanStats order
+const statsColumns = `s.id, s.player_id, s.match_id, COALESCE(s.team_id::text, ''), s.minutes_played, s.goals, s.assists,
+	s.passes, s.pass_accuracy, s.shots, s.shots_on_target, s.tackles, s.interceptions, s.fouls,
+	s.yellow_cards, s.red_cards, s.distance_covered, s.created_at, s.updated_at`
+
+// matchColumns columns of matches in scanMatch order
+const matchColumns = `m.id, m.home_team_id, m.away_team_id, m.date, COALESCE(m.venue, ''), COALESCE(m.competition, ''),
+	COALESCE(m.season_id::text, ''), COALESCE(m.round, 0), m.home_score, m.away_score, m.home_possession, m.away_possession,
+	COALESCE(m.home_coach, ''), COALESCE(m.away_coach, ''), m.status, m.created_at, m.updated_at`
+
+type playerMatchStatsRepository struct {
+	db *sqlx.DB
+}
+
+// NewPlayerMatchStatsRepository create repository for PlayerMatchStats data
+func NewPlayerMatchStatsRepository(db *sqlx.DB) domain.PlayerMatchStatsRepository {
+	return &playerMatchStatsRepository{
+		db: db,
+	}
+}
+
+// Create add new PlayerMatchStats data
+func (r *playerMatchStatsRepository) Create(stats *domain.PlayerMatchStats) error {
+	query := `
+		INSERT INTO player_match_stats (id, player_id, match_id, team_id, minutes_played, goals, assists, passes,
+			pass_accuracy, shots, shots_on_target, tackles, interceptions, fouls, yellow_cards, red_cards,
+			distance_covered, created_at, updated_at)
+		VALUES ($1, $2, $3, NULLIF($4, '')::uuid, $5, $6, $7, $8, $9, $10, $11, $12, $13, $14, $15, $16, $17, $18, $19)
+	`
+
+	_, err := r.db.Exec(query, append([]interface{}{stats.ID}, statsValues(stats)...)...)
+	return err
+}
+
+func (r *playerMatchStatsRepository) GetByID(id string) (*domain.PlayerMatchStats, error) {
+	query := `SELECT ` + statsColumns + ` FROM player_match_stats s WHERE s.id = $1`
+
+	stats, err := scanStats(r.db.QueryRowx(query, id))
+	if errors.Is(err, sql.ErrNoRows) {
+		return nil, domain.ErrNotFound
+	}
+	if err != nil {
+		return nil, err
+	}
+
+	return stats, nil
+}
+
+// Update update PlayerMatchStats data, domain.ErrNotFound if there is no row of its id
+func (r *playerMatchStatsRepository) Update(stats *domain.PlayerMatchStats) error {
+	query := `
+		UPDATE player_match_stats
+		SET player_id = $2, match_id = $3, team_id = NULLIF($4, '')::uuid, minutes_played = $5, goals = $6,
+			assists = $7, passes = $8, pass_accuracy = $9, shots = $10, shots_on_target = $11, tackles = $12,
+			interceptions = $13, fouls = $14, yellow_cards = $15, red_cards = $16, distance_covered = $17,
+			created_at = $18, updated_at = $19
+		WHERE id = $1
+	`
+
+	result, err := r.db.Exec(query, append([]interface{}{stats.ID}, statsValues(stats)...)...)
+	if err != nil {
+		return err
+	}
+
+	affected, err := result.RowsAffected()
+	if err != nil {
+		return err
+	}
+	if affected == 0 {
+		return domain.ErrNotFound
+	}
+
+	return nil
+}
+
+// Delete delete PlayerMatchStats data, domain.ErrNotFound if there is no row of the id
+func (r *playerMatchStatsRepository) Delete(id string) error {
+	result, err := r.db.Exec(`DELETE FROM player_match_stats WHERE id = $1`, id)
+	if err != nil {
+		return err
+	}
+
+	affected, err := result.RowsAffected()
+	if err != nil {
+		return err
+	}
+	if affected == 0 {
+		return domain.ErrNotFound
+	}
+
+	return nil
+}
+
+func (r *playerMatchStatsRepository) ListByPlayerID(playerID string) ([]*domain.PlayerMatchStats, error) {
+	query := `
+		SELECT ` + statsColumns + `
+		FROM player_match_stats s
+		JOIN matches m ON m.id = s.match_id
+		WHERE s.player_id = $1
+		ORDER BY m.date
+	`
+
+	return r.list(query, playerID)
+}
+
+func (r *playerMatchStatsRepository) ListByMatchID(matchID string) ([]*domain.PlayerMatchStats, error) {
+	query := `SELECT ` + statsColumns + ` FROM player_match_stats s WHERE s.match_id = $1`
+
+	return r.list(query, matchID)
+}
+
+// ListByMatchIDs get stats of a set of matches in one query, ordered by match
+func (r *playerMatchStatsRepository) ListByMatchIDs(matchIDs []string) ([]*domain.PlayerMatchStats, error) {
+	if len(matchIDs) == 0 {
+		return nil, nil
+	}
+
+	query := `SELECT ` + statsColumns + ` FROM player_match_stats s WHERE s.match_id = ANY($1) ORDER BY s.match_id`
+
+	return r.list(query, pq.Array(matchIDs))
+}
+
+// GetPlayerSeasonStats sum the rollups of a player in the seasons labelled season (like "2024/25" or "2025")
+// over every competition
+func (r *playerMatchStatsRepository) GetPlayerSeasonStats(playerID string, season string) (*domain.PlayerSeasonStats, error) {
+	query := `
+		SELECT COALESCE(SUM(matches_played), 0), COALESCE(SUM(minutes_played), 0), COALESCE(SUM(goals), 0),
+			COALESCE(SUM(assists), 0), COALESCE(SUM(pass_accuracy_sum) / NULLIF(SUM(matches_played), 0), 0),
+			COALESCE(SUM(shots_on_target), 0), COALESCE(SUM(tackles)::float / NULLIF(SUM(matches_played), 0), 0),
+			COALESCE(SUM(yellow_cards), 0), COALESCE(SUM(red_cards), 0), COALESCE(SUM(distance_covered), 0)
+		FROM player_season_rollups
+		WHERE player_id = $1 AND season = $2
+	`
+
+	stats := domain.PlayerSeasonStats{PlayerID: playerID, Season: season}
+	err := r.db.QueryRowx(query, playerID, season).Scan(
+		&stats.MatchesPlayed,
+		&stats.MinutesPlayed,
+		&stats.Goals,
+		&stats.Assists,
+		&stats.PassAccuracy,
+		&stats.ShotsOnTarget,
+		&stats.TacklesPerGame,
+		&stats.YellowCards,
+		&stats.RedCards,
+		&stats.DistanceCovered,
+	)
+	if err != nil {
+		return nil, err
+	}
+
+	return &stats, nil
+}
+
+// ListByPlayerAndDateRange get stats of a player joined with their match for matches between start and end, ordered by match date
+func (r *playerMatchStatsRepository) ListByPlayerAndDateRange(playerID string, start, end time.Time) ([]*domain.PlayerAppearance, error) {
+	query := `
+		SELECT ` + statsColumns + `, ` + matchColumns + `
+		FROM player_match_stats s
+		JOIN matches m ON m.id = s.match_id
+		WHERE s.player_id = $1 AND m.date >= $2 AND m.date <= $3
+		ORDER BY m.date
+	`
+
+	return r.listAppearances(query, playerID, start, end)
+}
+
+// ListByPlayersAndDateRange get stats of a set of players joined with their match for matches between start and end,
+// ordered by match date
+func (r *playerMatchStatsRepository) ListByPlayersAndDateRange(playerIDs []string, start, end time.Time) ([]*domain.PlayerAppearance, error) {
+	if len(playerIDs) == 0 {
+		return nil, nil
+	}
+
+	query := `
+		SELECT ` + statsColumns + `, ` + matchColumns + `
+		FROM player_match_stats s
+		JOIN matches m ON m.id = s.match_id
+		WHERE s.player_id = ANY($1) AND m.date >= $2 AND m.date <= $3
+		ORDER BY m.date, s.player_id
+	`
+
+	return r.listAppearances(query, pq.Array(playerIDs), start, end)
+}
+
+func (r *playerMatchStatsRepository) list(query string, args ...interface{}) ([]*domain.PlayerMatchStats, error) {
+	rows, err := r.db.Queryx(query, args...)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	var result []*domain.PlayerMatchStats
+	for rows.Next() {
+		stats, err := scanStats(rows)
+		if err != nil {
+			return nil, err
+		}
+		result = append(result, stats)
+	}
+
+	return result, rows.Err()
+}
+
+func (r *playerMatchStatsRepository) listAppearances(query string, args ...interface{}) ([]*domain.PlayerAppearance, error) {
+	rows, err := r.db.Queryx(query, args...)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	var result []*domain.PlayerAppearance
+	for rows.Next() {
+		var stats domain.PlayerMatchStats
+		var match domain.Match
+		err := rows.Scan(append(statsDest(&stats), matchDest(&match)...)...)
+		if err != nil {
+			return nil, err
+		}
+		result = append(result, &domain.PlayerAppearance{Match: &match, Stats: &stats})
+	}
+
+	return result, rows.Err()
+}
+
+// scanner is a single row of sqlx query results
+type scanner interface {
+	Scan(dest ...interface{}) error
+}
+
+func scanStats(row scanner) (*domain.PlayerMatchStats, error) {
+	var stats domain.PlayerMatchStats
+	if err := row.Scan(statsDest(&stats)...); err != nil {
+		return nil, err
+	}
+	return &stats, nil
+}
+
+// statsValues values of the stats columns after the ID, in insert order
+func statsValues(stats *domain.PlayerMatchStats) []interface{} {
+	return []interface{}{
+		stats.PlayerID,
+		stats.MatchID,
+		stats.TeamID,
+		stats.MinutesPlayed,
+		stats.Goals,
+		stats.Assists,
+		stats.Passes,
+		stats.PassAccuracy,
+		stats.Shots,
+		stats.ShotsOnTarget,
+		stats.Tackles,
+		stats.Interceptions,
+		stats.Fouls,
+		stats.YellowCards,
+		stats.RedCards,
+		stats.DistanceCovered,
+		stats.CreatedAt,
+		stats.UpdatedAt,
+	}
+}
+
+// statsDest scan destinations of statsColumns
+func statsDest(stats *domain.PlayerMatchStats) []interface{} {
+	return []interface{}{
+		&stats.ID,
+		&stats.PlayerID,
+		&stats.MatchID,
+		&stats.TeamID,
+		&stats.MinutesPlayed,
+		&stats.Goals,
+		&stats.Assists,
+		&stats.Passes,
+		&stats.PassAccuracy,
+		&stats.Shots,
+		&stats.ShotsOnTarget,
+		&stats.Tackles,
+		&stats.Interceptions,
+		&stats.Fouls,
+		&stats.YellowCards,
+		&stats.RedCards,
+		&stats.DistanceCovered,
+		&stats.CreatedAt,
+		&stats.UpdatedAt,
+	}
+}
+
+// matchDest scan destinations of matchColumns
+func matchDest(match *domain.Match) []interface{} {
+	return []interface{}{
+		&match.ID,
+		&match.HomeTeamID,
+		&match.AwayTeamID,
+		&match.Date,
+		&match.Venue,
+		&match.Competition,
+		&match.SeasonID,
+		&match.Round,
+		&match.HomeScore,
+		&match.AwayScore,
+		&match.HomePossession,
+		&match.AwayPossession,
+		&match.HomeCoach,
+		&match.AwayCoach,
+		&match.Status,
+		&match.CreatedAt,
+		&match.UpdatedAt,
+	}
+}
@@ -0,0 +1,181 @@
+package postgres
+
+import (
+	"football-analytics/internal/domain"
+	"testing"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/jmoiron/sqlx"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/suite"
+)
+
+type PlayerMatchStatsRepositoryTestSuite struct {
+	suite.Suite
+	db         *sqlx.DB
+	repository domain.PlayerMatchStatsRepository
+	playerID   string
+	matchID    string
+}
+
+func (s *PlayerMatchStatsRepositoryTestSuite) SetupSuite() {
+	db, err := NewConnection(testDatabaseURL)
+	assert.NoError(s.T(), err)
+	s.db = db
+	s.repository = NewPlayerMatchStatsRepository(s.db)
+
+	teamID := uuid.New().String()
+	_, err = s.db.Exec(`INSERT INTO teams (id, name, country, league) VALUES ($1, $2, $3, $4)`,
+		teamID, "Test Team", "Test Country", "Test League")
+	assert.NoError(s.T(), err)
+
+	s.playerID = uuid.New().String()
+	_, err = s.db.Exec(`INSERT INTO players (id, name, position, team_id) VALUES ($1, $2, $3, $4)`,
+		s.playerID, "Test Player", "Forward", teamID)
+	assert.NoError(s.T(), err)
+
+	s.matchID = uuid.New().String()
+	_, err = s.db.Exec(`
+		INSERT INTO matches (id, home_team_id, away_team_id, date, competition, status)
+		VALUES ($1, $2, $3, $4, $5, $6)
+	`, s.matchID, teamID, teamID, time.Date(2024, 9, 14, 15, 0, 0, 0, time.UTC), "League", "completed")
+	assert.NoError(s.T(), err)
+}
+
+func (s *PlayerMatchStatsRepositoryTestSuite) TearDownSuite() {
+	for _, table := range []string{"player_match_stats", "matches", "players", "teams"} {
+		_, err := s.db.Exec("DELETE FROM " + table)
+		assert.NoError(s.T(), err)
+	}
+	s.db.Close()
+}
+
+func (s *PlayerMatchStatsRepositoryTestSuite) TestUpdateAndDelete() {
+	stats := &domain.PlayerMatchStats{
+		ID:            uuid.New().String(),
+		PlayerID:      s.playerID,
+		MatchID:       s.matchID,
+		MinutesPlayed: 90,
+		Goals:         1,
+		CreatedAt:     time.Now(),
+		UpdatedAt:     time.Now(),
+	}
+	assert.NoError(s.T(), s.repository.Create(stats))
+
+	stats.Goals = 2
+	assert.NoError(s.T(), s.repository.Update(stats))
+	result, err := s.repository.GetByID(stats.ID)
+	assert.NoError(s.T(), err)
+	assert.Equal(s.T(), 2, result.Goals)
+
+	assert.NoError(s.T(), s.repository.Delete(stats.ID))
+	_, err = s.repository.GetByID(stats.ID)
+	assert.ErrorIs(s.T(), err, domain.ErrNotFound)
+}
+
+func (s *PlayerMatchStatsRepositoryTestSuite) TestUpdateAndDeleteMissing() {
+	missing := &domain.PlayerMatchStats{ID: uuid.New().String(), PlayerID: s.playerID, MatchID: s.matchID}
+
+	assert.ErrorIs(s.T(), s.repository.Update(missing), domain.ErrNotFound)
+	assert.ErrorIs(s.T(), s.repository.Delete(missing.ID), domain.ErrNotFound)
+}
+
+func TestPlayerMatchStatsRepositorySuite(t *testing.T) {
+	db, err := NewConnection(testDatabaseURL)
+	if err != nil {
+		t.Skipf("database not available: %v", err)
+	}
+	db.Close()
+
+	suite.Run(t, new(PlayerMatchStatsRepositoryTestSuite))
+}
+
+// seedBenchmarkStats insert 20 teams of 25 players playing every other team at home once a season over
+// 10 seasons, 14 players of each team appear in every match, and return a player of the dataset
+func seedBenchmarkStats(db *sqlx.DB) (string, error) {
+	for _, statement := range []string{
+		`INSERT INTO teams (id, name, country, league)
+		SELECT uuid_generate_v4(), 'bench ' || n, 'Test Country', 'Test League'
+		FROM generate_series(1, 20) n`,
+		`INSERT INTO players (id, name, position, team_id, number)
+		SELECT uuid_generate_v4(), t.name || ' player ' || n, 'Midfielder', t.id, n
+		FROM teams t CROSS JOIN generate_series(1, 25) n
+		WHERE t.name LIKE 'bench %'`,
+		`INSERT INTO matches (id, home_team_id, away_team_id, date, competition, status)
+		SELECT uuid_generate_v4(), h.id, a.id,
+			DATE '2014-08-01' + make_interval(years => s, days => (row_number() OVER (PARTITION BY s ORDER BY h.name, a.name))::int % 280),
+			'League', 'completed'
+		FROM generate_series(0, 9) s CROSS JOIN teams h CROSS JOIN teams a
+		WHERE h.name LIKE 'bench %' AND a.name LIKE 'bench %' AND h.id <> a.id`,
+		`INSERT INTO player_match_stats (id, player_id, match_id, team_id, minutes_played, goals, passes, pass_accuracy,
+			distance_covered)
+		SELECT uuid_generate_v4(), p.id, m.id, p.team_id, 90, p.number % 3, 40, 80, 10
+		FROM matches m JOIN players p ON p.team_id IN (m.home_team_id, m.away_team_id) AND p.number <= 14
+		WHERE p.name LIKE 'bench %'`,
+	} {
+		if _, err := db.Exec(statement); err != nil {
+			return "", err
+		}
+	}
+
+	var playerID string
+	err := db.Get(&playerID, `SELECT id FROM players WHERE name LIKE 'bench %' AND number = 7 LIMIT 1`)
+	return playerID, err
+}
+
+// BenchmarkListByPlayerAndDateRange compare the joined date range query with the queries it replaced,
+// every match of the range and the full stats history of the player, on a database of 10 seasons
+func BenchmarkListByPlayerAndDateRange(b *testing.B) {
+	db, err := NewConnection(testDatabaseURL)
+	if err != nil {
+		b.Skipf("database not available: %v", err)
+	}
+	defer func() {
+		for _, table := range []string{"player_match_stats", "matches", "players", "teams"} {
+			if _, err := db.Exec("DELETE FROM " + table); err != nil {
+				b.Error(err)
+			}
+		}
+		db.Close()
+	}()
+
+	playerID, err := seedBenchmarkStats(db)
+	if err != nil {
+		b.Fatal(err)
+	}
+	repository := NewPlayerMatchStatsRepository(db)
+	start, end := time.Date(2022, 8, 1, 0, 0, 0, 0, time.UTC), time.Now()
+
+	b.Run("joined", func(b *testing.B) {
+		for i := 0; i < b.N; i++ {
+			if _, err := repository.ListByPlayerAndDateRange(playerID, start, end); err != nil {
+				b.Fatal(err)
+			}
+		}
+	})
+
+	b.Run("legacy", func(b *testing.B) {
+		for i := 0; i < b.N; i++ {
+			var matchIDs []string
+			if err := db.Select(&matchIDs, `SELECT id FROM matches WHERE date >= $1 AND date <= $2`, start, end); err != nil {
+				b.Fatal(err)
+			}
+			inRange := make(map[string]bool, len(matchIDs))
+			for _, id := range matchIDs {
+				inRange[id] = true
+			}
+
+			rows, err := repository.ListByPlayerID(playerID)
+			if err != nil {
+				b.Fatal(err)
+			}
+			var kept []*domain.PlayerMatchStats
+			for _, row := range rows {
+				if inRange[row.MatchID] {
+					kept = append(kept, row)
+				}
+			}
+		}
+	})
+}
@@ -0,0 +1,133 @@
+package postgres
+
+import (
+	"database/sql"
+	"encoding/json"
+	"errors"
+	"football-analytics/internal/domain"
+
+	"github.com/jmoiron/sqlx"
+)
+
+type roleRepository struct {
+	db *sqlx.DB
+}
+
+// NewRoleRepository create repository for player role models
+func NewRoleRepository(db *sqlx.DB) domain.RoleRepository {
+	return &roleRepository{
+		db: db,
+	}
+}
+
+// Save add new RoleModel with the role of every clustered player
+func (r *roleRepository) Save(model *domain.RoleModel) error {
+	clusters, err := json.Marshal(model.Clusters)
+	if err != nil {
+		return err
+	}
+
+	tx, err := r.db.Beginx()
+	if err != nil {
+		return err
+	}
+	defer tx.Rollback()
+
+	_, err = tx.Exec(`
+		INSERT INTO role_models (id, season, k, inertia, clusters, created_at)
+		VALUES ($1, $2, $3, $4, $5, $6)
+	`, model.ID, model.Season, model.K, model.Inertia, clusters, model.CreatedAt)
+	if err != nil {
+		return err
+	}
+
+	for _, role := range model.Assignments {
+		_, err := tx.Exec(`
+			INSERT INTO player_roles (role_model_id, player_id, role_id, label, confidence, minutes)
+			VALUES ($1, $2, $3, $4, $5, $6)
+		`, model.ID, role.PlayerID, role.RoleID, role.Label, role.Confidence, role.Minutes)
+		if err != nil {
+			return err
+		}
+	}
+
+	return tx.Commit()
+}
+
+func (r *roleRepository) GetLatest() (*domain.RoleModel, error) {
+	query := `
+		SELECT id, season, k, inertia, clusters, created_at
+		FROM role_models
+		ORDER BY created_at DESC
+		LIMIT 1
+	`
+
+	return r.get(query)
+}
+
+func (r *roleRepository) GetLatestBySeason(season string) (*domain.RoleModel, error) {
+	query := `
+		SELECT id, season, k, inertia, clusters, created_at
+		FROM role_models
+		WHERE season = $1
+		ORDER BY created_at DESC
+		LIMIT 1
+	`
+
+	return r.get(query, season)
+}
+
+func (r *roleRepository) get(query string, args ...interface{}) (*domain.RoleModel, error) {
+	var model domain.RoleModel
+	var clusters []byte
+
+	err := r.db.QueryRowx(query, args...).Scan(
+		&model.ID,
+		&model.Season,
+		&model.K,
+		&model.Inertia,
+		&clusters,
+		&model.CreatedAt,
+	)
+	if errors.Is(err, sql.ErrNoRows) {
+		return nil, domain.ErrNotFound
+	}
+	if err != nil {
+		return nil, err
+	}
+
+	if err := json.Unmarshal(clusters, &model.Clusters); err != nil {
+		return nil, err
+	}
+
+	rows, err := r.db.Queryx(`
+		SELECT pr.player_id, p.name, p.position, pr.role_id, pr.label, pr.confidence, pr.minutes
+		FROM player_roles pr
+		JOIN players p ON p.id = pr.player_id
+		WHERE pr.role_model_id = $1
+		ORDER BY pr.role_id, pr.confidence DESC
+	`, model.ID)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	for rows.Next() {
+		var role domain.PlayerRole
+		err := rows.Scan(
+			&role.PlayerID,
+			&role.Name,
+			&role.Position,
+			&role.RoleID,
+			&role.Label,
+			&role.Confidence,
+			&role.Minutes,
+		)
+		if err != nil {
+			return nil, err
+		}
+		model.Assignments = append(model.Assignments, &role)
+	}
+
+	return &model, rows.Err()
+}
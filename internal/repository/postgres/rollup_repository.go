@@ -0,0 +1,208 @@
+package postgres
+
+import (
+	"football-analytics/internal/domain"
+
+	"github.com/jmoiron/sqlx"
+)
+
+// rollupColumns columns of player_season_rollups in rollupDest order
+const rollupColumns = `player_id, season, competition, matches_played, minutes_played, goals, assists, passes,
+	pass_accuracy_sum, shots, shots_on_target, tackles, interceptions, fouls, yellow_cards, red_cards, distance_covered`
+
+type rollupRepository struct {
+	db *sqlx.DB
+}
+
+// NewRollupRepository create repository for player season rollups
+func NewRollupRepository(db *sqlx.DB) domain.RollupRepository {
+	return &rollupRepository{
+		db: db,
+	}
+}
+
+// Apply add the totals of delta to its row in one statement, so concurrent writers do not lose updates
+func (r *rollupRepository) Apply(delta *domain.PlayerSeasonRollup) error {
+	tx, err := r.db.Beginx()
+	if err != nil {
+		return err
+	}
+	defer tx.Rollback()
+
+	_, err = tx.Exec(`
+		INSERT INTO player_season_rollups (`+rollupColumns+`, updated_at)
+		VALUES ($1, $2, $3, $4, $5, $6, $7, $8, $9, $10, $11, $12, $13, $14, $15, $16, $17, NOW())
+		ON CONFLICT (player_id, season, competition) DO UPDATE
+		SET matches_played = player_season_rollups.matches_played + EXCLUDED.matches_played,
+			minutes_played = player_season_rollups.minutes_played + EXCLUDED.minutes_played,
+			goals = player_season_rollups.goals + EXCLUDED.goals,
+			assists = player_season_rollups.assists + EXCLUDED.assists,
+			passes = player_season_rollups.passes + EXCLUDED.passes,
+			pass_accuracy_sum = player_season_rollups.pass_accuracy_sum + EXCLUDED.pass_accuracy_sum,
+			shots = player_season_rollups.shots + EXCLUDED.shots,
+			shots_on_target = player_season_rollups.shots_on_target + EXCLUDED.shots_on_target,
+			tackles = player_season_rollups.tackles + EXCLUDED.tackles,
+			interceptions = player_season_rollups.interceptions + EXCLUDED.interceptions,
+			fouls = player_season_rollups.fouls + EXCLUDED.fouls,
+			yellow_cards = player_season_rollups.yellow_cards + EXCLUDED.yellow_cards,
+			red_cards = player_season_rollups.red_cards + EXCLUDED.red_cards,
+			distance_covered = player_season_rollups.distance_covered + EXCLUDED.distance_covered,
+			updated_at = NOW()
+	`, rollupValues(delta)...)
+	if err != nil {
+		return err
+	}
+
+	_, err = tx.Exec(`
+		DELETE FROM player_season_rollups
+		WHERE player_id = $1 AND season = $2 AND competition = $3 AND matches_played <= 0
+	`, delta.PlayerID, delta.Season, delta.Competition)
+	if err != nil {
+		return err
+	}
+
+	return tx.Commit()
+}
+
+func (r *rollupRepository) List() ([]*domain.PlayerSeasonRollup, error) {
+	query := `
+		SELECT ` + rollupColumns + `, updated_at
+		FROM player_season_rollups
+		ORDER BY player_id, season, competition
+	`
+
+	rows, err := r.db.Queryx(query)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	var rollups []*domain.PlayerSeasonRollup
+	for rows.Next() {
+		var rollup domain.PlayerSeasonRollup
+		if err := rows.Scan(append(rollupDest(&rollup), &rollup.UpdatedAt)...); err != nil {
+			return nil, err
+		}
+		rollups = append(rollups, &rollup)
+	}
+
+	return rollups, rows.Err()
+}
+
+// ComputeFromRaw aggregate rollups from player_match_stats, a match is in its linked season, else in the
+// latest season of its competition started by its date, else in the August season like the service calendar
+func (r *rollupRepository) ComputeFromRaw() ([]*domain.PlayerSeasonRollup, error) {
+	query := `
+		SELECT s.player_id,
+			COALESCE(linked.label, started.label, y.year || '/' || LPAD(((y.year + 1) % 100)::text, 2, '0')) AS season,
+			COALESCE(m.competition, ''),
+			COUNT(*), SUM(s.minutes_played), SUM(s.goals), SUM(s.assists), SUM(s.passes), SUM(s.pass_accuracy),
+			SUM(s.shots), SUM(s.shots_on_target), SUM(s.tackles), SUM(s.interceptions), SUM(s.fouls),
+			SUM(s.yellow_cards), SUM(s.red_cards), SUM(s.distance_covered)
+		FROM player_match_stats s
+		JOIN matches m ON m.id = s.match_id
+		LEFT JOIN seasons linked ON linked.id = m.season_id
+		LEFT JOIN LATERAL (
+			SELECT se.label
+			FROM seasons se
+			WHERE se.competition = COALESCE(m.competition, '') AND se.start_date <= m.date
+			ORDER BY se.start_date DESC
+			LIMIT 1
+		) started ON true
+		CROSS JOIN LATERAL (
+			SELECT EXTRACT(YEAR FROM m.date AT TIME ZONE 'UTC')::int
+				- CASE WHEN EXTRACT(MONTH FROM m.date AT TIME ZONE 'UTC') < 8 THEN 1 ELSE 0 END AS year
+		) y
+		GROUP BY s.player_id, 2, COALESCE(m.competition, '')
+		ORDER BY s.player_id, 2, 3
+	`
+
+	rows, err := r.db.Queryx(query)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	var rollups []*domain.PlayerSeasonRollup
+	for rows.Next() {
+		var rollup domain.PlayerSeasonRollup
+		if err := rows.Scan(rollupDest(&rollup)...); err != nil {
+			return nil, err
+		}
+		rollups = append(rollups, &rollup)
+	}
+
+	return rollups, rows.Err()
+}
+
+// Replace set the totals of a rollup row, creating it
+func (r *rollupRepository) Replace(rollup *domain.PlayerSeasonRollup) error {
+	query := `
+		INSERT INTO player_season_rollups (` + rollupColumns + `, updated_at)
+		VALUES ($1, $2, $3, $4, $5, $6, $7, $8, $9, $10, $11, $12, $13, $14, $15, $16, $17, NOW())
+		ON CONFLICT (player_id, season, competition) DO UPDATE
+		SET matches_played = EXCLUDED.matches_played, minutes_played = EXCLUDED.minutes_played,
+			goals = EXCLUDED.goals, assists = EXCLUDED.assists, passes = EXCLUDED.passes,
+			pass_accuracy_sum = EXCLUDED.pass_accuracy_sum, shots = EXCLUDED.shots,
+			shots_on_target = EXCLUDED.shots_on_target, tackles = EXCLUDED.tackles,
+			interceptions = EXCLUDED.interceptions, fouls = EXCLUDED.fouls, yellow_cards = EXCLUDED.yellow_cards,
+			red_cards = EXCLUDED.red_cards, distance_covered = EXCLUDED.distance_covered, updated_at = NOW()
+	`
+
+	_, err := r.db.Exec(query, rollupValues(rollup)...)
+	return err
+}
+
+func (r *rollupRepository) Delete(playerID, season, competition string) error {
+	_, err := r.db.Exec(`
+		DELETE FROM player_season_rollups
+		WHERE player_id = $1 AND season = $2 AND competition = $3
+	`, playerID, season, competition)
+	return err
+}
+
+// rollupValues values of rollupColumns
+func rollupValues(rollup *domain.PlayerSeasonRollup) []interface{} {
+	return []interface{}{
+		rollup.PlayerID,
+		rollup.Season,
+		rollup.Competition,
+		rollup.MatchesPlayed,
+		rollup.MinutesPlayed,
+		rollup.Goals,
+		rollup.Assists,
+		rollup.Passes,
+		rollup.PassAccuracySum,
+		rollup.Shots,
+		rollup.ShotsOnTarget,
+		rollup.Tackles,
+		rollup.Interceptions,
+		rollup.Fouls,
+		rollup.YellowCards,
+		rollup.RedCards,
+		rollup.DistanceCovered,
+	}
+}
+
+// rollupDest scan destinations of rollupColumns
+func rollupDest(rollup *domain.PlayerSeasonRollup) []interface{} {
+	return []interface{}{
+		&rollup.PlayerID,
+		&rollup.Season,
+		&rollup.Competition,
+		&rollup.MatchesPlayed,
+		&rollup.MinutesPlayed,
+		&rollup.Goals,
+		&rollup.Assists,
+		&rollup.Passes,
+		&rollup.PassAccuracySum,
+		&rollup.Shots,
+		&rollup.ShotsOnTarget,
+		&rollup.Tackles,
+		&rollup.Interceptions,
+		&rollup.Fouls,
+		&rollup.YellowCards,
+		&rollup.RedCards,
+		&rollup.DistanceCovered,
+	}
+}
@@ -0,0 +1,160 @@
+package postgres
+
+import (
+	"database/sql"
+	"errors"
+	"football-analytics/internal/domain"
+
+	"github.com/jmoiron/sqlx"
+)
+
+const seasonColumns = `id, competition, label, start_date, end_date, created_at, updated_at`
+
+type seasonRepository struct {
+	db *sqlx.DB
+}
+
+// NewSeasonRepository create repository for competition seasons
+func NewSeasonRepository(db *sqlx.DB) domain.SeasonRepository {
+	return &seasonRepository{
+		db: db,
+	}
+}
+
+// Create add new Season and link the matches of its competition played during it
+func (r *seasonRepository) Create(season *domain.Season) error {
+	tx, err := r.db.Beginx()
+	if err != nil {
+		return err
+	}
+	defer tx.Rollback()
+
+	_, err = tx.Exec(`
+		INSERT INTO seasons (`+seasonColumns+`)
+		VALUES ($1, $2, $3, $4, $5, $6, $7)
+	`, season.ID, season.Competition, season.Label, season.StartDate, season.EndDate, season.CreatedAt, season.UpdatedAt)
+	if err != nil {
+		return err
+	}
+
+	if err := linkSeasonMatches(tx, season); err != nil {
+		return err
+	}
+
+	return tx.Commit()
+}
+
+func (r *seasonRepository) GetByID(id string) (*domain.Season, error) {
+	query := `
+		SELECT ` + seasonColumns + `
+		FROM seasons
+		WHERE id = $1
+	`
+
+	var season domain.Season
+	err := r.db.QueryRowx(query, id).Scan(seasonDest(&season)...)
+	if errors.Is(err, sql.ErrNoRows) {
+		return nil, domain.ErrNotFound
+	}
+	if err != nil {
+		return nil, err
+	}
+
+	return &season, nil
+}
+
+// Update change the dates or label of a season and relink the matches of its competition
+func (r *seasonRepository) Update(season *domain.Season) error {
+	tx, err := r.db.Beginx()
+	if err != nil {
+		return err
+	}
+	defer tx.Rollback()
+
+	result, err := tx.Exec(`
+		UPDATE seasons
+		SET competition = $2, label = $3, start_date = $4, end_date = $5, updated_at = $6
+		WHERE id = $1
+	`, season.ID, season.Competition, season.Label, season.StartDate, season.EndDate, season.UpdatedAt)
+	if err != nil {
+		return err
+	}
+	if n, err := result.RowsAffected(); err == nil && n == 0 {
+		return domain.ErrNotFound
+	}
+
+	// matches that no longer fall in the season are unlinked before the ones that do are linked
+	_, err = tx.Exec(`
+		UPDATE matches
+		SET season_id = NULL
+		WHERE season_id = $1 AND (competition IS DISTINCT FROM $2 OR date < $3 OR date >= $4)
+	`, season.ID, season.Competition, season.StartDate, season.EndDate)
+	if err != nil {
+		return err
+	}
+
+	if err := linkSeasonMatches(tx, season); err != nil {
+		return err
+	}
+
+	return tx.Commit()
+}
+
+func (r *seasonRepository) List() ([]*domain.Season, error) {
+	return r.list(`
+		SELECT ` + seasonColumns + `
+		FROM seasons
+		ORDER BY competition, start_date
+	`)
+}
+
+func (r *seasonRepository) ListByCompetition(competition string) ([]*domain.Season, error) {
+	return r.list(`
+		SELECT `+seasonColumns+`
+		FROM seasons
+		WHERE competition = $1
+		ORDER BY start_date
+	`, competition)
+}
+
+func (r *seasonRepository) list(query string, args ...interface{}) ([]*domain.Season, error) {
+	rows, err := r.db.Queryx(query, args...)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	var seasons []*domain.Season
+	for rows.Next() {
+		var season domain.Season
+		if err := rows.Scan(seasonDest(&season)...); err != nil {
+			return nil, err
+		}
+		seasons = append(seasons, &season)
+	}
+
+	return seasons, rows.Err()
+}
+
+// linkSeasonMatches set the season of the competition's matches played during it
+func linkSeasonMatches(tx *sqlx.Tx, season *domain.Season) error {
+	_, err := tx.Exec(`
+		UPDATE matches
+		SET season_id = $1
+		WHERE COALESCE(competition, '') = $2 AND date >= $3 AND date < $4
+	`, season.ID, season.Competition, season.StartDate, season.EndDate)
+	return err
+}
+
+// seasonDest scan destinations of seasonColumns
+func seasonDest(season *domain.Season) []interface{} {
+	return []interface{}{
+		&season.ID,
+		&season.Competition,
+		&season.Label,
+		&season.StartDate,
+		&season.EndDate,
+		&season.CreatedAt,
+		&season.UpdatedAt,
+	}
+}
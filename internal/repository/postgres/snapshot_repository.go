@@ -0,0 +1,125 @@
+package postgres
+
+import (
+	"football-analytics/internal/domain"
+	"time"
+
+	"github.com/jmoiron/sqlx"
+)
+
+// snapshotColumns columns of metrics_snapshots in snapshotDest order
+const snapshotColumns = `id, player_id, date, time_range, model_version, goals_per_minute, assists_per_minute,
+	pass_accuracy, shot_accuracy, defensive_efficiency, stamina, overall_rating, created_at`
+
+type snapshotRepository struct {
+	db *sqlx.DB
+}
+
+// NewSnapshotRepository create repository for player metrics snapshots
+func NewSnapshotRepository(db *sqlx.DB) domain.SnapshotRepository {
+	return &snapshotRepository{
+		db: db,
+	}
+}
+
+// Save add new MetricsSnapshot, a snapshot of the same player, date, time range and model version is replaced
+func (r *snapshotRepository) Save(snapshot *domain.MetricsSnapshot) error {
+	query := `
+		INSERT INTO metrics_snapshots (` + snapshotColumns + `)
+		VALUES ($1, $2, $3, $4, $5, $6, $7, $8, $9, $10, $11, $12, $13)
+		ON CONFLICT (player_id, date, time_range, model_version) DO UPDATE
+		SET goals_per_minute = EXCLUDED.goals_per_minute, assists_per_minute = EXCLUDED.assists_per_minute,
+			pass_accuracy = EXCLUDED.pass_accuracy, shot_accuracy = EXCLUDED.shot_accuracy,
+			defensive_efficiency = EXCLUDED.defensive_efficiency, stamina = EXCLUDED.stamina,
+			overall_rating = EXCLUDED.overall_rating, created_at = EXCLUDED.created_at
+	`
+
+	metrics := snapshot.Metrics
+	_, err := r.db.Exec(
+		query,
+		snapshot.ID,
+		snapshot.PlayerID,
+		snapshot.Date,
+		snapshot.TimeRange,
+		snapshot.ModelVersion,
+		metrics.GoalsPerMinute,
+		metrics.AssistsPerMinute,
+		metrics.PassAccuracy,
+		metrics.ShotAccuracy,
+		metrics.DefensiveEfficiency,
+		metrics.Stamina,
+		metrics.OverallRating,
+		snapshot.CreatedAt,
+	)
+
+	return err
+}
+
+func (r *snapshotRepository) ListByPlayer(playerID, timeRange, modelVersion string, start, end time.Time) ([]*domain.MetricsSnapshot, error) {
+	query := `
+		SELECT ` + snapshotColumns + `
+		FROM metrics_snapshots
+		WHERE player_id = $1 AND time_range = $2 AND model_version = $3 AND date >= $4::date
+			AND ($5::date IS NULL OR date <= $5::date)
+		ORDER BY date
+	`
+
+	var endDate interface{}
+	if !end.IsZero() {
+		endDate = end
+	}
+
+	return r.list(query, playerID, timeRange, modelVersion, start, endDate)
+}
+
+// ListLatest latest snapshot of every player on or before date
+func (r *snapshotRepository) ListLatest(date time.Time, timeRange, modelVersion string) ([]*domain.MetricsSnapshot, error) {
+	query := `
+		SELECT DISTINCT ON (player_id) ` + snapshotColumns + `
+		FROM metrics_snapshots
+		WHERE time_range = $1 AND model_version = $2 AND date <= $3::date
+		ORDER BY player_id, date DESC
+	`
+
+	return r.list(query, timeRange, modelVersion, date)
+}
+
+func (r *snapshotRepository) list(query string, args ...interface{}) ([]*domain.MetricsSnapshot, error) {
+	rows, err := r.db.Queryx(query, args...)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	var snapshots []*domain.MetricsSnapshot
+	for rows.Next() {
+		snapshot := domain.MetricsSnapshot{Metrics: &domain.PerformanceMetrics{}}
+		if err := rows.Scan(snapshotDest(&snapshot)...); err != nil {
+			return nil, err
+		}
+		snapshot.Metrics.PlayerID = snapshot.PlayerID
+		snapshots = append(snapshots, &snapshot)
+	}
+
+	return snapshots, rows.Err()
+}
+
+// snapshotDest scan destinations of snapshotColumns
+func snapshotDest(snapshot *domain.MetricsSnapshot) []interface{} {
+	metrics := snapshot.Metrics
+	return []interface{}{
+		&snapshot.ID,
+		&snapshot.PlayerID,
+		&snapshot.Date,
+		&snapshot.TimeRange,
+		&snapshot.ModelVersion,
+		&metrics.GoalsPerMinute,
+		&metrics.AssistsPerMinute,
+		&metrics.PassAccuracy,
+		&metrics.ShotAccuracy,
+		&metrics.DefensiveEfficiency,
+		&metrics.Stamina,
+		&metrics.OverallRating,
+		&snapshot.CreatedAt,
+	}
+}
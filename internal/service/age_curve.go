@@ -0,0 +1,223 @@
+package service
+
+import (
+	"errors"
+	"football-analytics/internal/domain"
+	"time"
+)
+
+// minPeakBandMinutes bands with less minutes are not considered for the peak of an age curve
+const minPeakBandMinutes = 900
+
+// ageBands age ranges of an age curve, the first and last bands are open ended
+var ageBands = [][2]int{
+	{0, 18},
+	{19, 20},
+	{21, 22},
+	{23, 24},
+	{25, 26},
+	{27, 28},
+	{29, 30},
+	{31, 32},
+	{33, 34},
+	{35, 99},
+}
+
+// GetAgeCurve get per 90 output by age band of every player of a position across the dataset
+func (s *analyticsService) GetAgeCurve(position string) (*domain.AgeCurve, error) {
+	players, err := s.playerRepo.List()
+	if err != nil {
+		return nil, err
+	}
+
+	appearancesByPlayer, err := s.appearancesByPlayerInRange(time.Time{}, s.clock.Now())
+	if err != nil {
+		return nil, err
+	}
+
+	return buildAgeCurve(position, players, appearancesByPlayer), nil
+}
+
+// GetPlayerCareerPhase place a player on the age curve of their position
+func (s *analyticsService) GetPlayerCareerPhase(playerID string) (*domain.PlayerCareerPhase, error) {
+	player, err := s.playerRepo.GetByID(playerID)
+	if err != nil {
+		return nil, err
+	}
+	if player.Birthday.IsZero() {
+		return nil, errors.New("player has no birthday")
+	}
+
+	players, err := s.playerRepo.List()
+	if err != nil {
+		return nil, err
+	}
+
+	now := s.clock.Now()
+	appearancesByPlayer, err := s.appearancesByPlayerInRange(time.Time{}, now)
+	if err != nil {
+		return nil, err
+	}
+
+	curve := buildAgeCurve(player.Position, players, appearancesByPlayer)
+
+	// current output is the last 365 days of appearances
+	var recent []*domain.PlayerMatchStats
+	for _, app := range appearancesByPlayer[player.ID] {
+		if !app.match.Date.Before(now.AddDate(-1, 0, 0)) {
+			recent = append(recent, app.stats)
+		}
+	}
+	profile := buildProfile(player.ID, recent)
+	if profile.minutes == 0 {
+		return nil, domain.ErrNoAppearances
+	}
+
+	age := ageAt(player.Birthday, now)
+	phase := &domain.PlayerCareerPhase{
+		PlayerID: player.ID,
+		Position: player.Position,
+		Age:      age,
+		Per90:    profileMap(profile),
+	}
+
+	if curve.PeakBand != nil {
+		switch {
+		case age < curve.PeakBand.MinAge:
+			phase.Phase = domain.CareerDevelopment
+		case age > curve.PeakBand.MaxAge:
+			phase.Phase = domain.CareerDecline
+		default:
+			phase.Phase = domain.CareerPeak
+		}
+	}
+
+	expected := nearestAgeBand(curve, age)
+	if expected == nil {
+		return phase, nil
+	}
+	phase.Expected = expected.Per90
+
+	var ratioSum float64
+	var ratios int
+	for metric, value := range phase.Per90 {
+		if outputMetric(metric) && expected.Per90[metric] > 0 {
+			ratioSum += value / expected.Per90[metric]
+			ratios++
+		}
+	}
+	if ratios > 0 {
+		phase.RelativeIndex = ratioSum / float64(ratios)
+	}
+
+	return phase, nil
+}
+
+// buildAgeCurve aggregate appearances of the players of a position by their age at each match
+func buildAgeCurve(position string, players []*domain.Player, appearancesByPlayer map[string][]appearance) *domain.AgeCurve {
+	bandStats := make([][]*domain.PlayerMatchStats, len(ageBands))
+	bandPlayers := make([]map[string]bool, len(ageBands))
+	var allStats []*domain.PlayerMatchStats
+
+	for _, player := range players {
+		if player.Position != position || player.Birthday.IsZero() {
+			continue
+		}
+		for _, app := range appearancesByPlayer[player.ID] {
+			band := ageBandIndex(ageAt(player.Birthday, app.match.Date))
+			bandStats[band] = append(bandStats[band], app.stats)
+			if bandPlayers[band] == nil {
+				bandPlayers[band] = make(map[string]bool)
+			}
+			bandPlayers[band][player.ID] = true
+			allStats = append(allStats, app.stats)
+		}
+	}
+
+	average := buildProfile("", allStats)
+	curve := &domain.AgeCurve{Position: position}
+
+	for i, stats := range bandStats {
+		profile := buildProfile("", stats)
+		if profile.minutes == 0 {
+			continue
+		}
+
+		band := &domain.AgeBand{
+			MinAge:      ageBands[i][0],
+			MaxAge:      ageBands[i][1],
+			Players:     len(bandPlayers[i]),
+			Appearances: len(stats),
+			Minutes:     profile.minutes,
+			Per90:       profileMap(profile),
+		}
+
+		// output is the mean ratio of the band to the position average over every output metric
+		var ratios int
+		for k, value := range profile.per90 {
+			if outputMetric(profileMetrics[k]) && average.per90[k] > 0 {
+				band.Output += value / average.per90[k]
+				ratios++
+			}
+		}
+		if ratios > 0 {
+			band.Output /= float64(ratios)
+		}
+
+		curve.Bands = append(curve.Bands, band)
+	}
+
+	for _, band := range curve.Bands {
+		if band.Minutes < minPeakBandMinutes {
+			continue
+		}
+		if curve.PeakBand == nil || band.Output > curve.PeakBand.Output {
+			curve.PeakBand = band
+		}
+	}
+
+	return curve
+}
+
+// outputMetric whether a profile metric counts as output, more fouls is not a better player so they
+// are reported per 90 but left out of the band output and the relative index
+func outputMetric(metric string) bool {
+	return metric != "fouls"
+}
+
+// ageBandIndex index of the age band containing age
+func ageBandIndex(age int) int {
+	for i, band := range ageBands {
+		if age <= band[1] {
+			return i
+		}
+	}
+	return len(ageBands) - 1
+}
+
+// nearestAgeBand the band of the curve containing age, or the closest band with data
+func nearestAgeBand(curve *domain.AgeCurve, age int) *domain.AgeBand {
+	var nearest *domain.AgeBand
+	bestDistance := 0
+	for _, band := range curve.Bands {
+		distance := 0
+		if age < band.MinAge {
+			distance = band.MinAge - age
+		} else if age > band.MaxAge {
+			distance = age - band.MaxAge
+		}
+		if nearest == nil || distance < bestDistance {
+			nearest, bestDistance = band, distance
+		}
+	}
+	return nearest
+}
+
+// profileMap per 90 values of a profile by metric name
+func profileMap(profile *playerProfile) map[string]float64 {
+	result := make(map[string]float64)
+	for k, metric := range profileMetrics {
+		result[metric] = profile.per90[k]
+	}
+	return result
+}
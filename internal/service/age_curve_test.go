@@ -0,0 +1,90 @@
+package service
+
+import (
+	"fmt"
+	"football-analytics/internal/domain"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+// newAgeCurveFixture forwards of 20 and 28 and a keeper playing ten weekly matches from 2024-09-01
+func newAgeCurveFixture() (*analyticsService, []*domain.Player, map[string][]appearance) {
+	players := &memoryPlayerRepository{players: make(map[string]*domain.Player)}
+	for _, player := range []*domain.Player{
+		{ID: "young", Position: "Forward", Birthday: utcDate(2004, 1, 1)},
+		{ID: "old", Position: "Forward", Birthday: utcDate(1996, 1, 1)},
+		{ID: "steady", Position: "Forward", Birthday: utcDate(1996, 1, 1)},
+		{ID: "keeper", Position: "Goalkeeper", Birthday: utcDate(1996, 1, 1)},
+	} {
+		players.players[player.ID] = player
+		players.list = append(players.list, player)
+	}
+
+	stats := &memoryStatsRepository{matches: make(map[string]*domain.Match), byPlayer: make(map[string][]*domain.PlayerMatchStats)}
+	appearancesByPlayer := make(map[string][]appearance)
+	for i := 0; i < 10; i++ {
+		match := &domain.Match{ID: fmt.Sprintf("m%d", i+1), Date: utcDate(2024, 9, 1).AddDate(0, 0, 7*i), Status: "completed"}
+		stats.matches[match.ID] = match
+		for _, row := range []domain.PlayerMatchStats{
+			{PlayerID: "young", Goals: 2},
+			{PlayerID: "old", Goals: 1, Fouls: 5},
+			{PlayerID: "steady", Goals: 1},
+			{PlayerID: "keeper", Passes: 30},
+		} {
+			stat := row
+			stat.MatchID = match.ID
+			stat.MinutesPlayed = 90
+			stats.byPlayer[stat.PlayerID] = append(stats.byPlayer[stat.PlayerID], &stat)
+			appearancesByPlayer[stat.PlayerID] = append(appearancesByPlayer[stat.PlayerID], appearance{match: match, stats: &stat})
+		}
+	}
+
+	service := &analyticsService{
+		playerStatsRepo: stats,
+		playerRepo:      players,
+		calendar:        newSeasonCalendar(nil),
+		clock:           domain.FixedClock(utcDate(2024, 12, 1)),
+	}
+	return service, players.list, appearancesByPlayer
+}
+
+func TestBuildAgeCurve(t *testing.T) {
+	_, players, appearancesByPlayer := newAgeCurveFixture()
+
+	curve := buildAgeCurve("Forward", players, appearancesByPlayer)
+
+	assert.Len(t, curve.Bands, 2)
+	assert.Equal(t, 19, curve.Bands[0].MinAge)
+	assert.Equal(t, 1, curve.Bands[0].Players)
+	assert.Equal(t, 27, curve.Bands[1].MinAge)
+	assert.Equal(t, 2, curve.Bands[1].Players)
+	assert.Equal(t, 20, curve.Bands[1].Appearances)
+	assert.InDelta(t, 2.5, curve.Bands[1].Per90["fouls"], 1e-9)
+
+	// goals are the only output, the fouls of the older band do not raise it
+	assert.InDelta(t, 2/(4.0/3), curve.Bands[0].Output, 1e-9)
+	assert.InDelta(t, 1/(4.0/3), curve.Bands[1].Output, 1e-9)
+	assert.Equal(t, 19, curve.PeakBand.MinAge)
+}
+
+func TestGetPlayerCareerPhase(t *testing.T) {
+	service, _, _ := newAgeCurveFixture()
+
+	young, err := service.GetPlayerCareerPhase("young")
+	assert.NoError(t, err)
+	assert.Equal(t, 20, young.Age)
+	assert.Equal(t, domain.CareerPeak, young.Phase)
+
+	// old scores like the band and fouls twice as often, which does not count as output
+	old, err := service.GetPlayerCareerPhase("old")
+	assert.NoError(t, err)
+	assert.Equal(t, 28, old.Age)
+	assert.Equal(t, domain.CareerDecline, old.Phase)
+	assert.InDelta(t, 5, old.Per90["fouls"], 1e-9)
+	assert.InDelta(t, 1, old.RelativeIndex, 1e-9)
+
+	service.clock = domain.FixedClock(utcDate(2026, 1, 1))
+	_, err = service.GetPlayerCareerPhase("old")
+	assert.ErrorIs(t, err, domain.ErrNoAppearances)
+}
@@ -0,0 +1,378 @@
+package service
+
+import (
+	"errors"
+	"fmt"
+	"football-analytics/internal/domain"
+	"log"
+	"sync"
+	"time"
+
+	"github.com/google/uuid"
+)
+
+// defaultAlertWindow matches of the rolling alert metrics when the rule has no window
+const defaultAlertWindow = 5
+
+// alertQueueSize triggered alerts waiting for delivery, alerts triggered while the queue is full are
+// stored but not delivered
+const alertQueueSize = 256
+
+// AlertService is interface for user defined alert rules, register it as observer of the observed
+// stats repository to evaluate the rules of a player after each write of their stats, triggered alerts
+// are delivered in the background until Close
+type AlertService interface {
+	domain.StatsObserver
+	SaveRule(rule *domain.AlertRule) (*domain.AlertRule, error)
+	ListRules() ([]*domain.AlertRule, error)
+	DeleteRule(id string) error
+	EvaluateMatch(playerID, matchID string) ([]*domain.Alert, error)
+	ListAlerts(filter domain.AlertFilter) ([]*domain.Alert, error)
+	Acknowledge(alertID string) (*domain.Alert, error)
+	Close()
+}
+
+// alertDelivery is a triggered alert queued for its notifiers
+type alertDelivery struct {
+	alert     *domain.Alert
+	notifiers []domain.Notifier
+}
+
+type alertService struct {
+	alertRepo       domain.AlertRepository
+	playerStatsRepo domain.PlayerMatchStatsRepository
+	playerRepo      domain.PlayerRepository
+	matchRepo       domain.MatchRepository
+	discipline      DisciplineService
+	workload        WorkloadService
+	notifiers       []domain.Notifier
+
+	mu         sync.Mutex
+	closed     bool
+	deliveries chan alertDelivery
+	delivered  chan struct{} // closed when the queue is drained after Close
+}
+
+// NewAlertService create instance of AlertService delivering triggered alerts through the notifiers,
+// call Close on shutdown to deliver the queued alerts
+func NewAlertService(
+	alertRepo domain.AlertRepository,
+	playerStatsRepo domain.PlayerMatchStatsRepository,
+	playerRepo domain.PlayerRepository,
+	matchRepo domain.MatchRepository,
+	discipline DisciplineService,
+	workload WorkloadService,
+	notifiers ...domain.Notifier,
+) AlertService {
+	s := &alertService{
+		alertRepo:       alertRepo,
+		playerStatsRepo: playerStatsRepo,
+		playerRepo:      playerRepo,
+		matchRepo:       matchRepo,
+		discipline:      discipline,
+		workload:        workload,
+		notifiers:       notifiers,
+		deliveries:      make(chan alertDelivery, alertQueueSize),
+		delivered:       make(chan struct{}),
+	}
+	go s.deliver()
+
+	return s
+}
+
+// SaveRule create or replace an alert rule
+func (s *alertService) SaveRule(rule *domain.AlertRule) (*domain.AlertRule, error) {
+	if rule.Name == "" {
+		return nil, errors.New("alert rule needs a name")
+	}
+	switch rule.Metric {
+	case domain.AlertRollingRatingChange, domain.AlertYellowCards, domain.AlertWorkloadRatio:
+	default:
+		return nil, fmt.Errorf("unknown alert metric %q", rule.Metric)
+	}
+	switch rule.Operator {
+	case domain.AlertAbove, domain.AlertAtLeast, domain.AlertBelow, domain.AlertAtMost:
+	default:
+		return nil, fmt.Errorf("unknown alert operator %q", rule.Operator)
+	}
+	if rule.Window < 0 {
+		return nil, fmt.Errorf("invalid window %d of alert rule %s", rule.Window, rule.Name)
+	}
+	if rule.Window == 0 {
+		rule.Window = defaultAlertWindow
+	}
+	for _, name := range rule.Notifiers {
+		if s.notifier(name) == nil {
+			return nil, fmt.Errorf("unknown notifier %q", name)
+		}
+	}
+
+	if rule.ID == "" {
+		rule.ID = uuid.New().String()
+		rule.CreatedAt = time.Now()
+	}
+	rule.UpdatedAt = time.Now()
+
+	if err := s.alertRepo.SaveRule(rule); err != nil {
+		return nil, err
+	}
+
+	return rule, nil
+}
+
+func (s *alertService) ListRules() ([]*domain.AlertRule, error) {
+	return s.alertRepo.ListRules()
+}
+
+// DeleteRule delete a rule together with its alerts
+func (s *alertService) DeleteRule(id string) error {
+	return s.alertRepo.DeleteRule(id)
+}
+
+// StatsChanged evaluate the rules of the player of a new or updated row, a failed evaluation is logged
+func (s *alertService) StatsChanged(old, new *domain.PlayerMatchStats) {
+	if new == nil {
+		return
+	}
+	if _, err := s.EvaluateMatch(new.PlayerID, new.MatchID); err != nil {
+		log.Printf("alert: evaluate player %s in match %s: %v", new.PlayerID, new.MatchID, err)
+	}
+}
+
+// EvaluateMatch evaluate the enabled rules of a player as of a match and return the alerts triggered,
+// a rule is not triggered again for a player while one of its alerts is open or for the same match
+func (s *alertService) EvaluateMatch(playerID, matchID string) ([]*domain.Alert, error) {
+	player, err := s.playerRepo.GetByID(playerID)
+	if err != nil {
+		return nil, err
+	}
+
+	match, err := s.matchRepo.GetByID(matchID)
+	if err != nil {
+		return nil, err
+	}
+
+	rules, err := s.alertRepo.ListRules()
+	if err != nil {
+		return nil, err
+	}
+
+	var stats []*domain.PlayerMatchStats
+	var triggered []*domain.Alert
+	for _, rule := range rules {
+		if !alertApplies(rule, player) {
+			continue
+		}
+
+		var value float64
+		var ok bool
+		switch rule.Metric {
+		case domain.AlertRollingRatingChange:
+			if stats == nil {
+				rows, err := s.playerStatsRepo.ListByPlayerAndDateRange(player.ID, time.Time{}, match.Date)
+				if err != nil {
+					return nil, err
+				}
+				stats = make([]*domain.PlayerMatchStats, 0, len(rows))
+				for _, row := range rows {
+					stats = append(stats, row.Stats)
+				}
+			}
+			value, ok = rollingRatingChange(stats, rule.Window)
+		case domain.AlertYellowCards:
+			// the status before the day after the match counts the cards of the match
+			status, err := s.discipline.GetPlayerStatus(player.ID, match.Competition, match.Date.AddDate(0, 0, 1))
+			if err != nil {
+				return nil, err
+			}
+			value, ok = float64(status.YellowCards), true
+		case domain.AlertWorkloadRatio:
+			workload, err := s.workload.GetPlayerWorkload(player.ID, match.Date)
+			if err != nil {
+				return nil, err
+			}
+			value, ok = workload.WorkloadRatio, true
+		}
+		if !ok || !rule.Operator.Compare(value, rule.Threshold) {
+			continue
+		}
+
+		alert, err := s.trigger(rule, player, match, value)
+		if err != nil {
+			return nil, err
+		}
+		if alert != nil {
+			triggered = append(triggered, alert)
+		}
+	}
+
+	return triggered, nil
+}
+
+func (s *alertService) ListAlerts(filter domain.AlertFilter) ([]*domain.Alert, error) {
+	return s.alertRepo.ListAlerts(filter)
+}
+
+// Acknowledge mark an alert as handled, acknowledging an acknowledged alert changes nothing
+func (s *alertService) Acknowledge(alertID string) (*domain.Alert, error) {
+	alert, err := s.alertRepo.GetAlert(alertID)
+	if err != nil {
+		return nil, err
+	}
+	if alert.State == domain.AlertAcknowledged {
+		return alert, nil
+	}
+
+	now := time.Now()
+	alert.State = domain.AlertAcknowledged
+	alert.AcknowledgedAt = &now
+	if err := s.alertRepo.UpdateAlert(alert); err != nil {
+		return nil, err
+	}
+
+	return alert, nil
+}
+
+// Close stop delivering new alerts and wait until the queued ones are delivered
+func (s *alertService) Close() {
+	s.mu.Lock()
+	if !s.closed {
+		s.closed = true
+		close(s.deliveries)
+	}
+	s.mu.Unlock()
+
+	<-s.delivered
+}
+
+// trigger store an alert of the rule and queue it for delivery, nil if the rule already has an open
+// alert for the player or an alert for the match, the open alert check is enforced by the repository
+// when evaluations of the player run concurrently
+func (s *alertService) trigger(rule *domain.AlertRule, player *domain.Player, match *domain.Match, value float64) (*domain.Alert, error) {
+	existing, err := s.alertRepo.ListAlerts(domain.AlertFilter{RuleID: rule.ID, PlayerID: player.ID})
+	if err != nil {
+		return nil, err
+	}
+	for _, alert := range existing {
+		if alert.State == domain.AlertOpen || alert.MatchID == match.ID {
+			return nil, nil
+		}
+	}
+
+	alert := &domain.Alert{
+		ID:          uuid.New().String(),
+		RuleID:      rule.ID,
+		RuleName:    rule.Name,
+		PlayerID:    player.ID,
+		MatchID:     match.ID,
+		Metric:      rule.Metric,
+		Value:       value,
+		Threshold:   rule.Threshold,
+		Message:     alertMessage(rule, player, value),
+		State:       domain.AlertOpen,
+		TriggeredAt: time.Now(),
+	}
+	err = s.alertRepo.CreateAlert(alert)
+	if errors.Is(err, domain.ErrDuplicate) {
+		return nil, nil
+	}
+	if err != nil {
+		return nil, err
+	}
+
+	var notifiers []domain.Notifier
+	for _, notifier := range s.notifiers {
+		if len(rule.Notifiers) == 0 || containsString(rule.Notifiers, notifier.Name()) {
+			notifiers = append(notifiers, notifier)
+		}
+	}
+	if len(notifiers) > 0 {
+		s.enqueue(alertDelivery{alert: alert, notifiers: notifiers})
+	}
+
+	return alert, nil
+}
+
+// enqueue queue a delivery without waiting, the alert stays stored when it can not be queued
+func (s *alertService) enqueue(delivery alertDelivery) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	if s.closed {
+		log.Printf("alert: service closed, alert %s not delivered", delivery.alert.ID)
+		return
+	}
+	select {
+	case s.deliveries <- delivery:
+	default:
+		log.Printf("alert: delivery queue full, alert %s not delivered", delivery.alert.ID)
+	}
+}
+
+// deliver send the queued alerts to their notifiers until the queue is closed, delivery failures
+// do not undo the stored alert
+func (s *alertService) deliver() {
+	defer close(s.delivered)
+
+	for delivery := range s.deliveries {
+		for _, notifier := range delivery.notifiers {
+			if err := notifier.Notify(delivery.alert); err != nil {
+				log.Printf("alert: notify %s of alert %s: %v", notifier.Name(), delivery.alert.ID, err)
+			}
+		}
+	}
+}
+
+// notifier the configured notifier of a name, nil if there is none
+func (s *alertService) notifier(name string) domain.Notifier {
+	for _, notifier := range s.notifiers {
+		if notifier.Name() == name {
+			return notifier
+		}
+	}
+	return nil
+}
+
+// alertApplies tell if an enabled rule watches the player
+func alertApplies(rule *domain.AlertRule, player *domain.Player) bool {
+	if rule.Disabled {
+		return false
+	}
+	if rule.PlayerID != "" && rule.PlayerID != player.ID {
+		return false
+	}
+	if rule.TeamID != "" && rule.TeamID != player.TeamID {
+		return false
+	}
+	return true
+}
+
+// rollingRatingChange percent change of the overall rating of the last window stats (ordered by date)
+// against the window before, false without enough stats or a previous rating
+func rollingRatingChange(stats []*domain.PlayerMatchStats, window int) (float64, bool) {
+	if window <= 0 || len(stats) < 2*window {
+		return 0, false
+	}
+
+	recent := calculateMetricsFromStats("", stats[len(stats)-window:]).OverallRating
+	previous := calculateMetricsFromStats("", stats[len(stats)-2*window:len(stats)-window]).OverallRating
+	if previous == 0 {
+		return 0, false
+	}
+
+	return (recent - previous) / previous * 100, true
+}
+
+// alertMessage human readable description of a triggered rule
+func alertMessage(rule *domain.AlertRule, player *domain.Player, value float64) string {
+	var description string
+	switch rule.Metric {
+	case domain.AlertRollingRatingChange:
+		description = fmt.Sprintf("rolling %d match rating changed %+.1f%%", rule.Window, value)
+	case domain.AlertYellowCards:
+		description = fmt.Sprintf("%.0f yellow cards", value)
+	case domain.AlertWorkloadRatio:
+		description = fmt.Sprintf("workload ratio %.2f", value)
+	}
+	return fmt.Sprintf("%s: %s (%s %s %g)", player.Name, description, rule.Metric, rule.Operator, rule.Threshold)
+}
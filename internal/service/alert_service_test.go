@@ -0,0 +1,213 @@
+package service
+
+import (
+	"errors"
+	"football-analytics/internal/domain"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestRollingRatingChange(t *testing.T) {
+	var stats []*domain.PlayerMatchStats
+	for i := 0; i < 4; i++ {
+		stats = append(stats, &domain.PlayerMatchStats{MinutesPlayed: 90, PassAccuracy: 80, DistanceCovered: 10})
+	}
+	for i := 0; i < 4; i++ {
+		stats = append(stats, &domain.PlayerMatchStats{MinutesPlayed: 90, PassAccuracy: 40, DistanceCovered: 5})
+	}
+
+	change, ok := rollingRatingChange(stats, 4)
+	assert.True(t, ok)
+	assert.InDelta(t, -50, change, 1e-9)
+
+	_, ok = rollingRatingChange(stats, 5)
+	assert.False(t, ok, "needs two full windows")
+
+	rule := &domain.AlertRule{Metric: domain.AlertRollingRatingChange, Operator: domain.AlertBelow, Threshold: -20}
+	assert.True(t, rule.Operator.Compare(change, rule.Threshold))
+}
+
+func TestAlertApplies(t *testing.T) {
+	player := &domain.Player{ID: "p1", TeamID: "t1"}
+
+	assert.True(t, alertApplies(&domain.AlertRule{}, player))
+	assert.True(t, alertApplies(&domain.AlertRule{TeamID: "t1"}, player))
+	assert.False(t, alertApplies(&domain.AlertRule{TeamID: "t2"}, player))
+	assert.False(t, alertApplies(&domain.AlertRule{PlayerID: "p2"}, player))
+	assert.False(t, alertApplies(&domain.AlertRule{Disabled: true}, player))
+}
+
+// memoryAlertRepository keep alerts in memory, refusing a second open alert of a rule for a player
+// like the partial unique index
+type memoryAlertRepository struct {
+	domain.AlertRepository
+	mu     sync.Mutex
+	rules  []*domain.AlertRule
+	alerts []*domain.Alert
+}
+
+func (r *memoryAlertRepository) ListRules() ([]*domain.AlertRule, error) {
+	return r.rules, nil
+}
+
+func (r *memoryAlertRepository) CreateAlert(alert *domain.Alert) error {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	for _, existing := range r.alerts {
+		if existing.State == domain.AlertOpen && existing.RuleID == alert.RuleID && existing.PlayerID == alert.PlayerID {
+			return domain.ErrDuplicate
+		}
+	}
+	r.alerts = append(r.alerts, alert)
+	return nil
+}
+
+func (r *memoryAlertRepository) ListAlerts(filter domain.AlertFilter) ([]*domain.Alert, error) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	var result []*domain.Alert
+	for _, alert := range r.alerts {
+		if (filter.RuleID == "" || alert.RuleID == filter.RuleID) && (filter.PlayerID == "" || alert.PlayerID == filter.PlayerID) {
+			result = append(result, alert)
+		}
+	}
+	return result, nil
+}
+
+// unlistedAlertRepository hide stored alerts from ListAlerts, like an evaluation racing another one
+type unlistedAlertRepository struct {
+	*memoryAlertRepository
+}
+
+func (r unlistedAlertRepository) ListAlerts(filter domain.AlertFilter) ([]*domain.Alert, error) {
+	return nil, nil
+}
+
+// recordingNotifier record the alerts it is notified of, blocking until release is closed, every
+// delivery fails which must not undo the stored alert
+type recordingNotifier struct {
+	name    string
+	release chan struct{}
+	mu      sync.Mutex
+	alerts  []*domain.Alert
+}
+
+func (n *recordingNotifier) Name() string {
+	return n.name
+}
+
+func (n *recordingNotifier) Notify(alert *domain.Alert) error {
+	<-n.release
+	n.mu.Lock()
+	defer n.mu.Unlock()
+	n.alerts = append(n.alerts, alert)
+	return errors.New("mail server down")
+}
+
+func (n *recordingNotifier) notified() []*domain.Alert {
+	n.mu.Lock()
+	defer n.mu.Unlock()
+	return n.alerts
+}
+
+type fixedWorkload struct {
+	WorkloadService
+	ratio float64
+}
+
+func (w fixedWorkload) GetPlayerWorkload(playerID string, date time.Time) (*domain.PlayerWorkload, error) {
+	return &domain.PlayerWorkload{PlayerID: playerID, WorkloadRatio: w.ratio}, nil
+}
+
+// alertFixture create a player with a match and a workload ratio of 1.6, and an alert service
+// with a "log" and a "mail" notifier whose deliveries wait for release
+func alertFixture(alertRepo domain.AlertRepository) (AlertService, *recordingNotifier, *recordingNotifier, chan struct{}) {
+	player := &domain.Player{ID: "p1", Name: "Player One", TeamID: "t1"}
+	players := &memoryPlayerRepository{players: map[string]*domain.Player{"p1": player}}
+	matches := &memoryMatchRepository{matches: []*domain.Match{
+		{ID: "m1", Date: utcDate(2025, 3, 1)},
+		{ID: "m2", Date: utcDate(2025, 3, 8)},
+	}}
+
+	release := make(chan struct{})
+	logNotifier := &recordingNotifier{name: "log", release: release}
+	mailNotifier := &recordingNotifier{name: "mail", release: release}
+	alerts := NewAlertService(alertRepo, nil, players, matches, nil, fixedWorkload{ratio: 1.6}, logNotifier, mailNotifier)
+
+	return alerts, logNotifier, mailNotifier, release
+}
+
+func TestEvaluateMatchTriggersOncePerOpenAlert(t *testing.T) {
+	alertRepo := &memoryAlertRepository{rules: []*domain.AlertRule{
+		{ID: "r1", Name: "overload", Metric: domain.AlertWorkloadRatio, Operator: domain.AlertAbove, Threshold: 1.5, Notifiers: []string{"mail"}},
+		{ID: "r2", Name: "low", Metric: domain.AlertWorkloadRatio, Operator: domain.AlertBelow, Threshold: 0.8},
+		{ID: "r3", Name: "other team", Metric: domain.AlertWorkloadRatio, Operator: domain.AlertAbove, Threshold: 1, TeamID: "t2"},
+	}}
+	alerts, logNotifier, mailNotifier, release := alertFixture(alertRepo)
+
+	triggered, err := alerts.EvaluateMatch("p1", "m1")
+
+	assert.NoError(t, err)
+	if assert.Len(t, triggered, 1) {
+		assert.Equal(t, "r1", triggered[0].RuleID)
+		assert.Equal(t, "m1", triggered[0].MatchID)
+		assert.Equal(t, domain.AlertOpen, triggered[0].State)
+		assert.InDelta(t, 1.6, triggered[0].Value, 1e-9)
+		assert.Equal(t, "Player One: workload ratio 1.60 (workload_ratio > 1.5)", triggered[0].Message)
+	}
+
+	// the open alert holds back the rule for the next match
+	triggered, err = alerts.EvaluateMatch("p1", "m2")
+	assert.NoError(t, err)
+	assert.Empty(t, triggered)
+
+	// once acknowledged the rule triggers again, but not for a match it already alerted on
+	alertRepo.alerts[0].State = domain.AlertAcknowledged
+	triggered, err = alerts.EvaluateMatch("p1", "m1")
+	assert.NoError(t, err)
+	assert.Empty(t, triggered)
+	triggered, err = alerts.EvaluateMatch("p1", "m2")
+	assert.NoError(t, err)
+	assert.Len(t, triggered, 1)
+
+	// deliveries wait in the queue without holding back the evaluation
+	assert.Empty(t, mailNotifier.notified())
+	close(release)
+	alerts.Close()
+	assert.Len(t, mailNotifier.notified(), 2)
+	assert.Empty(t, logNotifier.notified(), "the rule names its notifiers")
+}
+
+func TestEvaluateMatchConcurrentDuplicate(t *testing.T) {
+	// the open alert is not listed yet, the repository refuses the second one
+	alertRepo := &memoryAlertRepository{rules: []*domain.AlertRule{
+		{ID: "r1", Name: "overload", Metric: domain.AlertWorkloadRatio, Operator: domain.AlertAtLeast, Threshold: 1.5},
+	}}
+	alerts, logNotifier, mailNotifier, release := alertFixture(unlistedAlertRepository{alertRepo})
+
+	first, err := alerts.EvaluateMatch("p1", "m1")
+	assert.NoError(t, err)
+	assert.Len(t, first, 1)
+	second, err := alerts.EvaluateMatch("p1", "m2")
+	assert.NoError(t, err)
+	assert.Empty(t, second)
+
+	close(release)
+	alerts.Close()
+	assert.Len(t, alertRepo.alerts, 1)
+	assert.Len(t, logNotifier.notified(), 1)
+	assert.Len(t, mailNotifier.notified(), 1)
+}
+
+func TestEvaluateMatchUnknownMatch(t *testing.T) {
+	alerts, _, _, release := alertFixture(&memoryAlertRepository{})
+	close(release)
+	defer alerts.Close()
+
+	_, err := alerts.EvaluateMatch("p1", "missing")
+
+	assert.ErrorIs(t, err, domain.ErrNotFound)
+}
@@ -0,0 +1,149 @@
+package service
+
+import (
+	"fmt"
+	"football-analytics/internal/domain"
+	"sync"
+	"testing"
+	"time"
+)
+
+// benchmark dataset: 20 teams of 25 players, a double round robin per season over 10 seasons, held by
+// in-memory repositories so the benchmarks measure the service work only, the date range query itself is
+// benchmarked against a database by BenchmarkListByPlayerAndDateRange of the postgres repositories
+const (
+	benchTeams          = 20
+	benchSquad          = 25
+	benchStarters       = 14
+	benchSeasons        = 10
+	benchFirstSeason    = 2014
+	benchComparePlayers = 22
+)
+
+var (
+	benchOnce    sync.Once
+	benchPlayers *memoryPlayerRepository
+	benchMatches *memoryMatchRepository
+	benchStats   *memoryStatsRepository
+)
+
+// benchDataset build the synthetic 10 season dataset once for every benchmark
+func benchDataset() (*memoryPlayerRepository, *memoryMatchRepository, *memoryStatsRepository) {
+	benchOnce.Do(func() {
+		benchPlayers = &memoryPlayerRepository{players: make(map[string]*domain.Player)}
+		benchMatches = &memoryMatchRepository{}
+		benchStats = &memoryStatsRepository{
+			matches:  make(map[string]*domain.Match),
+			byPlayer: make(map[string][]*domain.PlayerMatchStats),
+		}
+
+		for t := 0; t < benchTeams; t++ {
+			for n := 0; n < benchSquad; n++ {
+				player := &domain.Player{ID: fmt.Sprintf("p%d-%d", t, n), TeamID: fmt.Sprintf("t%d", t), Position: "Midfielder"}
+				benchPlayers.players[player.ID] = player
+				benchPlayers.list = append(benchPlayers.list, player)
+			}
+		}
+
+		for season := 0; season < benchSeasons; season++ {
+			date := time.Date(benchFirstSeason+season, 8, 10, 15, 0, 0, 0, time.UTC)
+			for home := 0; home < benchTeams; home++ {
+				for away := 0; away < benchTeams; away++ {
+					if home == away {
+						continue
+					}
+					match := &domain.Match{
+						ID:         fmt.Sprintf("m%d-%d-%d", season, home, away),
+						HomeTeamID: fmt.Sprintf("t%d", home),
+						AwayTeamID: fmt.Sprintf("t%d", away),
+						Date:       date.Add(time.Duration(home*benchTeams+away) * 12 * time.Hour),
+						Status:     "completed",
+					}
+					benchMatches.matches = append(benchMatches.matches, match)
+					benchStats.matches[match.ID] = match
+
+					for _, team := range []int{home, away} {
+						for n := 0; n < benchStarters; n++ {
+							playerID := fmt.Sprintf("p%d-%d", team, (n+season+home)%benchSquad)
+							benchStats.byPlayer[playerID] = append(benchStats.byPlayer[playerID], &domain.PlayerMatchStats{
+								PlayerID:        playerID,
+								MatchID:         match.ID,
+								MinutesPlayed:   90,
+								Goals:           n % 3 / 2,
+								Passes:          40,
+								PassAccuracy:    82,
+								Shots:           2,
+								ShotsOnTarget:   1,
+								Tackles:         2,
+								DistanceCovered: 10.5,
+							})
+						}
+					}
+				}
+			}
+		}
+	})
+
+	return benchPlayers, benchMatches, benchStats
+}
+
+func newBenchService() domain.AnalyticsService {
+	players, matches, stats := benchDataset()
+	return NewAnalyticsService(stats, players, matches, nil, nil, nil, nil, nil)
+}
+
+// legacyPlayerPerformance the way performance was calculated before the date filter moved into the
+// stats repository: every match of the range and the full stats history of the player are loaded
+func legacyPlayerPerformance(matches *memoryMatchRepository, stats *memoryStatsRepository, playerID string, start, end time.Time) *domain.PerformanceMetrics {
+	rangeMatches, _ := matches.ListByDateRange(start, end)
+	matchIDs := make(map[string]bool)
+	for _, match := range rangeMatches {
+		matchIDs[match.ID] = true
+	}
+
+	allStats, _ := stats.ListByPlayerID(playerID)
+	var filtered []*domain.PlayerMatchStats
+	for _, stat := range allStats {
+		if matchIDs[stat.MatchID] {
+			filtered = append(filtered, stat)
+		}
+	}
+
+	return calculateMetricsFromStats(playerID, filtered)
+}
+
+func BenchmarkCalculatePlayerPerformanceAll(b *testing.B) {
+	service := newBenchService()
+	b.ResetTimer()
+
+	for i := 0; i < b.N; i++ {
+		if _, err := service.CalculatePlayerPerformance("p3-7", "all"); err != nil {
+			b.Fatal(err)
+		}
+	}
+}
+
+func BenchmarkCalculatePlayerPerformanceAllLegacy(b *testing.B) {
+	_, matches, stats := benchDataset()
+	start, end := time.Time{}, time.Now()
+	b.ResetTimer()
+
+	for i := 0; i < b.N; i++ {
+		legacyPlayerPerformance(matches, stats, "p3-7", start, end)
+	}
+}
+
+func BenchmarkComparePlayerPerformance(b *testing.B) {
+	service := newBenchService()
+	var playerIDs []string
+	for n := 0; n < benchComparePlayers; n++ {
+		playerIDs = append(playerIDs, fmt.Sprintf("p%d-%d", n%benchTeams, n))
+	}
+	b.ResetTimer()
+
+	for i := 0; i < b.N; i++ {
+		if _, err := service.ComparePlayerPerformance(playerIDs); err != nil {
+			b.Fatal(err)
+		}
+	}
+}
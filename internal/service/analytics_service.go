@@ -2,13 +2,17 @@ package service
 
 import (
 	"football-analytics/internal/domain"
-	"time"
 )
 
 type analyticsService struct {
 	playerStatsRepo domain.PlayerMatchStatsRepository
 	playerRepo      domain.PlayerRepository
 	matchRepo       domain.MatchRepository
+	teamRepo        domain.TeamRepository
+	modelRepo       domain.MatchModelRepository
+	roleRepo        domain.RoleRepository
+	calendar        *seasonCalendar
+	clock           domain.Clock
 }
 
 // NewAnalyticsService create instance of AnalyticsService
@@ -16,67 +20,48 @@ func NewAnalyticsService(
 	playerStatsRepo domain.PlayerMatchStatsRepository,
 	playerRepo domain.PlayerRepository,
 	matchRepo domain.MatchRepository,
+	teamRepo domain.TeamRepository,
+	modelRepo domain.MatchModelRepository,
+	roleRepo domain.RoleRepository,
+	seasonRepo domain.SeasonRepository,
+	clock domain.Clock,
 ) domain.AnalyticsService {
+	if clock == nil {
+		clock = domain.SystemClock{}
+	}
+
 	return &analyticsService{
 		playerStatsRepo: playerStatsRepo,
 		playerRepo:      playerRepo,
 		matchRepo:       matchRepo,
+		teamRepo:        teamRepo,
+		modelRepo:       modelRepo,
+		roleRepo:        roleRepo,
+		calendar:        newSeasonCalendar(seasonRepo),
+		clock:           clock,
 	}
 }
 
-// CalculatePlayerPerformance calculate player performance in a specific time range
+// CalculatePlayerPerformance calculate player performance in a time range written in the
+// syntax of domain.ParseTimeRange
 func (s *analyticsService) CalculatePlayerPerformance(playerID string, timeRange string) (*domain.PerformanceMetrics, error) {
-	// get player data
-	player, err := s.playerRepo.GetByID(playerID)
+	// parse time range
+	tr, err := domain.ParseTimeRange(timeRange)
 	if err != nil {
 		return nil, err
 	}
 
-	// set time range
-	var startDate, endDate time.Time
-	endDate = time.Now()
-
-	switch timeRange {
-	case "week":
-		startDate = endDate.AddDate(0, 0, -7)
-	case "month":
-		startDate = endDate.AddDate(0, -1, 0)
-	case "season":
-		// assume season start in August
-		year := endDate.Year()
-		if endDate.Month() < 8 {
-			year--
-		}
-		startDate = time.Date(year, 8, 1, 0, 0, 0, 0, time.UTC)
-	default: // "all"
-		startDate = time.Date(2000, 1, 1, 0, 0, 0, 0, time.UTC)
-	}
-
-	// get match data in a specific time range
-	matches, err := s.matchRepo.ListByDateRange(startDate, endDate)
-	if err != nil {
+	// check player exists
+	if _, err := s.playerRepo.GetByID(playerID); err != nil {
 		return nil, err
 	}
 
-	// create map of match IDs
-	matchIDs := make(map[string]bool)
-	for _, match := range matches {
-		matchIDs[match.ID] = true
-	}
-
-	// get player stats
-	allStats, err := s.playerStatsRepo.ListByPlayerID(playerID)
+	// get player stats in matches of the time range
+	appearances, _, err := s.playerRange(playerID, tr, s.clock.Now())
 	if err != nil {
 		return nil, err
 	}
-
-	// filter stats in a specific time range
-	var filteredStats []*domain.PlayerMatchStats
-	for _, stat := range allStats {
-		if matchIDs[stat.MatchID] {
-			filteredStats = append(filteredStats, stat)
-		}
-	}
+	filteredStats := appearanceStats(appearances)
 
 	// calculate performance
 	metrics := &domain.PerformanceMetrics{
@@ -123,84 +108,34 @@ func (s *analyticsService) CalculatePlayerPerformance(playerID string, timeRange
 	metrics.Stamina = totalDistance / matchCount
 
 	// calculate overall rating (example calculation)
-	metrics.OverallRating = (
-		metrics.GoalsPerMinute*100 +
-			metrics.AssistsPerMinute*50 +
-			metrics.PassAccuracy*0.3 +
-			metrics.ShotAccuracy*0.2 +
-			metrics.DefensiveEfficiency*0.1 +
-			metrics.Stamina*0.1) / 6
+	metrics.OverallRating = overallRating(metrics)
 
 	return metrics, nil
 }
 
 // ComparePlayerPerformance compare player performance of multiple players
 func (s *analyticsService) ComparePlayerPerformance(playerIDs []string) (map[string]*domain.PerformanceMetrics, error) {
-	result := make(map[string]*domain.PerformanceMetrics)
-
-	for _, playerID := range playerIDs {
-		metrics, err := s.CalculatePlayerPerformance(playerID, "season")
-		if err != nil {
-			return nil, err
-		}
-		result[playerID] = metrics
-	}
-
-	return result, nil
+	return s.batchMetrics(playerIDs)
 }
 
-// GetPlayerProgressOverTime get player progress over time
+// GetPlayerProgressOverTime get player progress over time, both dates are included
 func (s *analyticsService) GetPlayerProgressOverTime(playerID string, startDateStr, endDateStr string) ([]*domain.PerformanceMetrics, error) {
-	// convert date from string to time.Time
-	startDate, err := time.Parse("2006-01-02", startDateStr)
-	if err != nil {
-		return nil, err
-	}
-
-	endDate, err := time.Parse("2006-01-02", endDateStr)
+	// convert dates to a time range
+	tr, err := domain.DateRange(startDateStr, endDateStr)
 	if err != nil {
 		return nil, err
 	}
 
-	// get match data in a specific time range
-	matches, err := s.matchRepo.ListByDateRange(startDate, endDate)
+	// get player appearances in a specific time range, sorted by match date
+	appearances, _, err := s.playerRange(playerID, tr, s.clock.Now())
 	if err != nil {
 		return nil, err
 	}
 
-	// sort matches by date
-	// (in this case, assume matches are sorted by date)
-
-	// get player stats
-	allStats, err := s.playerStatsRepo.ListByPlayerID(playerID)
-	if err != nil {
-		return nil, err
-	}
-
-	// create map of stats by match ID
-	statsMap := make(map[string]*domain.PlayerMatchStats)
-	for _, stat := range allStats {
-		statsMap[stat.MatchID] = stat
-	}
-
-	// create progress data by interval (e.g. every 5 matches)
+	// create progress data by interval of the player's appearances (e.g. every 5 matches)
 	var progressData []*domain.PerformanceMetrics
-	const interval = 5
-	var currentStats []*domain.PlayerMatchStats
-
-	for i, match := range matches {
-		if stat, ok := statsMap[match.ID]; ok {
-			currentStats = append(currentStats, stat)
-		}
-
-		// calculate every interval matches or when reach last match
-		if (i+1)%interval == 0 || i == len(matches)-1 {
-			if len(currentStats) > 0 {
-				// calculate performance from accumulated stats
-				metrics := calculateMetricsFromStats(playerID, currentStats)
-				progressData = append(progressData, metrics)
-			}
-		}
+	for _, point := range blockProgress(playerID, appearances, defaultProgressSize) {
+		progressData = append(progressData, point.Metrics)
 	}
 
 	return progressData, nil
@@ -222,16 +157,23 @@ func (s *analyticsService) GetTeamPerformanceByPosition(teamID string) (map[stri
 		}
 	}
 
-	// calculate player performance in each position
+	// calculate performance of every player of the team in one batch
+	var playerIDs []string
+	for _, ids := range playersByPosition {
+		playerIDs = append(playerIDs, ids...)
+	}
+	metrics, err := s.batchMetrics(playerIDs)
+	if err != nil {
+		return nil, err
+	}
+
 	result := make(map[string][]*domain.PerformanceMetrics)
-	for position, playerIDs := range playersByPosition {
+	for position, ids := range playersByPosition {
 		var positionMetrics []*domain.PerformanceMetrics
-		for _, playerID := range playerIDs {
-			metrics, err := s.CalculatePlayerPerformance(playerID, "season")
-			if err != nil {
-				return nil, err
+		for _, playerID := range ids {
+			if m, ok := metrics[playerID]; ok {
+				positionMetrics = append(positionMetrics, m)
 			}
-			positionMetrics = append(positionMetrics, metrics)
 		}
 		result[position] = positionMetrics
 	}
@@ -285,13 +227,17 @@ func calculateMetricsFromStats(playerID string, stats []*domain.PlayerMatchStats
 	metrics.Stamina = totalDistance / matchCount
 
 	// calculate overall rating
-	metrics.OverallRating = (
-		metrics.GoalsPerMinute*100 +
-			metrics.AssistsPerMinute*50 +
-			metrics.PassAccuracy*0.3 +
-			metrics.ShotAccuracy*0.2 +
-			metrics.DefensiveEfficiency*0.1 +
-			metrics.Stamina*0.1) / 6
+	metrics.OverallRating = overallRating(metrics)
 
 	return metrics
+}
+
+// overallRating calculate overall rating from the other metrics, the mean of the rating components
+// brought to the common scale
+func overallRating(metrics *domain.PerformanceMetrics) float64 {
+	var total float64
+	for _, component := range ratingComponents {
+		total += component.value(metrics) * component.scale
+	}
+	return total / float64(len(ratingComponents))
 } 
\ No newline at end of file
@@ -0,0 +1,253 @@
+package service
+
+import (
+	"football-analytics/internal/domain"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+)
+
+// MockPlayerMatchStatsRepository is mock for PlayerMatchStatsRepository
+type MockPlayerMatchStatsRepository struct {
+	mock.Mock
+}
+
+func (m *MockPlayerMatchStatsRepository) Create(stats *domain.PlayerMatchStats) error {
+	args := m.Called(stats)
+	return args.Error(0)
+}
+
+func (m *MockPlayerMatchStatsRepository) GetByID(id string) (*domain.PlayerMatchStats, error) {
+	args := m.Called(id)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).(*domain.PlayerMatchStats), args.Error(1)
+}
+
+func (m *MockPlayerMatchStatsRepository) Update(stats *domain.PlayerMatchStats) error {
+	args := m.Called(stats)
+	return args.Error(0)
+}
+
+func (m *MockPlayerMatchStatsRepository) Delete(id string) error {
+	args := m.Called(id)
+	return args.Error(0)
+}
+
+func (m *MockPlayerMatchStatsRepository) ListByPlayerID(playerID string) ([]*domain.PlayerMatchStats, error) {
+	args := m.Called(playerID)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).([]*domain.PlayerMatchStats), args.Error(1)
+}
+
+func (m *MockPlayerMatchStatsRepository) ListByMatchID(matchID string) ([]*domain.PlayerMatchStats, error) {
+	args := m.Called(matchID)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).([]*domain.PlayerMatchStats), args.Error(1)
+}
+
+func (m *MockPlayerMatchStatsRepository) ListByMatchIDs(matchIDs []string) ([]*domain.PlayerMatchStats, error) {
+	args := m.Called(matchIDs)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).([]*domain.PlayerMatchStats), args.Error(1)
+}
+
+func (m *MockPlayerMatchStatsRepository) GetPlayerSeasonStats(playerID string, season string) (*domain.PlayerSeasonStats, error) {
+	args := m.Called(playerID, season)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).(*domain.PlayerSeasonStats), args.Error(1)
+}
+
+func (m *MockPlayerMatchStatsRepository) ListByPlayerAndDateRange(playerID string, start, end time.Time) ([]*domain.PlayerAppearance, error) {
+	args := m.Called(playerID, start, end)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).([]*domain.PlayerAppearance), args.Error(1)
+}
+
+func (m *MockPlayerMatchStatsRepository) ListByPlayersAndDateRange(playerIDs []string, start, end time.Time) ([]*domain.PlayerAppearance, error) {
+	args := m.Called(playerIDs, start, end)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).([]*domain.PlayerAppearance), args.Error(1)
+}
+
+// MockMatchRepository is mock for MatchRepository
+type MockMatchRepository struct {
+	mock.Mock
+}
+
+func (m *MockMatchRepository) Create(match *domain.Match) error {
+	args := m.Called(match)
+	return args.Error(0)
+}
+
+func (m *MockMatchRepository) GetByID(id string) (*domain.Match, error) {
+	args := m.Called(id)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).(*domain.Match), args.Error(1)
+}
+
+func (m *MockMatchRepository) Update(match *domain.Match) error {
+	args := m.Called(match)
+	return args.Error(0)
+}
+
+func (m *MockMatchRepository) Delete(id string) error {
+	args := m.Called(id)
+	return args.Error(0)
+}
+
+func (m *MockMatchRepository) List() ([]*domain.Match, error) {
+	args := m.Called()
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).([]*domain.Match), args.Error(1)
+}
+
+func (m *MockMatchRepository) ListByTeamID(teamID string) ([]*domain.Match, error) {
+	args := m.Called(teamID)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).([]*domain.Match), args.Error(1)
+}
+
+func (m *MockMatchRepository) ListByDateRange(start, end time.Time) ([]*domain.Match, error) {
+	args := m.Called(start, end)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).([]*domain.Match), args.Error(1)
+}
+
+// progressFixture create 6 league matches, one a week from 2024-09-02, the player plays in 4 of them
+func progressFixture(playerID string) ([]*domain.Match, []*domain.PlayerMatchStats) {
+	var matches []*domain.Match
+	var stats []*domain.PlayerMatchStats
+	first := time.Date(2024, 9, 2, 15, 0, 0, 0, time.UTC)
+
+	for i := 0; i < 6; i++ {
+		match := &domain.Match{ID: string(rune('a' + i)), Date: first.AddDate(0, 0, 7*i)}
+		matches = append(matches, match)
+		if i == 1 || i == 4 {
+			continue
+		}
+		stats = append(stats, &domain.PlayerMatchStats{
+			PlayerID:      playerID,
+			MatchID:       match.ID,
+			MinutesPlayed: 90,
+			Goals:         i % 2,
+			Shots:         2,
+			ShotsOnTarget: 1,
+			PassAccuracy:  80,
+		})
+	}
+
+	return matches, stats
+}
+
+func newProgressService(playerID string) (domain.AnalyticsService, *MockMatchRepository, *MockPlayerMatchStatsRepository) {
+	matchRepo := new(MockMatchRepository)
+	statsRepo := new(MockPlayerMatchStatsRepository)
+	matches, stats := progressFixture(playerID)
+
+	statsRepo.On("ListByPlayerAndDateRange", playerID, mock.Anything, mock.Anything).Return(joinFixture(matches, stats), nil)
+
+	return NewAnalyticsService(statsRepo, new(MockPlayerRepository), matchRepo, nil, nil, nil, nil, nil), matchRepo, statsRepo
+}
+
+// joinFixture join fixture stats with their match like the stats repository does
+func joinFixture(matches []*domain.Match, stats []*domain.PlayerMatchStats) []*domain.PlayerAppearance {
+	var rows []*domain.PlayerAppearance
+	for _, app := range joinAppearances(matches, stats) {
+		rows = append(rows, &domain.PlayerAppearance{Match: app.match, Stats: app.stats})
+	}
+	return rows
+}
+
+func TestGetPlayerProgressSeriesRolling(t *testing.T) {
+	service, matchRepo, statsRepo := newProgressService("p1")
+
+	points, err := service.GetPlayerProgressSeries("p1", "2024-08-01", "2024-12-31", domain.ProgressOptions{
+		Window: domain.ProgressRolling,
+		Size:   3,
+	})
+
+	assert.NoError(t, err)
+	assert.Len(t, points, 2)
+	for _, point := range points {
+		assert.Equal(t, 3, point.Appearances)
+	}
+	assert.Equal(t, time.Date(2024, 9, 2, 15, 0, 0, 0, time.UTC), points[0].WindowStart)
+	assert.Equal(t, time.Date(2024, 9, 23, 15, 0, 0, 0, time.UTC), points[0].WindowEnd)
+	assert.Equal(t, time.Date(2024, 10, 7, 15, 0, 0, 0, time.UTC), points[1].WindowEnd)
+
+	matchRepo.AssertExpectations(t)
+	statsRepo.AssertExpectations(t)
+}
+
+func TestGetPlayerProgressSeriesMonth(t *testing.T) {
+	service, _, _ := newProgressService("p1")
+
+	points, err := service.GetPlayerProgressSeries("p1", "2024-08-01", "2024-12-31", domain.ProgressOptions{
+		Window: domain.ProgressMonth,
+	})
+
+	assert.NoError(t, err)
+	assert.Len(t, points, 2)
+	assert.Equal(t, time.Date(2024, 9, 1, 0, 0, 0, 0, time.UTC), points[0].WindowStart)
+	assert.Equal(t, time.Date(2024, 10, 1, 0, 0, 0, 0, time.UTC), points[0].WindowEnd)
+	assert.Equal(t, 3, points[0].Appearances)
+	assert.Equal(t, 1, points[1].Appearances)
+}
+
+func TestGetPlayerProgressSeriesEWMA(t *testing.T) {
+	service, _, _ := newProgressService("p1")
+
+	points, err := service.GetPlayerProgressSeries("p1", "2024-08-01", "2024-12-31", domain.ProgressOptions{
+		Window: domain.ProgressEWMA,
+		Alpha:  0.5,
+	})
+
+	assert.NoError(t, err)
+	assert.Len(t, points, 4)
+	assert.Equal(t, 4, points[3].Appearances)
+	// goals per played match are 0, 0, 1, 1
+	assert.InDelta(t, 0, points[1].Metrics.GoalsPerMinute, 1e-9)
+	assert.InDelta(t, 0.5/90, points[2].Metrics.GoalsPerMinute, 1e-9)
+	assert.InDelta(t, 0.75/90, points[3].Metrics.GoalsPerMinute, 1e-9)
+}
+
+func TestGetPlayerProgressSeriesInvalidWindow(t *testing.T) {
+	service, _, _ := newProgressService("p1")
+
+	_, err := service.GetPlayerProgressSeries("p1", "2024-08-01", "2024-12-31", domain.ProgressOptions{Window: "fortnight"})
+	assert.Error(t, err)
+}
+
+func TestGetPlayerProgressOverTimeUsesAppearances(t *testing.T) {
+	service, _, _ := newProgressService("p1")
+
+	progress, err := service.GetPlayerProgressOverTime("p1", "2024-08-01", "2024-12-31")
+
+	assert.NoError(t, err)
+	// 4 appearances fit in a single block of 5
+	assert.Len(t, progress, 1)
+}
@@ -0,0 +1,324 @@
+package service
+
+import (
+	"errors"
+	"fmt"
+	"football-analytics/internal/domain"
+	"math"
+	"sort"
+	"time"
+)
+
+const (
+	calibrationBuckets = 10
+	minProbability     = 1e-15
+)
+
+// BacktestService is interface for walk-forward evaluation of match outcome models
+type BacktestService interface {
+	Run(opts domain.BacktestOptions, predictors ...domain.MatchPredictor) (*domain.BacktestReport, error)
+}
+
+type backtestService struct {
+	matchRepo domain.MatchRepository
+}
+
+// NewBacktestService create instance of BacktestService
+func NewBacktestService(matchRepo domain.MatchRepository) BacktestService {
+	return &backtestService{
+		matchRepo: matchRepo,
+	}
+}
+
+// backtestScore running totals of one predictor
+type backtestScore struct {
+	matches int
+	logLoss float64
+	brier   float64
+	correct int
+	buckets [calibrationBuckets]struct {
+		count, hits int
+		predicted   float64
+	}
+}
+
+// Run walk forward over the completed matches in the range, one matchday at a time every predictor
+// is refitted on the matches completed before that day and then predicts the matches of the day,
+// saved model versions take part through NewStoredModelPredictor
+func (s *backtestService) Run(opts domain.BacktestOptions, predictors ...domain.MatchPredictor) (*domain.BacktestReport, error) {
+	if len(predictors) == 0 {
+		return nil, errors.New("no predictors to backtest")
+	}
+	if !opts.StartDate.Before(opts.EndDate) {
+		return nil, fmt.Errorf("invalid backtest range %s - %s", opts.StartDate.Format("2006-01-02"), opts.EndDate.Format("2006-01-02"))
+	}
+
+	allMatches, err := s.matchRepo.List()
+	if err != nil {
+		return nil, err
+	}
+
+	var matches []*domain.Match
+	for _, match := range allMatches {
+		if match.Status != "completed" {
+			continue
+		}
+		if opts.Competition != "" && match.Competition != opts.Competition {
+			continue
+		}
+		matches = append(matches, match)
+	}
+	sort.SliceStable(matches, func(i, j int) bool {
+		return matches[i].Date.Before(matches[j].Date)
+	})
+
+	// group the matches to predict by matchday
+	var matchdays []time.Time
+	byMatchday := make(map[time.Time][]*domain.Match)
+	for _, match := range matches {
+		if match.Date.Before(opts.StartDate) || !match.Date.Before(opts.EndDate) {
+			continue
+		}
+		d := match.Date.UTC()
+		day := time.Date(d.Year(), d.Month(), d.Day(), 0, 0, 0, 0, time.UTC)
+		if _, ok := byMatchday[day]; !ok {
+			matchdays = append(matchdays, day)
+		}
+		byMatchday[day] = append(byMatchday[day], match)
+	}
+
+	scores := make([]*backtestScore, len(predictors))
+	for i := range scores {
+		scores[i] = &backtestScore{}
+	}
+
+	for _, day := range matchdays {
+		// only matches played before the matchday are known when predicting it
+		var history []*domain.Match
+		for _, match := range matches {
+			if !match.Date.Before(day) {
+				break
+			}
+			history = append(history, match)
+		}
+
+		for i, predictor := range predictors {
+			if err := predictor.Fit(history, day); err != nil {
+				return nil, fmt.Errorf("fit %s at %s: %w", predictor.Name(), day.Format("2006-01-02"), err)
+			}
+
+			for _, match := range byMatchday[day] {
+				prediction, err := predictor.Predict(match.HomeTeamID, match.AwayTeamID)
+				if err != nil {
+					return nil, fmt.Errorf("predict %s with %s: %w", match.ID, predictor.Name(), err)
+				}
+				scores[i].add(prediction, match)
+			}
+		}
+	}
+
+	report := &domain.BacktestReport{
+		StartDate:   opts.StartDate,
+		EndDate:     opts.EndDate,
+		Competition: opts.Competition,
+		CreatedAt:   time.Now(),
+	}
+	for i, predictor := range predictors {
+		result := scores[i].result(predictor.Name())
+		result.Matchdays = len(matchdays)
+		report.Results = append(report.Results, result)
+	}
+
+	sort.SliceStable(report.Results, func(i, j int) bool {
+		return report.Results[i].LogLoss < report.Results[j].LogLoss
+	})
+
+	return report, nil
+}
+
+// add score one prediction against the actual result
+func (b *backtestScore) add(prediction *domain.MatchPrediction, match *domain.Match) {
+	probabilities := []float64{prediction.HomeWin, prediction.Draw, prediction.AwayWin}
+	actual := 1
+	switch {
+	case match.HomeScore > match.AwayScore:
+		actual = 0
+	case match.HomeScore < match.AwayScore:
+		actual = 2
+	}
+
+	b.matches++
+	b.logLoss -= math.Log(math.Max(probabilities[actual], minProbability))
+
+	best := 0
+	for k, p := range probabilities {
+		observed := 0.0
+		if k == actual {
+			observed = 1
+		}
+		b.brier += (p - observed) * (p - observed)
+
+		if p > probabilities[best] {
+			best = k
+		}
+
+		bucket := int(p * calibrationBuckets)
+		if bucket >= calibrationBuckets {
+			bucket = calibrationBuckets - 1
+		}
+		if bucket < 0 {
+			bucket = 0
+		}
+		b.buckets[bucket].count++
+		b.buckets[bucket].predicted += p
+		if k == actual {
+			b.buckets[bucket].hits++
+		}
+	}
+
+	if best == actual {
+		b.correct++
+	}
+}
+
+// result averaged scores of the predictor
+func (b *backtestScore) result(model string) *domain.BacktestResult {
+	result := &domain.BacktestResult{
+		Model:   model,
+		Matches: b.matches,
+	}
+
+	if b.matches > 0 {
+		n := float64(b.matches)
+		result.LogLoss = b.logLoss / n
+		result.BrierScore = b.brier / n
+		result.Accuracy = float64(b.correct) / n
+	}
+
+	for i, bucket := range b.buckets {
+		calibration := &domain.CalibrationBucket{
+			Lower:       float64(i) / calibrationBuckets,
+			Upper:       float64(i+1) / calibrationBuckets,
+			Predictions: bucket.count,
+		}
+		if bucket.count > 0 {
+			calibration.MeanPredicted = bucket.predicted / float64(bucket.count)
+			calibration.ObservedRate = float64(bucket.hits) / float64(bucket.count)
+		}
+		result.Calibration = append(result.Calibration, calibration)
+	}
+
+	return result
+}
+
+type dixonColesPredictor struct {
+	opts  domain.MatchModelOptions
+	model *domain.MatchModel
+}
+
+// NewDixonColesPredictor create a MatchPredictor fitting a Dixon-Coles model with the given options
+func NewDixonColesPredictor(opts domain.MatchModelOptions) domain.MatchPredictor {
+	if opts.DecayRate == 0 {
+		opts.DecayRate = defaultDecayRate
+	}
+	return &dixonColesPredictor{opts: opts}
+}
+
+func (p *dixonColesPredictor) Name() string {
+	return fmt.Sprintf("dixon-coles(decay=%g)", p.opts.DecayRate)
+}
+
+func (p *dixonColesPredictor) Fit(history []*domain.Match, asOf time.Time) error {
+	p.model = fitDixonColes(history, asOf, p.opts.DecayRate)
+	p.model.Version = p.Name()
+	return nil
+}
+
+func (p *dixonColesPredictor) Predict(homeTeamID, awayTeamID string) (*domain.MatchPrediction, error) {
+	if p.model == nil {
+		return nil, errors.New("predictor is not fitted")
+	}
+	return predictWithModel(p.model, homeTeamID, awayTeamID), nil
+}
+
+type storedModelPredictor struct {
+	modelRepo domain.MatchModelRepository
+	version   string
+	model     *domain.MatchModel
+}
+
+// NewStoredModelPredictor create a MatchPredictor of a saved model version, the model is loaded on the
+// first fit and never refitted, fitting it before the date it was trained until is an error since
+// it would predict matches it was trained on
+func NewStoredModelPredictor(modelRepo domain.MatchModelRepository, version string) domain.MatchPredictor {
+	return &storedModelPredictor{modelRepo: modelRepo, version: version}
+}
+
+func (p *storedModelPredictor) Name() string {
+	return "model:" + p.version
+}
+
+func (p *storedModelPredictor) Fit(history []*domain.Match, asOf time.Time) error {
+	if p.model == nil {
+		model, err := p.modelRepo.GetByVersion(p.version)
+		if err != nil {
+			return err
+		}
+		p.model = model
+	}
+	if asOf.Before(p.model.TrainedUntil) {
+		return fmt.Errorf("model %s is trained until %s", p.version, p.model.TrainedUntil.Format("2006-01-02"))
+	}
+	return nil
+}
+
+func (p *storedModelPredictor) Predict(homeTeamID, awayTeamID string) (*domain.MatchPrediction, error) {
+	if p.model == nil {
+		return nil, errors.New("predictor is not fitted")
+	}
+	return predictWithModel(p.model, homeTeamID, awayTeamID), nil
+}
+
+type baseRatePredictor struct {
+	homeWin, draw, awayWin float64
+}
+
+// NewBaseRatePredictor create a MatchPredictor that predicts the historical home win, draw and
+// away win rates for every match, the baseline other models must beat
+func NewBaseRatePredictor() domain.MatchPredictor {
+	return &baseRatePredictor{}
+}
+
+func (p *baseRatePredictor) Name() string {
+	return "base-rate"
+}
+
+func (p *baseRatePredictor) Fit(history []*domain.Match, asOf time.Time) error {
+	// start from one pseudo match of each outcome so early matchdays are not certain
+	homeWins, draws, awayWins := 1.0, 1.0, 1.0
+	for _, match := range history {
+		switch {
+		case match.HomeScore > match.AwayScore:
+			homeWins++
+		case match.HomeScore < match.AwayScore:
+			awayWins++
+		default:
+			draws++
+		}
+	}
+
+	total := homeWins + draws + awayWins
+	p.homeWin, p.draw, p.awayWin = homeWins/total, draws/total, awayWins/total
+	return nil
+}
+
+func (p *baseRatePredictor) Predict(homeTeamID, awayTeamID string) (*domain.MatchPrediction, error) {
+	return &domain.MatchPrediction{
+		HomeTeamID:   homeTeamID,
+		AwayTeamID:   awayTeamID,
+		ModelVersion: p.Name(),
+		HomeWin:      p.homeWin,
+		Draw:         p.draw,
+		AwayWin:      p.awayWin,
+	}, nil
+}
@@ -0,0 +1,131 @@
+package service
+
+import (
+	"football-analytics/internal/domain"
+	"math"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+)
+
+// recordingPredictor predict fixed probabilities and record what it was fitted on and asked to predict
+type recordingPredictor struct {
+	fits      []time.Time
+	histories [][]string
+	predicted [][]string // home team of each predicted match, per fit
+}
+
+func (p *recordingPredictor) Name() string {
+	return "fixed"
+}
+
+func (p *recordingPredictor) Fit(history []*domain.Match, asOf time.Time) error {
+	var ids []string
+	for _, match := range history {
+		ids = append(ids, match.ID)
+	}
+	p.fits = append(p.fits, asOf)
+	p.histories = append(p.histories, ids)
+	p.predicted = append(p.predicted, nil)
+	return nil
+}
+
+func (p *recordingPredictor) Predict(homeTeamID, awayTeamID string) (*domain.MatchPrediction, error) {
+	p.predicted[len(p.predicted)-1] = append(p.predicted[len(p.predicted)-1], homeTeamID)
+	return &domain.MatchPrediction{HomeTeamID: homeTeamID, AwayTeamID: awayTeamID, HomeWin: 0.5, Draw: 0.3, AwayWin: 0.2}, nil
+}
+
+// newBacktestFixture league and cup matches around a September 2024 backtest
+func newBacktestFixture() *listingMatchRepository {
+	// every home team is named after its match
+	match := func(id, competition string, date time.Time, homeScore, awayScore int, status string) *domain.Match {
+		return &domain.Match{ID: id, HomeTeamID: id, AwayTeamID: "away", Competition: competition, Date: date, HomeScore: homeScore, AwayScore: awayScore, Status: status}
+	}
+	return &listingMatchRepository{memoryMatchRepository: memoryMatchRepository{matches: []*domain.Match{
+		match("e", "League", utcDate(2024, 9, 29), 1, 0, "completed"),
+		match("d", "League", utcDate(2024, 9, 15), 2, 0, "completed"),
+		match("b", "League", utcDate(2024, 9, 1).Add(18*time.Hour), 1, 1, "completed"),
+		match("a", "League", utcDate(2024, 9, 1).Add(15*time.Hour), 2, 1, "completed"),
+		match("h", "League", utcDate(2024, 8, 25), 0, 0, "completed"),
+		match("x", "Cup", utcDate(2024, 9, 5), 3, 0, "completed"),
+		match("c", "League", utcDate(2024, 9, 8), 0, 1, "completed"),
+		match("s", "League", utcDate(2024, 9, 22), 0, 0, "scheduled"),
+	}}}
+}
+
+func TestBacktestFoldBoundaries(t *testing.T) {
+	service := NewBacktestService(newBacktestFixture())
+	predictor := &recordingPredictor{}
+
+	report, err := service.Run(domain.BacktestOptions{StartDate: utcDate(2024, 9, 1), EndDate: utcDate(2024, 9, 29), Competition: "League"}, predictor)
+	assert.NoError(t, err)
+
+	// one fold per matchday, each fitted only on the matches completed before it
+	assert.Equal(t, []time.Time{utcDate(2024, 9, 1), utcDate(2024, 9, 8), utcDate(2024, 9, 15)}, predictor.fits)
+	assert.Equal(t, [][]string{{"h"}, {"h", "a", "b"}, {"h", "a", "b", "c"}}, predictor.histories)
+	assert.Equal(t, [][]string{{"a", "b"}, {"c"}, {"d"}}, predictor.predicted)
+
+	assert.Len(t, report.Results, 1)
+	assert.Equal(t, 4, report.Results[0].Matches)
+	assert.Equal(t, 3, report.Results[0].Matchdays)
+}
+
+func TestBacktestScores(t *testing.T) {
+	service := NewBacktestService(newBacktestFixture())
+
+	report, err := service.Run(domain.BacktestOptions{StartDate: utcDate(2024, 9, 1), EndDate: utcDate(2024, 9, 29), Competition: "League"}, &recordingPredictor{}, NewBaseRatePredictor())
+	assert.NoError(t, err)
+	assert.Len(t, report.Results, 2)
+	assert.LessOrEqual(t, report.Results[0].LogLoss, report.Results[1].LogLoss)
+
+	var fixed *domain.BacktestResult
+	for _, result := range report.Results {
+		if result.Model == "fixed" {
+			fixed = result
+		}
+	}
+
+	// outcomes: home win, draw, away win, home win
+	assert.InDelta(t, -(2*math.Log(0.5)+math.Log(0.3)+math.Log(0.2))/4, fixed.LogLoss, 1e-9)
+	assert.InDelta(t, (0.38+0.78+0.98+0.38)/4, fixed.BrierScore, 1e-9)
+	assert.InDelta(t, 0.5, fixed.Accuracy, 1e-9)
+
+	assert.Len(t, fixed.Calibration, calibrationBuckets)
+	assert.Equal(t, 4, fixed.Calibration[5].Predictions)
+	assert.InDelta(t, 0.5, fixed.Calibration[5].MeanPredicted, 1e-9)
+	assert.InDelta(t, 0.5, fixed.Calibration[5].ObservedRate, 1e-9)
+	assert.Equal(t, 4, fixed.Calibration[2].Predictions)
+	assert.InDelta(t, 0.25, fixed.Calibration[2].ObservedRate, 1e-9)
+}
+
+func TestBacktestStoredModel(t *testing.T) {
+	modelRepo := &memoryModelRepository{models: []*domain.MatchModel{
+		{Version: "v1", Strengths: map[string]*domain.TeamStrength{"d": {TeamID: "d", Attack: 2, Defence: 0.5}}, TrainedUntil: utcDate(2024, 9, 8)},
+	}}
+	service := NewBacktestService(newBacktestFixture())
+
+	report, err := service.Run(domain.BacktestOptions{StartDate: utcDate(2024, 9, 8), EndDate: utcDate(2024, 9, 29), Competition: "League"}, NewStoredModelPredictor(modelRepo, "v1"))
+	assert.NoError(t, err)
+	if assert.Len(t, report.Results, 1) {
+		assert.Equal(t, "model:v1", report.Results[0].Model)
+		assert.Equal(t, 2, report.Results[0].Matches)
+	}
+
+	// the model has seen the matches before the 8th
+	_, err = service.Run(domain.BacktestOptions{StartDate: utcDate(2024, 9, 1), EndDate: utcDate(2024, 9, 29), Competition: "League"}, NewStoredModelPredictor(modelRepo, "v1"))
+	assert.EqualError(t, err, "fit model:v1 at 2024-09-01: model v1 is trained until 2024-09-08")
+
+	_, err = service.Run(domain.BacktestOptions{StartDate: utcDate(2024, 9, 8), EndDate: utcDate(2024, 9, 29)}, NewStoredModelPredictor(modelRepo, "v2"))
+	assert.ErrorIs(t, err, domain.ErrNotFound)
+}
+
+func TestBacktestInvalidOptions(t *testing.T) {
+	service := NewBacktestService(newBacktestFixture())
+
+	_, err := service.Run(domain.BacktestOptions{StartDate: utcDate(2024, 9, 1), EndDate: utcDate(2024, 9, 29)})
+	assert.EqualError(t, err, "no predictors to backtest")
+
+	_, err = service.Run(domain.BacktestOptions{StartDate: utcDate(2024, 9, 29), EndDate: utcDate(2024, 9, 1)}, NewBaseRatePredictor())
+	assert.EqualError(t, err, "invalid backtest range 2024-09-29 - 2024-09-01")
+}
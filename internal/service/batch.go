@@ -0,0 +1,156 @@
+package service
+
+import (
+	"context"
+	"fmt"
+	"football-analytics/internal/domain"
+	"sort"
+	"sync"
+)
+
+const defaultBatchWorkers = 8
+
+// CalculatePlayersPerformance calculate performance of many players concurrently, the appearances of
+// the whole batch are fetched in one query and a failing player does not stop the others,
+// players not processed when ctx is cancelled are reported as failures and the context error is returned
+func (s *analyticsService) CalculatePlayersPerformance(ctx context.Context, playerIDs []string, opts domain.BatchOptions) (*domain.BatchResult, error) {
+	timeRange := opts.TimeRange
+	if timeRange == "" {
+		timeRange = "season"
+	}
+	workers := opts.Workers
+	if workers == 0 {
+		workers = defaultBatchWorkers
+	}
+	if workers < 0 {
+		return nil, fmt.Errorf("invalid worker count %d", opts.Workers)
+	}
+	if workers > len(playerIDs) {
+		workers = len(playerIDs)
+	}
+
+	tr, err := domain.ParseTimeRange(timeRange)
+	if err != nil {
+		return nil, err
+	}
+
+	// one calendar read resolves the range of every player
+	calendar, err := s.calendar.memoized()
+	if err != nil {
+		return nil, err
+	}
+	now := s.clock.Now()
+	startDate, endDate, err := calendar.span(tr, now)
+	if err != nil {
+		return nil, err
+	}
+
+	rows, err := s.playerStatsRepo.ListByPlayersAndDateRange(playerIDs, startDate, endDate)
+	if err != nil {
+		return nil, err
+	}
+	rowsByPlayer := make(map[string][]*domain.PlayerAppearance)
+	for _, row := range rows {
+		rowsByPlayer[row.Stats.PlayerID] = append(rowsByPlayer[row.Stats.PlayerID], row)
+	}
+
+	statsByPlayer := make(map[string][]*domain.PlayerMatchStats)
+	for playerID, rows := range rowsByPlayer {
+		window, err := calendar.playerWindow(tr, rows, now)
+		if err != nil {
+			return nil, err
+		}
+		for _, row := range window.filter(rows) {
+			statsByPlayer[playerID] = append(statsByPlayer[playerID], row.Stats)
+		}
+	}
+
+	type outcome struct {
+		playerID string
+		metrics  *domain.PerformanceMetrics
+		err      error
+	}
+
+	jobs := make(chan string)
+	outcomes := make(chan outcome)
+	var wg sync.WaitGroup
+	for i := 0; i < workers; i++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			for playerID := range jobs {
+				metrics, err := s.metricsFromStats(playerID, statsByPlayer[playerID])
+				outcomes <- outcome{playerID: playerID, metrics: metrics, err: err}
+			}
+		}()
+	}
+
+	go func() {
+		defer close(jobs)
+		for _, playerID := range playerIDs {
+			select {
+			case jobs <- playerID:
+			case <-ctx.Done():
+				return
+			}
+		}
+	}()
+
+	go func() {
+		wg.Wait()
+		close(outcomes)
+	}()
+
+	result := &domain.BatchResult{Metrics: make(map[string]*domain.PerformanceMetrics)}
+	done := make(map[string]bool)
+	for o := range outcomes {
+		done[o.playerID] = true
+		if o.err != nil {
+			result.Failures = append(result.Failures, &domain.BatchFailure{PlayerID: o.playerID, Err: o.err, Message: o.err.Error()})
+			continue
+		}
+		result.Metrics[o.playerID] = o.metrics
+	}
+
+	if err := ctx.Err(); err != nil {
+		for _, playerID := range playerIDs {
+			if !done[playerID] {
+				result.Failures = append(result.Failures, &domain.BatchFailure{PlayerID: playerID, Err: err, Message: err.Error()})
+			}
+		}
+	}
+
+	// failures in request order, whatever order the workers finished in
+	order := make(map[string]int)
+	for i, playerID := range playerIDs {
+		order[playerID] = i
+	}
+	sort.SliceStable(result.Failures, func(i, j int) bool {
+		return order[result.Failures[i].PlayerID] < order[result.Failures[j].PlayerID]
+	})
+
+	return result, ctx.Err()
+}
+
+// metricsFromStats calculate performance of an existing player from prefetched stats
+func (s *analyticsService) metricsFromStats(playerID string, stats []*domain.PlayerMatchStats) (*domain.PerformanceMetrics, error) {
+	if _, err := s.playerRepo.GetByID(playerID); err != nil {
+		return nil, err
+	}
+
+	return calculateMetricsFromStats(playerID, stats), nil
+}
+
+// batchMetrics run a season batch and return the metrics of every player, or the error of the first
+// failed player in request order
+func (s *analyticsService) batchMetrics(playerIDs []string) (map[string]*domain.PerformanceMetrics, error) {
+	result, err := s.CalculatePlayersPerformance(context.Background(), playerIDs, domain.BatchOptions{})
+	if err != nil {
+		return nil, err
+	}
+	if len(result.Failures) > 0 {
+		return nil, result.Failures[0].Err
+	}
+
+	return result.Metrics, nil
+}
@@ -0,0 +1,64 @@
+package service
+
+import (
+	"context"
+	"errors"
+	"football-analytics/internal/domain"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+)
+
+func newBatchService() (domain.AnalyticsService, *MockPlayerMatchStatsRepository) {
+	matchRepo := new(MockMatchRepository)
+	statsRepo := new(MockPlayerMatchStatsRepository)
+	playerRepo := new(MockPlayerRepository)
+	matches, stats := progressFixture("p1")
+
+	for _, id := range []string{"p1", "p2"} {
+		playerRepo.On("GetByID", id).Return(&domain.Player{ID: id, TeamID: "t1", Position: "Forward"}, nil)
+	}
+	playerRepo.On("GetByID", "missing").Return(nil, errors.New("player not found"))
+	playerRepo.On("GetByID", "p3").Return(nil, errors.New("connection reset"))
+	statsRepo.On("ListByPlayersAndDateRange", mock.Anything, mock.Anything, mock.Anything).Return(joinFixture(matches, stats), nil)
+
+	return NewAnalyticsService(statsRepo, playerRepo, matchRepo, nil, nil, nil, nil, nil), statsRepo
+}
+
+func TestCalculatePlayersPerformancePartialFailure(t *testing.T) {
+	service, statsRepo := newBatchService()
+
+	result, err := service.CalculatePlayersPerformance(context.Background(), []string{"p1", "missing", "p2", "p3"}, domain.BatchOptions{TimeRange: "all", Workers: 2})
+
+	assert.NoError(t, err)
+	assert.Len(t, result.Metrics, 2)
+	assert.Greater(t, result.Metrics["p1"].GoalsPerMinute, 0.0)
+	assert.Equal(t, 0.0, result.Metrics["p2"].OverallRating)
+	if assert.Len(t, result.Failures, 2) {
+		assert.Equal(t, "missing", result.Failures[0].PlayerID)
+		assert.Equal(t, "p3", result.Failures[1].PlayerID)
+	}
+	// appearances are fetched once for the whole batch
+	statsRepo.AssertNumberOfCalls(t, "ListByPlayersAndDateRange", 1)
+}
+
+func TestComparePlayerPerformanceFailsOnFirstError(t *testing.T) {
+	service, _ := newBatchService()
+
+	metrics, err := service.ComparePlayerPerformance([]string{"p1", "p3", "missing"})
+
+	assert.EqualError(t, err, "connection reset")
+	assert.Nil(t, metrics)
+}
+
+func TestCalculatePlayersPerformanceCancelled(t *testing.T) {
+	service, _ := newBatchService()
+	ctx, cancel := context.WithCancel(context.Background())
+	cancel()
+
+	result, err := service.CalculatePlayersPerformance(ctx, []string{"p1", "p2"}, domain.BatchOptions{Workers: 1})
+
+	assert.ErrorIs(t, err, context.Canceled)
+	assert.Equal(t, len(result.Metrics)+len(result.Failures), 2)
+}
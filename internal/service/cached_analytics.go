@@ -0,0 +1,271 @@
+package service
+
+import (
+	"fmt"
+	"football-analytics/internal/domain"
+	"sync"
+	"time"
+)
+
+// analyticsModelVersion version of the metric formulas, part of every cache key so results of
+// an older formula are never served and stored with every metrics snapshot, bump it whenever
+// a formula changes
+const analyticsModelVersion = "1"
+
+const defaultCacheTTL = 10 * time.Minute
+
+// maxCacheEntries entries kept at most, the entries expiring first make room for new ones
+const maxCacheEntries = 10000
+
+// CachedAnalyticsService is an AnalyticsService caching player results, it observes stats and
+// match writes to drop the entries they affect
+type CachedAnalyticsService interface {
+	domain.AnalyticsService
+	domain.StatsObserver
+	domain.MatchObserver
+}
+
+type cacheEntry struct {
+	playerID string
+	start    time.Time
+	end      time.Time
+	value    interface{}
+	expires  time.Time
+}
+
+// cacheCall is a computation in flight, concurrent identical requests wait for it instead of computing again
+type cacheCall struct {
+	done  chan struct{}
+	value interface{}
+	err   error
+}
+
+type cachedAnalyticsService struct {
+	domain.AnalyticsService
+	matchRepo domain.MatchRepository
+	calendar  *seasonCalendar
+	ttl       time.Duration
+	clock     domain.Clock
+
+	mu         sync.Mutex
+	entries    map[string]*cacheEntry
+	calls      map[string]*cacheCall
+	generation uint64    // incremented by every invalidation
+	nextSweep  time.Time // expired entries are dropped by the first write after it
+}
+
+// NewCachedAnalyticsService create a cache in front of an AnalyticsService, entries live for ttl
+// (default 10 minutes) unless a stats or match write in their date range drops them sooner,
+// register it as observer of the observed stats and match repositories for invalidation, expiry
+// and relative ranges are read from clock, the system clock if nil
+func NewCachedAnalyticsService(
+	analytics domain.AnalyticsService,
+	matchRepo domain.MatchRepository,
+	seasonRepo domain.SeasonRepository,
+	ttl time.Duration,
+	clock domain.Clock,
+) CachedAnalyticsService {
+	if ttl <= 0 {
+		ttl = defaultCacheTTL
+	}
+	if clock == nil {
+		clock = domain.SystemClock{}
+	}
+
+	return &cachedAnalyticsService{
+		AnalyticsService: analytics,
+		matchRepo:        matchRepo,
+		calendar:         newSeasonCalendar(seasonRepo),
+		ttl:              ttl,
+		clock:            clock,
+		entries:          make(map[string]*cacheEntry),
+		calls:            make(map[string]*cacheCall),
+	}
+}
+
+func (c *cachedAnalyticsService) CalculatePlayerPerformance(playerID string, timeRange string) (*domain.PerformanceMetrics, error) {
+	// the span holds every match the range may select for the player, and the ones deciding which
+	tr, err := domain.ParseTimeRange(timeRange)
+	if err != nil {
+		return nil, err
+	}
+	start, end, err := c.calendar.span(tr, c.clock.Now())
+	if err != nil {
+		return c.AnalyticsService.CalculatePlayerPerformance(playerID, timeRange)
+	}
+	key := c.key("performance", playerID, tr.String())
+
+	value, err := c.load(key, playerID, start, end, func() (interface{}, error) {
+		return c.AnalyticsService.CalculatePlayerPerformance(playerID, timeRange)
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	return value.(*domain.PerformanceMetrics), nil
+}
+
+func (c *cachedAnalyticsService) GetPlayerProgressOverTime(playerID string, startDate, endDate string) ([]*domain.PerformanceMetrics, error) {
+	start, end, err := c.dateRange(startDate, endDate)
+	if err != nil {
+		return c.AnalyticsService.GetPlayerProgressOverTime(playerID, startDate, endDate)
+	}
+	key := c.key("progress", playerID, startDate, endDate)
+
+	value, err := c.load(key, playerID, start, end, func() (interface{}, error) {
+		return c.AnalyticsService.GetPlayerProgressOverTime(playerID, startDate, endDate)
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	return value.([]*domain.PerformanceMetrics), nil
+}
+
+func (c *cachedAnalyticsService) GetPlayerProgressSeries(playerID string, startDate, endDate string, opts domain.ProgressOptions) ([]*domain.ProgressPoint, error) {
+	start, end, err := c.dateRange(startDate, endDate)
+	if err != nil {
+		return c.AnalyticsService.GetPlayerProgressSeries(playerID, startDate, endDate, opts)
+	}
+	key := c.key("series", playerID, startDate, endDate, string(opts.Window), fmt.Sprint(opts.Size), fmt.Sprint(opts.Alpha))
+
+	value, err := c.load(key, playerID, start, end, func() (interface{}, error) {
+		return c.AnalyticsService.GetPlayerProgressSeries(playerID, startDate, endDate, opts)
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	return value.([]*domain.ProgressPoint), nil
+}
+
+// StatsChanged drop the entries of the player whose range contains the date of the stats' match,
+// for the row before and after the write
+func (c *cachedAnalyticsService) StatsChanged(old, new *domain.PlayerMatchStats) {
+	for _, stats := range []*domain.PlayerMatchStats{old, new} {
+		if stats != nil {
+			c.invalidateStats(stats)
+		}
+	}
+}
+
+// MatchChanged drop the entries of every player whose range contains the match date, before or after the write
+func (c *cachedAnalyticsService) MatchChanged(old, new *domain.Match) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	c.generation++
+	for key, entry := range c.entries {
+		if (old != nil && entry.covers(old.Date)) || (new != nil && entry.covers(new.Date)) {
+			delete(c.entries, key)
+		}
+	}
+}
+
+func (c *cachedAnalyticsService) invalidateStats(stats *domain.PlayerMatchStats) {
+	match, err := c.matchRepo.GetByID(stats.MatchID)
+
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	c.generation++
+	for key, entry := range c.entries {
+		if entry.playerID != stats.PlayerID {
+			continue
+		}
+		// without the match date every entry of the player may be affected
+		if err != nil || entry.covers(match.Date) {
+			delete(c.entries, key)
+		}
+	}
+}
+
+// load get the cached value of key or compute it, one computation per key runs at a time and a value
+// computed while an invalidation happened is returned but not stored
+func (c *cachedAnalyticsService) load(key, playerID string, start, end time.Time, compute func() (interface{}, error)) (interface{}, error) {
+	c.mu.Lock()
+	if entry, ok := c.entries[key]; ok {
+		if c.clock.Now().Before(entry.expires) {
+			c.mu.Unlock()
+			return entry.value, nil
+		}
+		delete(c.entries, key)
+	}
+	if call, ok := c.calls[key]; ok {
+		c.mu.Unlock()
+		<-call.done
+		return call.value, call.err
+	}
+
+	call := &cacheCall{done: make(chan struct{})}
+	c.calls[key] = call
+	generation := c.generation
+	c.mu.Unlock()
+
+	call.value, call.err = compute()
+
+	c.mu.Lock()
+	delete(c.calls, key)
+	if call.err == nil && generation == c.generation {
+		c.makeRoom()
+		c.entries[key] = &cacheEntry{
+			playerID: playerID,
+			start:    start,
+			end:      end,
+			value:    call.value,
+			expires:  c.clock.Now().Add(c.ttl),
+		}
+	}
+	c.mu.Unlock()
+	close(call.done)
+
+	return call.value, call.err
+}
+
+// makeRoom drop the expired entries once per ttl, and when the cache is still full the entry expiring
+// first, called with mu held before storing an entry
+func (c *cachedAnalyticsService) makeRoom() {
+	now := c.clock.Now()
+	if !now.Before(c.nextSweep) || len(c.entries) >= maxCacheEntries {
+		for key, entry := range c.entries {
+			if !now.Before(entry.expires) {
+				delete(c.entries, key)
+			}
+		}
+		c.nextSweep = now.Add(c.ttl)
+	}
+
+	for len(c.entries) >= maxCacheEntries {
+		var first string
+		for key, entry := range c.entries {
+			if first == "" || entry.expires.Before(c.entries[first].expires) {
+				first = key
+			}
+		}
+		delete(c.entries, first)
+	}
+}
+
+// key cache key of a method call, the formula version is part of it
+func (c *cachedAnalyticsService) key(method string, parts ...string) string {
+	key := analyticsModelVersion + "|" + method
+	for _, part := range parts {
+		key += "|" + part
+	}
+	return key
+}
+
+// covers whether t is in the entry's date range, both ends included
+func (e *cacheEntry) covers(t time.Time) bool {
+	return !t.Before(e.start) && !t.After(e.end)
+}
+
+// dateRange range of dates formatted like 2006-01-02, the whole end day included
+func (c *cachedAnalyticsService) dateRange(startDate, endDate string) (time.Time, time.Time, error) {
+	tr, err := domain.DateRange(startDate, endDate)
+	if err != nil {
+		return time.Time{}, time.Time{}, err
+	}
+
+	return tr.Start, tr.End.Add(-time.Nanosecond), nil
+}
@@ -0,0 +1,146 @@
+package service
+
+import (
+	"fmt"
+	"football-analytics/internal/domain"
+	"sync"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+)
+
+// countingAnalytics count CalculatePlayerPerformance calls, blocking each call until release is closed
+type countingAnalytics struct {
+	domain.AnalyticsService
+	calls   int32
+	release chan struct{}
+}
+
+func (a *countingAnalytics) CalculatePlayerPerformance(playerID string, timeRange string) (*domain.PerformanceMetrics, error) {
+	atomic.AddInt32(&a.calls, 1)
+	<-a.release
+	return &domain.PerformanceMetrics{PlayerID: playerID}, nil
+}
+
+func newCountingCache() (*countingAnalytics, *MockMatchRepository, CachedAnalyticsService) {
+	inner := &countingAnalytics{release: make(chan struct{})}
+	matchRepo := new(MockMatchRepository)
+	return inner, matchRepo, NewCachedAnalyticsService(inner, matchRepo, nil, time.Hour, nil)
+}
+
+// manualClock is a clock moved forward by the test
+type manualClock struct {
+	mu  sync.Mutex
+	now time.Time
+}
+
+func (c *manualClock) Now() time.Time {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	return c.now
+}
+
+func (c *manualClock) advance(d time.Duration) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	c.now = c.now.Add(d)
+}
+
+func TestCachedAnalyticsExpiresOnClock(t *testing.T) {
+	inner := &countingAnalytics{release: make(chan struct{})}
+	close(inner.release)
+	clock := &manualClock{now: utcDate(2025, 1, 10)}
+	cache := NewCachedAnalyticsService(inner, new(MockMatchRepository), nil, time.Hour, clock)
+
+	_, _ = cache.CalculatePlayerPerformance("p1", "all")
+	clock.advance(59 * time.Minute)
+	_, _ = cache.CalculatePlayerPerformance("p1", "all")
+	assert.Equal(t, int32(1), atomic.LoadInt32(&inner.calls))
+
+	clock.advance(time.Minute)
+	_, _ = cache.CalculatePlayerPerformance("p1", "all")
+	assert.Equal(t, int32(2), atomic.LoadInt32(&inner.calls))
+}
+
+func TestCachedAnalyticsEvictsExpiredEntries(t *testing.T) {
+	inner := &countingAnalytics{release: make(chan struct{})}
+	close(inner.release)
+	clock := &manualClock{now: utcDate(2025, 1, 10)}
+	cache := NewCachedAnalyticsService(inner, new(MockMatchRepository), nil, time.Hour, clock).(*cachedAnalyticsService)
+
+	_, _ = cache.CalculatePlayerPerformance("p1", "all")
+	_, _ = cache.CalculatePlayerPerformance("p2", "all")
+	clock.advance(30 * time.Minute)
+	_, _ = cache.CalculatePlayerPerformance("p3", "all")
+	assert.Len(t, cache.entries, 3)
+
+	// the first write after the ttl drops the expired entries of players never read again
+	clock.advance(time.Hour)
+	_, _ = cache.CalculatePlayerPerformance("p4", "all")
+	assert.Len(t, cache.entries, 1)
+}
+
+func TestCachedAnalyticsIsBounded(t *testing.T) {
+	inner := &countingAnalytics{release: make(chan struct{})}
+	close(inner.release)
+	clock := &manualClock{now: utcDate(2025, 1, 10)}
+	cache := NewCachedAnalyticsService(inner, new(MockMatchRepository), nil, time.Hour, clock).(*cachedAnalyticsService)
+
+	for i := 0; i <= maxCacheEntries; i++ {
+		clock.advance(time.Millisecond)
+		_, _ = cache.CalculatePlayerPerformance(fmt.Sprintf("p%d", i), "all")
+	}
+
+	assert.Len(t, cache.entries, maxCacheEntries)
+	_, ok := cache.entries[cache.key("performance", "p0", "all")]
+	assert.False(t, ok, "the entry expiring first makes room")
+}
+
+func TestCachedAnalyticsCollapsesConcurrentRequests(t *testing.T) {
+	inner, _, cache := newCountingCache()
+
+	var wg sync.WaitGroup
+	for i := 0; i < 10; i++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			metrics, err := cache.CalculatePlayerPerformance("p1", "all")
+			assert.NoError(t, err)
+			assert.Equal(t, "p1", metrics.PlayerID)
+		}()
+	}
+	time.Sleep(10 * time.Millisecond)
+	close(inner.release)
+	wg.Wait()
+
+	_, _ = cache.CalculatePlayerPerformance("p1", "all")
+	assert.Equal(t, int32(1), atomic.LoadInt32(&inner.calls))
+}
+
+func TestCachedAnalyticsInvalidation(t *testing.T) {
+	inner, matchRepo, cache := newCountingCache()
+	close(inner.release)
+	matchRepo.On("GetByID", "old").Return(&domain.Match{ID: "old", Date: time.Date(1990, 1, 1, 0, 0, 0, 0, time.UTC)}, nil)
+	matchRepo.On("GetByID", "recent").Return(&domain.Match{ID: "recent", Date: time.Now().AddDate(0, 0, -1)}, nil)
+
+	_, _ = cache.CalculatePlayerPerformance("p1", "1y")
+	_, _ = cache.CalculatePlayerPerformance("p2", "1y")
+
+	// stats of another player or outside the range keep the entry
+	cache.StatsChanged(nil, &domain.PlayerMatchStats{PlayerID: "p2", MatchID: "old"})
+	cache.StatsChanged(&domain.PlayerMatchStats{PlayerID: "p1", MatchID: "old"}, nil)
+	_, _ = cache.CalculatePlayerPerformance("p1", "1y")
+	assert.Equal(t, int32(2), atomic.LoadInt32(&inner.calls))
+
+	cache.StatsChanged(&domain.PlayerMatchStats{PlayerID: "p1", MatchID: "old"}, &domain.PlayerMatchStats{PlayerID: "p1", MatchID: "recent"})
+	_, _ = cache.CalculatePlayerPerformance("p1", "1y")
+	_, _ = cache.CalculatePlayerPerformance("p2", "1y")
+	assert.Equal(t, int32(3), atomic.LoadInt32(&inner.calls))
+
+	cache.MatchChanged(nil, &domain.Match{ID: "recent", Date: time.Now().AddDate(0, 0, -1)})
+	_, _ = cache.CalculatePlayerPerformance("p1", "1y")
+	_, _ = cache.CalculatePlayerPerformance("p2", "1y")
+	assert.Equal(t, int32(5), atomic.LoadInt32(&inner.calls))
+}
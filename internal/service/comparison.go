@@ -0,0 +1,176 @@
+package service
+
+import (
+	"fmt"
+	"football-analytics/internal/domain"
+	"math"
+	"math/rand"
+	"sort"
+)
+
+const (
+	defaultBootstrapSamples = 2000
+	defaultConfidence       = 0.9
+	defaultBootstrapSeed    = 1
+)
+
+// ComparePlayersWithConfidence compare players with a bootstrap confidence interval of every metric
+// and the probability that each player is better than each other one, matches are resampled with replacement
+func (s *analyticsService) ComparePlayersWithConfidence(playerIDs []string, opts domain.ComparisonOptions) (*domain.PlayerComparison, error) {
+	opts, err := comparisonDefaults(opts)
+	if err != nil {
+		return nil, err
+	}
+
+	tr, err := domain.ParseTimeRange(opts.TimeRange)
+	if err != nil {
+		return nil, err
+	}
+
+	now := s.clock.Now()
+	statsByPlayer := make([][]*domain.PlayerMatchStats, len(playerIDs))
+	for i, playerID := range playerIDs {
+		if _, err := s.playerRepo.GetByID(playerID); err != nil {
+			return nil, err
+		}
+
+		appearances, _, err := s.playerRange(playerID, tr, now)
+		if err != nil {
+			return nil, err
+		}
+		statsByPlayer[i] = appearanceStats(appearances)
+	}
+
+	comparison := bootstrapComparison(playerIDs, statsByPlayer, opts)
+	comparison.TimeRange = opts.TimeRange
+
+	return comparison, nil
+}
+
+// comparisonDefaults fill the zero options with defaults and validate them
+func comparisonDefaults(opts domain.ComparisonOptions) (domain.ComparisonOptions, error) {
+	if opts.TimeRange == "" {
+		opts.TimeRange = "season"
+	}
+	if opts.Samples == 0 {
+		opts.Samples = defaultBootstrapSamples
+	}
+	if opts.Confidence == 0 {
+		opts.Confidence = defaultConfidence
+	}
+	if opts.Seed == 0 {
+		opts.Seed = defaultBootstrapSeed
+	}
+	if opts.Samples < 0 || opts.Confidence <= 0 || opts.Confidence >= 1 {
+		return opts, fmt.Errorf("invalid comparison options samples=%d confidence=%v", opts.Samples, opts.Confidence)
+	}
+
+	return opts, nil
+}
+
+// bootstrapComparison estimate every metric of every player with a percentile interval over
+// resampled matches, sample b of every player is compared with sample b of the others
+func bootstrapComparison(playerIDs []string, statsByPlayer [][]*domain.PlayerMatchStats, opts domain.ComparisonOptions) *domain.PlayerComparison {
+	rng := rand.New(rand.NewSource(opts.Seed))
+
+	// samples[player][metric][b]
+	samples := make([][][]float64, len(playerIDs))
+	comparison := &domain.PlayerComparison{
+		Samples:    opts.Samples,
+		Confidence: opts.Confidence,
+	}
+
+	for i, playerID := range playerIDs {
+		stats := statsByPlayer[i]
+		samples[i] = make([][]float64, len(domain.ComparisonMetrics))
+		for m := range samples[i] {
+			samples[i][m] = make([]float64, opts.Samples)
+		}
+
+		resample := make([]*domain.PlayerMatchStats, len(stats))
+		for b := 0; b < opts.Samples; b++ {
+			for k := range resample {
+				resample[k] = stats[rng.Intn(len(stats))]
+			}
+			values := comparisonValues(calculateMetricsFromStats(playerID, resample))
+			for m, value := range values {
+				samples[i][m][b] = value
+			}
+		}
+
+		estimate := &domain.PlayerEstimate{
+			PlayerID: playerID,
+			Matches:  len(stats),
+			Metrics:  make(map[string]*domain.MetricEstimate),
+		}
+		point := comparisonValues(calculateMetricsFromStats(playerID, stats))
+		for m, metric := range domain.ComparisonMetrics {
+			lower, upper := percentileInterval(samples[i][m], opts.Confidence)
+			estimate.Metrics[metric] = &domain.MetricEstimate{Value: point[m], Lower: lower, Upper: upper}
+		}
+		comparison.Players = append(comparison.Players, estimate)
+	}
+
+	for a := range playerIDs {
+		for b := a + 1; b < len(playerIDs); b++ {
+			for m, metric := range domain.ComparisonMetrics {
+				comparison.Pairwise = append(comparison.Pairwise, &domain.MetricProbability{
+					PlayerA:     playerIDs[a],
+					PlayerB:     playerIDs[b],
+					Metric:      metric,
+					Probability: probabilityGreater(samples[a][m], samples[b][m]),
+				})
+			}
+		}
+	}
+
+	return comparison
+}
+
+// comparisonValues metric values in ComparisonMetrics order
+func comparisonValues(metrics *domain.PerformanceMetrics) []float64 {
+	return []float64{
+		metrics.GoalsPerMinute,
+		metrics.AssistsPerMinute,
+		metrics.PassAccuracy,
+		metrics.ShotAccuracy,
+		metrics.DefensiveEfficiency,
+		metrics.Stamina,
+		metrics.OverallRating,
+	}
+}
+
+// percentileInterval central interval of the samples with the given coverage
+func percentileInterval(samples []float64, confidence float64) (float64, float64) {
+	if len(samples) == 0 {
+		return 0, 0
+	}
+
+	sorted := append([]float64(nil), samples...)
+	sort.Float64s(sorted)
+
+	tail := (1 - confidence) / 2
+	lower := int(math.Floor(tail * float64(len(sorted)-1)))
+	upper := int(math.Ceil((1 - tail) * float64(len(sorted)-1)))
+
+	return sorted[lower], sorted[upper]
+}
+
+// probabilityGreater share of paired samples where a is greater than b, ties count half
+func probabilityGreater(a, b []float64) float64 {
+	if len(a) == 0 {
+		return 0.5
+	}
+
+	var wins float64
+	for i := range a {
+		switch {
+		case a[i] > b[i]:
+			wins++
+		case a[i] == b[i]:
+			wins += 0.5
+		}
+	}
+
+	return wins / float64(len(a))
+}
@@ -0,0 +1,57 @@
+package service
+
+import (
+	"football-analytics/internal/domain"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func comparisonStats(goals ...int) []*domain.PlayerMatchStats {
+	var stats []*domain.PlayerMatchStats
+	for _, g := range goals {
+		stats = append(stats, &domain.PlayerMatchStats{MinutesPlayed: 90, Goals: g, Shots: 3, ShotsOnTarget: 1, PassAccuracy: 80})
+	}
+	return stats
+}
+
+func TestBootstrapComparison(t *testing.T) {
+	opts, err := comparisonDefaults(domain.ComparisonOptions{Samples: 500})
+	assert.NoError(t, err)
+
+	comparison := bootstrapComparison(
+		[]string{"striker", "defender", "twin"},
+		[][]*domain.PlayerMatchStats{
+			comparisonStats(2, 1, 2, 3, 1, 2, 2, 1, 2, 3),
+			comparisonStats(0, 0, 0, 1, 0, 0, 0, 0, 0, 0),
+			comparisonStats(0, 0, 0, 1, 0, 0, 0, 0, 0, 0),
+		},
+		opts,
+	)
+
+	for _, player := range comparison.Players {
+		goals := player.Metrics["goals_per_minute"]
+		assert.LessOrEqual(t, goals.Lower, goals.Value)
+		assert.GreaterOrEqual(t, goals.Upper, goals.Value)
+	}
+
+	probabilities := make(map[string]float64)
+	for _, p := range comparison.Pairwise {
+		if p.Metric == "goals_per_minute" {
+			probabilities[p.PlayerA+"/"+p.PlayerB] = p.Probability
+		}
+	}
+	assert.Greater(t, probabilities["striker/defender"], 0.99)
+	assert.InDelta(t, 0.5, probabilities["defender/twin"], 0.1)
+
+	again := bootstrapComparison(
+		[]string{"striker", "defender", "twin"},
+		[][]*domain.PlayerMatchStats{
+			comparisonStats(2, 1, 2, 3, 1, 2, 2, 1, 2, 3),
+			comparisonStats(0, 0, 0, 1, 0, 0, 0, 0, 0, 0),
+			comparisonStats(0, 0, 0, 1, 0, 0, 0, 0, 0, 0),
+		},
+		opts,
+	)
+	assert.Equal(t, comparison, again)
+}
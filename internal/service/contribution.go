@@ -0,0 +1,245 @@
+package service
+
+import (
+	"football-analytics/internal/domain"
+	"sort"
+	"time"
+)
+
+// GetPlayerContribution get a player's share of the output of their team over the time range, the
+// team is the one the player played for in each match, a player who changed teams is measured
+// against the totals of every team they played for from their first to their last appearance for it
+func (s *analyticsService) GetPlayerContribution(playerID string, timeRange string) (*domain.PlayerContribution, error) {
+	tr, err := domain.ParseTimeRange(timeRange)
+	if err != nil {
+		return nil, err
+	}
+
+	player, err := s.playerRepo.GetByID(playerID)
+	if err != nil {
+		return nil, err
+	}
+
+	appearances, window, err := s.playerRange(playerID, tr, s.clock.Now())
+	if err != nil {
+		return nil, err
+	}
+
+	teamOf, _, err := s.statsTeams()
+	if err != nil {
+		return nil, err
+	}
+
+	// appearances are ordered by date, the player's time at a team runs from the first to the last
+	var teamIDs []string
+	first := make(map[string]time.Time)
+	last := make(map[string]time.Time)
+	for _, app := range appearances {
+		teamID := teamOf(app.stats)
+		if teamID == "" {
+			continue
+		}
+		if !containsString(teamIDs, teamID) {
+			teamIDs = append(teamIDs, teamID)
+			first[teamID] = app.match.Date
+		}
+		last[teamID] = app.match.Date
+	}
+
+	matches, err := s.matchRepo.ListByDateRange(window.start, window.end)
+	if err != nil {
+		return nil, err
+	}
+	matches = sortedMatches(matches)
+
+	contribution := &domain.PlayerContribution{PlayerID: player.ID, Name: player.Name, TeamIDs: teamIDs}
+	totals := &domain.TeamTotals{}
+	statsByMatch := make(map[string][]*domain.PlayerMatchStats)
+	for _, teamID := range teamIDs {
+		var teamMatches []*domain.Match
+		for _, match := range matches {
+			if window.includes(match) && playedBy(match, teamID) &&
+				!match.Date.Before(first[teamID]) && !match.Date.After(last[teamID]) {
+				teamMatches = append(teamMatches, match)
+			}
+		}
+		if err := s.loadMatchStats(teamMatches, statsByMatch); err != nil {
+			return nil, err
+		}
+
+		teamTotals, players := squadTotals(teamID, teamMatches, statsByMatch, teamOf)
+		addTeamTotals(totals, teamTotals)
+		if row, ok := players[playerID]; ok {
+			addContribution(contribution, row)
+		}
+	}
+	applyShares(contribution, totals)
+
+	return contribution, nil
+}
+
+// GetSquadContributions get the contribution of every player who played for the team in its
+// completed matches of the time range
+func (s *analyticsService) GetSquadContributions(teamID string, timeRange string) (*domain.SquadContributions, error) {
+	tr, err := domain.ParseTimeRange(timeRange)
+	if err != nil {
+		return nil, err
+	}
+
+	matches, err := s.teamRange(teamID, tr, s.clock.Now())
+	if err != nil {
+		return nil, err
+	}
+
+	teamOf, players, err := s.statsTeams()
+	if err != nil {
+		return nil, err
+	}
+	names := make(map[string]string)
+	for _, player := range players {
+		names[player.ID] = player.Name
+	}
+
+	var teamMatches []*domain.Match
+	for _, match := range matches {
+		if playedBy(match, teamID) {
+			teamMatches = append(teamMatches, match)
+		}
+	}
+	statsByMatch := make(map[string][]*domain.PlayerMatchStats)
+	if err := s.loadMatchStats(teamMatches, statsByMatch); err != nil {
+		return nil, err
+	}
+
+	totals, rows := squadTotals(teamID, teamMatches, statsByMatch, teamOf)
+	squad := &domain.SquadContributions{TeamID: teamID, TimeRange: timeRange, Totals: totals}
+	for playerID, row := range rows {
+		row.Name = names[playerID]
+		applyShares(row, totals)
+		squad.Players = append(squad.Players, row)
+	}
+
+	sort.SliceStable(squad.Players, func(i, j int) bool {
+		a, b := squad.Players[i], squad.Players[j]
+		if a.GoalInvolvement != b.GoalInvolvement {
+			return a.GoalInvolvement > b.GoalInvolvement
+		}
+		if a.Minutes != b.Minutes {
+			return a.Minutes > b.Minutes
+		}
+		return a.PlayerID < b.PlayerID
+	})
+
+	return squad, nil
+}
+
+// loadMatchStats add the stats of the matches missing from statsByMatch, fetched in one query
+func (s *analyticsService) loadMatchStats(matches []*domain.Match, statsByMatch map[string][]*domain.PlayerMatchStats) error {
+	var missing []*domain.Match
+	for _, match := range matches {
+		if _, ok := statsByMatch[match.ID]; !ok {
+			missing = append(missing, match)
+		}
+	}
+	if len(missing) == 0 {
+		return nil
+	}
+
+	loaded, err := loadStatsByMatch(s.playerStatsRepo, missing)
+	if err != nil {
+		return err
+	}
+	// matches without stats are kept so they are not fetched again
+	for _, match := range missing {
+		statsByMatch[match.ID] = loaded[match.ID]
+	}
+	return nil
+}
+
+// playedBy tell if the team played the completed match
+func playedBy(match *domain.Match, teamID string) bool {
+	return match.Status == "completed" && (match.HomeTeamID == teamID || match.AwayTeamID == teamID)
+}
+
+// squadTotals totals of the team over its matches and the sums of every player who played for it
+func squadTotals(
+	teamID string,
+	matches []*domain.Match,
+	statsByMatch map[string][]*domain.PlayerMatchStats,
+	teamOf func(stat *domain.PlayerMatchStats) string,
+) (*domain.TeamTotals, map[string]*domain.PlayerContribution) {
+	totals := &domain.TeamTotals{}
+	players := make(map[string]*domain.PlayerContribution)
+
+	for _, match := range matches {
+		totals.Matches++
+		if match.HomeTeamID == teamID {
+			totals.Goals += match.HomeScore
+		} else {
+			totals.Goals += match.AwayScore
+		}
+
+		for _, stat := range statsByMatch[match.ID] {
+			if teamOf(stat) != teamID {
+				continue
+			}
+
+			totals.Assists += stat.Assists
+			totals.Shots += stat.Shots
+			totals.Minutes += stat.MinutesPlayed
+			totals.DefensiveActions += stat.Tackles + stat.Interceptions
+
+			row, ok := players[stat.PlayerID]
+			if !ok {
+				row = &domain.PlayerContribution{PlayerID: stat.PlayerID, TeamIDs: []string{teamID}}
+				players[stat.PlayerID] = row
+			}
+			row.Appearances++
+			row.Goals += stat.Goals
+			row.Assists += stat.Assists
+			row.Shots += stat.Shots
+			row.Minutes += stat.MinutesPlayed
+			row.DefensiveActions += stat.Tackles + stat.Interceptions
+		}
+	}
+
+	return totals, players
+}
+
+// addTeamTotals add the totals of another team to sum
+func addTeamTotals(sum, totals *domain.TeamTotals) {
+	sum.Matches += totals.Matches
+	sum.Goals += totals.Goals
+	sum.Assists += totals.Assists
+	sum.Shots += totals.Shots
+	sum.Minutes += totals.Minutes
+	sum.DefensiveActions += totals.DefensiveActions
+}
+
+// addContribution add the sums of a player for another team to contribution
+func addContribution(contribution, row *domain.PlayerContribution) {
+	contribution.Appearances += row.Appearances
+	contribution.Goals += row.Goals
+	contribution.Assists += row.Assists
+	contribution.Shots += row.Shots
+	contribution.Minutes += row.Minutes
+	contribution.DefensiveActions += row.DefensiveActions
+}
+
+// applyShares set the shares of a contribution in percent of the team totals, zero for empty totals
+func applyShares(contribution *domain.PlayerContribution, totals *domain.TeamTotals) {
+	share := func(value, total int) float64 {
+		if total == 0 {
+			return 0
+		}
+		return float64(value) / float64(total) * 100
+	}
+
+	contribution.Team = totals
+	contribution.GoalShare = share(contribution.Goals, totals.Goals)
+	contribution.AssistShare = share(contribution.Assists, totals.Assists)
+	contribution.ShotShare = share(contribution.Shots, totals.Shots)
+	contribution.MinuteShare = share(contribution.Minutes, totals.Matches*90)
+	contribution.DefensiveShare = share(contribution.DefensiveActions, totals.DefensiveActions)
+	contribution.GoalInvolvement = share(contribution.Goals+contribution.Assists, totals.Goals)
+}
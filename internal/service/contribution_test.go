@@ -0,0 +1,95 @@
+package service
+
+import (
+	"football-analytics/internal/domain"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestSquadTotals(t *testing.T) {
+	teamOf := func(stat *domain.PlayerMatchStats) string { return stat.TeamID }
+	matches := []*domain.Match{
+		{ID: "m1", HomeTeamID: "t1", AwayTeamID: "t2", HomeScore: 3, AwayScore: 1, Status: "completed"},
+		{ID: "m2", HomeTeamID: "t3", AwayTeamID: "t1", HomeScore: 0, AwayScore: 1, Status: "completed"},
+	}
+	statsByMatch := map[string][]*domain.PlayerMatchStats{
+		"m1": {
+			{PlayerID: "p1", TeamID: "t1", MinutesPlayed: 90, Goals: 2, Shots: 5, Tackles: 1},
+			{PlayerID: "p2", TeamID: "t1", MinutesPlayed: 90, Goals: 1, Assists: 2, Shots: 3, Interceptions: 3},
+			{PlayerID: "p9", TeamID: "t2", MinutesPlayed: 90, Goals: 1, Shots: 4},
+		},
+		"m2": {
+			{PlayerID: "p1", TeamID: "t1", MinutesPlayed: 60, Goals: 1, Shots: 2},
+		},
+	}
+
+	totals, players := squadTotals("t1", matches, statsByMatch, teamOf)
+	assert.Equal(t, &domain.TeamTotals{Matches: 2, Goals: 4, Assists: 2, Shots: 10, Minutes: 240, DefensiveActions: 4}, totals)
+	assert.Len(t, players, 2)
+
+	p1 := players["p1"]
+	applyShares(p1, totals)
+	assert.Equal(t, 2, p1.Appearances)
+	assert.InDelta(t, 75, p1.GoalShare, 1e-9)
+	assert.InDelta(t, 70, p1.ShotShare, 1e-9)
+	assert.InDelta(t, 150.0/180*100, p1.MinuteShare, 1e-9)
+	assert.InDelta(t, 25, p1.DefensiveShare, 1e-9)
+	assert.InDelta(t, 75, p1.GoalInvolvement, 1e-9)
+
+	p2 := players["p2"]
+	applyShares(p2, totals)
+	assert.InDelta(t, 100, p2.AssistShare, 1e-9)
+	assert.InDelta(t, 75, p2.GoalInvolvement, 1e-9)
+}
+
+// batchStatsRepository count the queries for the stats of a set of matches
+type batchStatsRepository struct {
+	*memoryStatsRepository
+	batches int
+}
+
+func (r *batchStatsRepository) ListByMatchIDs(matchIDs []string) ([]*domain.PlayerMatchStats, error) {
+	r.batches++
+	return r.memoryStatsRepository.ListByMatchIDs(matchIDs)
+}
+
+func TestGetSquadContributions(t *testing.T) {
+	service := newSplitsFixture()
+	stats := &batchStatsRepository{memoryStatsRepository: service.playerStatsRepo.(*memoryStatsRepository)}
+	service.playerStatsRepo = stats
+
+	squad, err := service.GetSquadContributions("t1", "all")
+	assert.NoError(t, err)
+	assert.Equal(t, 1, stats.batches)
+	assert.Equal(t, &domain.TeamTotals{Matches: 5, Goals: 7, Minutes: 540}, squad.Totals)
+
+	// team goals are the match scores, p3 has moved to t2 but played for t1
+	assert.Len(t, squad.Players, 2)
+	assert.Equal(t, "p1", squad.Players[0].PlayerID)
+	assert.InDelta(t, 500.0/7, squad.Players[0].GoalShare, 1e-9)
+	assert.Equal(t, "p3", squad.Players[1].PlayerID)
+	assert.InDelta(t, 20, squad.Players[1].MinuteShare, 1e-9)
+}
+
+func TestGetPlayerContributionTransferred(t *testing.T) {
+	service := newSplitsFixture()
+	stats := &batchStatsRepository{memoryStatsRepository: service.playerStatsRepo.(*memoryStatsRepository)}
+	service.playerStatsRepo = stats
+
+	// p3 moves to t2 after the draw at t3 and scores once in a 2-0 win
+	m8 := &domain.Match{ID: "m8", HomeTeamID: "t2", AwayTeamID: "t6", Competition: "League", Date: utcDate(2024, 9, 25), HomeScore: 2, Status: "completed"}
+	service.matchRepo.(*memoryMatchRepository).matches = append(service.matchRepo.(*memoryMatchRepository).matches, m8)
+	stats.matches[m8.ID] = m8
+	stats.byPlayer["p3"] = append(stats.byPlayer["p3"], &domain.PlayerMatchStats{PlayerID: "p3", MatchID: m8.ID, TeamID: "t2", MinutesPlayed: 90, Goals: 1})
+
+	contribution, err := service.GetPlayerContribution("p3", "all")
+	assert.NoError(t, err)
+	assert.Equal(t, []string{"t1", "t2"}, contribution.TeamIDs)
+	assert.Equal(t, 2, contribution.Appearances)
+	// only the matches of each team during the player's time there count
+	assert.Equal(t, &domain.TeamTotals{Matches: 2, Goals: 3, Minutes: 270}, contribution.Team)
+	assert.InDelta(t, 100.0/3, contribution.GoalShare, 1e-9)
+	assert.InDelta(t, 100, contribution.MinuteShare, 1e-9)
+	assert.Equal(t, 2, stats.batches)
+}
@@ -0,0 +1,476 @@
+package service
+
+import (
+	"errors"
+	"fmt"
+	"football-analytics/internal/domain"
+	"log"
+	"sort"
+	"time"
+
+	"github.com/google/uuid"
+)
+
+// defaultDisciplineRule is used for competitions without their own rule
+var defaultDisciplineRule = domain.DisciplineRule{
+	YellowThreshold:        5,
+	YellowBanMatches:       1,
+	StraightRedBanMatches:  3,
+	SecondYellowBanMatches: 1,
+}
+
+// DisciplineService is interface for card accumulation and suspension tracking, the status and
+// report queries do not write, register it as observer of the observed stats repository to store
+// the suspensions triggered by each write of stats
+type DisciplineService interface {
+	domain.StatsObserver
+	SaveRule(rule *domain.DisciplineRule) (*domain.DisciplineRule, error)
+	GetPlayerStatus(playerID, competition string, date time.Time) (*domain.PlayerDisciplineStatus, error)
+	GetMatchDisciplineReport(matchID string) (*domain.MatchDisciplineReport, error)
+	RecordSuspensions(matchID string) error
+	RecordServedBans(matchID string) error
+}
+
+type disciplineService struct {
+	disciplineRepo  domain.DisciplineRepository
+	playerStatsRepo domain.PlayerMatchStatsRepository
+	playerRepo      domain.PlayerRepository
+	matchRepo       domain.MatchRepository
+	calendar        *seasonCalendar
+}
+
+// NewDisciplineService create instance of DisciplineService
+func NewDisciplineService(
+	disciplineRepo domain.DisciplineRepository,
+	playerStatsRepo domain.PlayerMatchStatsRepository,
+	playerRepo domain.PlayerRepository,
+	matchRepo domain.MatchRepository,
+	seasonRepo domain.SeasonRepository,
+) DisciplineService {
+	return &disciplineService{
+		disciplineRepo:  disciplineRepo,
+		playerStatsRepo: playerStatsRepo,
+		playerRepo:      playerRepo,
+		matchRepo:       matchRepo,
+		calendar:        newSeasonCalendar(seasonRepo),
+	}
+}
+
+// SaveRule create or replace the discipline rule of a competition
+func (s *disciplineService) SaveRule(rule *domain.DisciplineRule) (*domain.DisciplineRule, error) {
+	if rule.Competition == "" {
+		return nil, errors.New("discipline rule needs a competition")
+	}
+	if rule.YellowThreshold <= 0 || rule.YellowBanMatches < 0 || rule.StraightRedBanMatches < 0 || rule.SecondYellowBanMatches < 0 || rule.ResetRound < 0 {
+		return nil, fmt.Errorf("invalid discipline rule for %s", rule.Competition)
+	}
+
+	if rule.ID == "" {
+		rule.ID = uuid.New().String()
+		rule.CreatedAt = time.Now()
+	}
+	rule.UpdatedAt = time.Now()
+
+	if err := s.disciplineRepo.SaveRule(rule); err != nil {
+		return nil, err
+	}
+
+	return rule, nil
+}
+
+// GetPlayerStatus get cards and suspensions of a player in the season of a competition in play at date,
+// counting the matches before date
+func (s *disciplineService) GetPlayerStatus(playerID, competition string, date time.Time) (*domain.PlayerDisciplineStatus, error) {
+	player, err := s.playerRepo.GetByID(playerID)
+	if err != nil {
+		return nil, err
+	}
+
+	rule, err := s.rule(competition)
+	if err != nil {
+		return nil, err
+	}
+
+	matches, err := s.competitionMatches(competition, date)
+	if err != nil {
+		return nil, err
+	}
+
+	return s.playerStatus(player, rule, matches, date)
+}
+
+// GetMatchDisciplineReport get players of both teams that are suspended or one yellow card from a ban for a match
+func (s *disciplineService) GetMatchDisciplineReport(matchID string) (*domain.MatchDisciplineReport, error) {
+	match, err := s.matchRepo.GetByID(matchID)
+	if err != nil {
+		return nil, err
+	}
+
+	rule, err := s.rule(match.Competition)
+	if err != nil {
+		return nil, err
+	}
+
+	matches, err := s.competitionMatches(match.Competition, match.Date)
+	if err != nil {
+		return nil, err
+	}
+
+	players, err := s.matchPlayers(match)
+	if err != nil {
+		return nil, err
+	}
+
+	report := &domain.MatchDisciplineReport{
+		MatchID:     match.ID,
+		Competition: match.Competition,
+	}
+
+	for _, player := range players {
+		status, err := s.playerStatus(player, rule, matches, match.Date)
+		if err != nil {
+			return nil, err
+		}
+
+		if status.Suspended {
+			report.Suspended = append(report.Suspended, status)
+		} else if status.OneCardFromBan {
+			report.AtRisk = append(report.AtRisk, status)
+		}
+	}
+
+	return report, nil
+}
+
+// RecordSuspensions store the suspensions triggered up to and including a completed match for every
+// player who played in it
+func (s *disciplineService) RecordSuspensions(matchID string) error {
+	match, err := s.matchRepo.GetByID(matchID)
+	if err != nil {
+		return err
+	}
+
+	matchStats, err := s.playerStatsRepo.ListByMatchID(match.ID)
+	if err != nil {
+		return err
+	}
+
+	for _, stat := range matchStats {
+		if err := s.recordPlayerSuspensions(stat.PlayerID, match); err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
+
+// StatsChanged store the suspensions triggered by a new or updated row, a failed write is logged
+// and left for RecordSuspensions
+func (s *disciplineService) StatsChanged(old, new *domain.PlayerMatchStats) {
+	if new == nil {
+		return
+	}
+
+	match, err := s.matchRepo.GetByID(new.MatchID)
+	if err != nil {
+		log.Printf("discipline: match %s of stats %s: %v", new.MatchID, new.ID, err)
+		return
+	}
+
+	if err := s.recordPlayerSuspensions(new.PlayerID, match); err != nil {
+		log.Printf("discipline: suspensions of player %s in match %s: %v", new.PlayerID, match.ID, err)
+	}
+}
+
+// RecordServedBans count a completed match towards the oldest open suspension of every
+// player of both teams that did not play in it
+func (s *disciplineService) RecordServedBans(matchID string) error {
+	match, err := s.matchRepo.GetByID(matchID)
+	if err != nil {
+		return err
+	}
+	if match.Status != "completed" {
+		return fmt.Errorf("match %s is not completed", match.ID)
+	}
+
+	rule, err := s.rule(match.Competition)
+	if err != nil {
+		return err
+	}
+
+	matches, err := s.competitionMatches(match.Competition, match.Date)
+	if err != nil {
+		return err
+	}
+
+	players, err := s.matchPlayers(match)
+	if err != nil {
+		return err
+	}
+
+	matchStats, err := s.playerStatsRepo.ListByMatchID(match.ID)
+	if err != nil {
+		return err
+	}
+	played := make(map[string]bool)
+	for _, stat := range matchStats {
+		played[stat.PlayerID] = true
+	}
+
+	for _, player := range players {
+		if played[player.ID] {
+			continue
+		}
+
+		// a ban has to be stored before a served match can count towards it
+		if err := s.storeSuspensions(player.ID, rule, matches); err != nil {
+			return err
+		}
+
+		status, err := s.playerStatus(player, rule, matches, match.Date)
+		if err != nil {
+			return err
+		}
+
+		for _, suspension := range status.Suspensions {
+			if suspension.Served < suspension.Matches && suspension.TriggerDate.Before(match.Date) {
+				if err := s.disciplineRepo.RecordServed(suspension.ID, match.ID); err != nil {
+					return err
+				}
+				break
+			}
+		}
+	}
+
+	return nil
+}
+
+// rule get the discipline rule of a competition, or the default rule if it has none
+func (s *disciplineService) rule(competition string) (*domain.DisciplineRule, error) {
+	rule, err := s.disciplineRepo.GetRule(competition)
+	if errors.Is(err, domain.ErrNotFound) {
+		defaultRule := defaultDisciplineRule
+		defaultRule.Competition = competition
+		return &defaultRule, nil
+	}
+	if err != nil {
+		return nil, err
+	}
+
+	return rule, nil
+}
+
+// competitionMatches get completed matches of the season of a competition in play at date played before date
+func (s *disciplineService) competitionMatches(competition string, date time.Time) ([]*domain.Match, error) {
+	season, err := s.calendar.seasonAt(competition, date)
+	if err != nil {
+		return nil, err
+	}
+
+	allMatches, err := s.matchRepo.ListByDateRange(season.StartDate, date)
+	if err != nil {
+		return nil, err
+	}
+
+	var matches []*domain.Match
+	for _, match := range allMatches {
+		if match.Competition == competition && match.Status == "completed" && !match.Date.Before(season.StartDate) && match.Date.Before(date) {
+			matches = append(matches, match)
+		}
+	}
+
+	return matches, nil
+}
+
+// matchPlayers get players of both teams of a match
+func (s *disciplineService) matchPlayers(match *domain.Match) ([]*domain.Player, error) {
+	allPlayers, err := s.playerRepo.List()
+	if err != nil {
+		return nil, err
+	}
+
+	var players []*domain.Player
+	for _, player := range allPlayers {
+		if player.TeamID == match.HomeTeamID || player.TeamID == match.AwayTeamID {
+			players = append(players, player)
+		}
+	}
+
+	return players, nil
+}
+
+// recordPlayerSuspensions store the suspensions of a player triggered in the competition of a match up
+// to and including the match
+func (s *disciplineService) recordPlayerSuspensions(playerID string, match *domain.Match) error {
+	rule, err := s.rule(match.Competition)
+	if err != nil {
+		return err
+	}
+
+	// matches before the day after the match include the match
+	matches, err := s.competitionMatches(match.Competition, match.Date.AddDate(0, 0, 1))
+	if err != nil {
+		return err
+	}
+
+	return s.storeSuspensions(playerID, rule, matches)
+}
+
+// storeSuspensions store the suspensions triggered in the matches that are not stored yet
+func (s *disciplineService) storeSuspensions(playerID string, rule *domain.DisciplineRule, matches []*domain.Match) error {
+	stats, err := s.playerStatsRepo.ListByPlayerID(playerID)
+	if err != nil {
+		return err
+	}
+
+	_, triggered := disciplineEvents(playerID, joinAppearances(matches, stats), rule, s.seasonLabels(matches))
+
+	existing, err := s.disciplineRepo.ListSuspensions(playerID, rule.Competition)
+	if err != nil {
+		return err
+	}
+	known := make(map[string]bool)
+	for _, suspension := range existing {
+		known[suspensionKey(suspension)] = true
+	}
+
+	for _, suspension := range triggered {
+		if known[suspensionKey(suspension)] {
+			continue
+		}
+		suspension.ID = uuid.New().String()
+		suspension.CreatedAt = time.Now()
+		suspension.UpdatedAt = suspension.CreatedAt
+		if err := s.disciplineRepo.CreateSuspension(suspension); err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
+
+// playerStatus summarize the player's status before date from the stored suspensions and the ones
+// triggered in matches that are not stored yet, nothing is written
+func (s *disciplineService) playerStatus(player *domain.Player, rule *domain.DisciplineRule, matches []*domain.Match, date time.Time) (*domain.PlayerDisciplineStatus, error) {
+	stats, err := s.playerStatsRepo.ListByPlayerID(player.ID)
+	if err != nil {
+		return nil, err
+	}
+
+	yellows, triggered := disciplineEvents(player.ID, joinAppearances(matches, stats), rule, s.seasonLabels(matches))
+
+	existing, err := s.disciplineRepo.ListSuspensions(player.ID, rule.Competition)
+	if err != nil {
+		return nil, err
+	}
+
+	var suspensions []*domain.Suspension
+	known := make(map[string]bool)
+	for _, suspension := range existing {
+		known[suspensionKey(suspension)] = true
+		if suspension.TriggerDate.Before(date) {
+			suspensions = append(suspensions, suspension)
+		}
+	}
+
+	for _, suspension := range triggered {
+		if !known[suspensionKey(suspension)] {
+			suspensions = append(suspensions, suspension)
+		}
+	}
+
+	sort.SliceStable(suspensions, func(i, j int) bool {
+		return suspensions[i].TriggerDate.Before(suspensions[j].TriggerDate)
+	})
+
+	status := &domain.PlayerDisciplineStatus{
+		PlayerID:    player.ID,
+		Name:        player.Name,
+		TeamID:      player.TeamID,
+		Competition: rule.Competition,
+		YellowCards: yellows,
+		Suspensions: suspensions,
+	}
+
+	for _, suspension := range suspensions {
+		if remaining := suspension.Matches - suspension.Served; remaining > 0 {
+			status.RemainingMatches += remaining
+		}
+	}
+	status.Suspended = status.RemainingMatches > 0
+	status.OneCardFromBan = rule.YellowThreshold > 1 && yellows%rule.YellowThreshold == rule.YellowThreshold-1
+
+	return status, nil
+}
+
+// seasonLabels season label of each match by id, matches the calendar can not place share an empty label
+func (s *disciplineService) seasonLabels(matches []*domain.Match) func(match *domain.Match) string {
+	labels := make(map[string]string)
+	for _, match := range matches {
+		if season, err := s.calendar.seasonOf(match); err == nil {
+			labels[match.ID] = season.Label
+		}
+	}
+	return func(match *domain.Match) string {
+		return labels[match.ID]
+	}
+}
+
+// suspensionKey identity of a suspension, the match that triggered it and the reason
+func suspensionKey(suspension *domain.Suspension) string {
+	return suspension.TriggerMatchID + "/" + string(suspension.Reason)
+}
+
+// disciplineEvents walk the appearances of a player in one competition (ordered by date) and
+// return the yellow cards counting towards the next accumulation ban and the suspensions triggered,
+// yellow cards and the reset round start over with every season
+func disciplineEvents(playerID string, appearances []appearance, rule *domain.DisciplineRule, seasonOf func(match *domain.Match) string) (int, []*domain.Suspension) {
+	var suspensions []*domain.Suspension
+	yellows := 0
+	reset := false
+	season := ""
+
+	suspend := func(app appearance, reason domain.SuspensionReason, matches int) {
+		if matches <= 0 {
+			return
+		}
+		suspensions = append(suspensions, &domain.Suspension{
+			PlayerID:       playerID,
+			Competition:    rule.Competition,
+			TriggerMatchID: app.match.ID,
+			TriggerDate:    app.match.Date,
+			Reason:         reason,
+			Matches:        matches,
+		})
+	}
+
+	for i, app := range appearances {
+		if label := seasonOf(app.match); i == 0 || label != season {
+			season = label
+			yellows = 0
+			reset = false
+		}
+		if rule.ResetRound > 0 && !reset && app.match.Round >= rule.ResetRound {
+			yellows = 0
+			reset = true
+		}
+
+		stat := app.stats
+		if stat.RedCards > 0 && stat.YellowCards >= 2 {
+			// the two yellows of a sending off do not count towards accumulation
+			suspend(app, domain.SuspensionSecondYellow, rule.SecondYellowBanMatches)
+			continue
+		}
+		if stat.RedCards > 0 {
+			suspend(app, domain.SuspensionStraightRed, rule.StraightRedBanMatches)
+		}
+
+		before := yellows
+		yellows += stat.YellowCards
+		if rule.YellowThreshold > 0 && yellows/rule.YellowThreshold > before/rule.YellowThreshold {
+			suspend(app, domain.SuspensionYellowAccumulation, rule.YellowBanMatches)
+		}
+	}
+
+	return yellows, suspensions
+}
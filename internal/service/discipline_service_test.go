@@ -0,0 +1,204 @@
+package service
+
+import (
+	"fmt"
+	"football-analytics/internal/domain"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+)
+
+// memoryDisciplineRepository keep rules and suspensions in memory
+type memoryDisciplineRepository struct {
+	rules       map[string]*domain.DisciplineRule
+	suspensions []*domain.Suspension
+}
+
+func (r *memoryDisciplineRepository) SaveRule(rule *domain.DisciplineRule) error {
+	r.rules[rule.Competition] = rule
+	return nil
+}
+
+func (r *memoryDisciplineRepository) GetRule(competition string) (*domain.DisciplineRule, error) {
+	rule, ok := r.rules[competition]
+	if !ok {
+		return nil, domain.ErrNotFound
+	}
+	return rule, nil
+}
+
+func (r *memoryDisciplineRepository) CreateSuspension(suspension *domain.Suspension) error {
+	r.suspensions = append(r.suspensions, suspension)
+	return nil
+}
+
+func (r *memoryDisciplineRepository) ListSuspensions(playerID, competition string) ([]*domain.Suspension, error) {
+	var suspensions []*domain.Suspension
+	for _, suspension := range r.suspensions {
+		if suspension.PlayerID == playerID && suspension.Competition == competition {
+			suspensions = append(suspensions, suspension)
+		}
+	}
+	return suspensions, nil
+}
+
+func (r *memoryDisciplineRepository) RecordServed(suspensionID, matchID string) error {
+	for _, suspension := range r.suspensions {
+		if suspension.ID == suspensionID {
+			suspension.Served++
+		}
+	}
+	return nil
+}
+
+// newDisciplineFixture p1 of t1 playing a league match a week from 2024-08-10 and from 2025-08-16
+func newDisciplineFixture(rule *domain.DisciplineRule, firstSeason, secondSeason []int) (*disciplineService, *memoryDisciplineRepository) {
+	player := &domain.Player{ID: "p1", Name: "Player", TeamID: "t1"}
+	players := &memoryPlayerRepository{players: map[string]*domain.Player{"p1": player}, list: []*domain.Player{player}}
+	matches := &memoryMatchRepository{}
+	stats := &memoryStatsRepository{matches: make(map[string]*domain.Match), byPlayer: make(map[string][]*domain.PlayerMatchStats)}
+
+	add := func(season string, first time.Time, cards []int) {
+		for i, yellows := range cards {
+			match := &domain.Match{
+				ID:          fmt.Sprintf("%s-%d", season, i+1),
+				HomeTeamID:  "t1",
+				AwayTeamID:  "t2",
+				Competition: rule.Competition,
+				Date:        first.AddDate(0, 0, 7*i),
+				Round:       i + 1,
+				Status:      "completed",
+			}
+			matches.matches = append(matches.matches, match)
+			stats.matches[match.ID] = match
+			stats.byPlayer["p1"] = append(stats.byPlayer["p1"], &domain.PlayerMatchStats{ID: "s-" + match.ID, PlayerID: "p1", MatchID: match.ID, YellowCards: yellows})
+		}
+	}
+	add("2024", utcDate(2024, 8, 10), firstSeason)
+	add("2025", utcDate(2025, 8, 16), secondSeason)
+
+	disciplineRepo := &memoryDisciplineRepository{rules: map[string]*domain.DisciplineRule{rule.Competition: rule}}
+	service := NewDisciplineService(disciplineRepo, stats, players, matches, nil).(*disciplineService)
+	return service, disciplineRepo
+}
+
+func disciplineAppearances(cards [][2]int) []appearance {
+	var appearances []appearance
+	first := time.Date(2024, 8, 10, 15, 0, 0, 0, time.UTC)
+	for i, c := range cards {
+		appearances = append(appearances, appearance{
+			match: &domain.Match{ID: string(rune('a' + i)), Date: first.AddDate(0, 0, 7*i), Round: i + 1},
+			stats: &domain.PlayerMatchStats{PlayerID: "p1", YellowCards: c[0], RedCards: c[1]},
+		})
+	}
+	return appearances
+}
+
+func oneSeason(match *domain.Match) string {
+	return "2024/25"
+}
+
+func TestDisciplineEventsYellowAccumulation(t *testing.T) {
+	rule := &domain.DisciplineRule{Competition: "League", YellowThreshold: 3, YellowBanMatches: 1}
+
+	// yellow cards per match: 1, 1, 0, 1, 1
+	yellows, suspensions := disciplineEvents("p1", disciplineAppearances([][2]int{{1, 0}, {1, 0}, {0, 0}, {1, 0}, {1, 0}}), rule, oneSeason)
+
+	assert.Equal(t, 4, yellows)
+	assert.Len(t, suspensions, 1)
+	assert.Equal(t, "d", suspensions[0].TriggerMatchID)
+	assert.Equal(t, domain.SuspensionYellowAccumulation, suspensions[0].Reason)
+	assert.Equal(t, 1, suspensions[0].Matches)
+}
+
+func TestDisciplineEventsRedCards(t *testing.T) {
+	rule := &domain.DisciplineRule{Competition: "League", YellowThreshold: 5, YellowBanMatches: 1, StraightRedBanMatches: 3, SecondYellowBanMatches: 1}
+
+	yellows, suspensions := disciplineEvents("p1", disciplineAppearances([][2]int{{2, 1}, {0, 1}, {1, 0}}), rule, oneSeason)
+
+	// the yellows of a second yellow sending off are not counted
+	assert.Equal(t, 1, yellows)
+	assert.Len(t, suspensions, 2)
+	assert.Equal(t, domain.SuspensionSecondYellow, suspensions[0].Reason)
+	assert.Equal(t, 1, suspensions[0].Matches)
+	assert.Equal(t, domain.SuspensionStraightRed, suspensions[1].Reason)
+	assert.Equal(t, 3, suspensions[1].Matches)
+}
+
+func TestDisciplineEventsResetRound(t *testing.T) {
+	rule := &domain.DisciplineRule{Competition: "Cup", YellowThreshold: 3, YellowBanMatches: 1, ResetRound: 3}
+
+	// two yellows before the reset round and two after it never reach the threshold
+	yellows, suspensions := disciplineEvents("p1", disciplineAppearances([][2]int{{1, 0}, {1, 0}, {1, 0}, {1, 0}}), rule, oneSeason)
+
+	assert.Equal(t, 2, yellows)
+	assert.Empty(t, suspensions)
+}
+
+func TestDisciplineEventsSeasonChange(t *testing.T) {
+	rule := &domain.DisciplineRule{Competition: "League", YellowThreshold: 3, YellowBanMatches: 1, ResetRound: 2}
+	seasonOf := func(match *domain.Match) string {
+		if match.ID < "c" {
+			return "2024/25"
+		}
+		return "2025/26"
+	}
+
+	// the reset round applies once in every season, yellows start over with the new season
+	yellows, suspensions := disciplineEvents("p1", disciplineAppearances([][2]int{{1, 0}, {1, 0}, {1, 0}, {1, 0}}), rule, seasonOf)
+
+	assert.Equal(t, 2, yellows)
+	assert.Empty(t, suspensions)
+}
+
+func TestGetPlayerStatusAcrossSeasons(t *testing.T) {
+	rule := &domain.DisciplineRule{Competition: "League", YellowThreshold: 3, YellowBanMatches: 1}
+	service, disciplineRepo := newDisciplineFixture(rule, []int{1, 1, 0}, []int{1, 0})
+
+	endOfFirst, err := service.GetPlayerStatus("p1", "League", utcDate(2025, 5, 1))
+	assert.NoError(t, err)
+	assert.Equal(t, 2, endOfFirst.YellowCards)
+	assert.True(t, endOfFirst.OneCardFromBan)
+
+	// the two yellows of 2024/25 do not carry over, so the yellow of 2025/26 triggers no ban
+	second, err := service.GetPlayerStatus("p1", "League", utcDate(2025, 10, 1))
+	assert.NoError(t, err)
+	assert.Equal(t, 1, second.YellowCards)
+	assert.False(t, second.OneCardFromBan)
+	assert.False(t, second.Suspended)
+	assert.Empty(t, disciplineRepo.suspensions)
+}
+
+func TestGetPlayerStatusDoesNotStoreSuspensions(t *testing.T) {
+	rule := &domain.DisciplineRule{Competition: "League", YellowThreshold: 2, YellowBanMatches: 1}
+	service, disciplineRepo := newDisciplineFixture(rule, []int{1, 1}, nil)
+
+	status, err := service.GetPlayerStatus("p1", "League", utcDate(2024, 9, 1))
+	assert.NoError(t, err)
+	assert.True(t, status.Suspended)
+	assert.Equal(t, 1, status.RemainingMatches)
+	assert.Empty(t, disciplineRepo.suspensions)
+
+	// the stats hook stores the ban once however often the row is written
+	stats, _ := service.playerStatsRepo.ListByPlayerID("p1")
+	match := &domain.Match{ID: stats[1].MatchID, Competition: "League", Date: utcDate(2024, 8, 17)}
+	assert.NoError(t, service.recordPlayerSuspensions("p1", match))
+	assert.NoError(t, service.recordPlayerSuspensions("p1", match))
+	assert.Len(t, disciplineRepo.suspensions, 1)
+
+	stored, err := service.GetPlayerStatus("p1", "League", utcDate(2024, 9, 1))
+	assert.NoError(t, err)
+	assert.Len(t, stored.Suspensions, 1)
+	assert.Equal(t, disciplineRepo.suspensions[0].ID, stored.Suspensions[0].ID)
+}
+
+func TestOneCardFromBanNeedsThresholdAboveOne(t *testing.T) {
+	rule := &domain.DisciplineRule{Competition: "League", YellowThreshold: 1, YellowBanMatches: 1}
+	service, _ := newDisciplineFixture(rule, []int{0}, nil)
+
+	status, err := service.GetPlayerStatus("p1", "League", utcDate(2024, 9, 1))
+	assert.NoError(t, err)
+	assert.Equal(t, 0, status.YellowCards)
+	assert.False(t, status.OneCardFromBan)
+}
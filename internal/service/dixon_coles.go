@@ -0,0 +1,244 @@
+package service
+
+import (
+	"football-analytics/internal/domain"
+	"math"
+	"time"
+)
+
+const (
+	defaultDecayRate = 0.0019
+	maxModelGoals    = 10
+	fitIterations    = 200
+	fitTolerance     = 1e-9
+	// strengthPrior pulls teams with few matches towards league average
+	strengthPrior = 1.0
+)
+
+// fitDixonColes fit team strengths, home advantage and rho on completed matches played before asOf,
+// older matches are down-weighted by exp(-decayRate * age in days)
+func fitDixonColes(matches []*domain.Match, asOf time.Time, decayRate float64) *domain.MatchModel {
+	type weightedMatch struct {
+		home, away int
+		homeGoals  float64
+		awayGoals  float64
+		weight     float64
+	}
+
+	index := make(map[string]int)
+	var teamIDs []string
+	teamIndex := func(teamID string) int {
+		if i, ok := index[teamID]; ok {
+			return i
+		}
+		index[teamID] = len(teamIDs)
+		teamIDs = append(teamIDs, teamID)
+		return index[teamID]
+	}
+
+	var data []weightedMatch
+	for _, match := range matches {
+		if match.Status != "completed" || !match.Date.Before(asOf) {
+			continue
+		}
+		age := asOf.Sub(match.Date).Hours() / 24
+		data = append(data, weightedMatch{
+			home:      teamIndex(match.HomeTeamID),
+			away:      teamIndex(match.AwayTeamID),
+			homeGoals: float64(match.HomeScore),
+			awayGoals: float64(match.AwayScore),
+			weight:    math.Exp(-decayRate * age),
+		})
+	}
+
+	n := len(teamIDs)
+	attack := make([]float64, n)
+	defence := make([]float64, n)
+	for i := range attack {
+		attack[i] = 1
+		defence[i] = 1
+	}
+	home := 1.0
+
+	// scored and conceded goals do not change between iterations
+	scored := make([]float64, n)
+	conceded := make([]float64, n)
+	var homeGoals float64
+	for _, m := range data {
+		scored[m.home] += m.weight * m.homeGoals
+		scored[m.away] += m.weight * m.awayGoals
+		conceded[m.home] += m.weight * m.awayGoals
+		conceded[m.away] += m.weight * m.homeGoals
+		homeGoals += m.weight * m.homeGoals
+	}
+
+	// maximum likelihood of the independent Poisson part, one parameter group at a time
+	for iter := 0; iter < fitIterations; iter++ {
+		exposure := make([]float64, n)
+		for _, m := range data {
+			exposure[m.home] += m.weight * defence[m.away] * home
+			exposure[m.away] += m.weight * defence[m.home]
+		}
+		for i := range attack {
+			attack[i] = (scored[i] + strengthPrior) / (exposure[i] + strengthPrior)
+		}
+
+		exposure = make([]float64, n)
+		var homeExposure float64
+		for _, m := range data {
+			exposure[m.away] += m.weight * attack[m.home] * home
+			exposure[m.home] += m.weight * attack[m.away]
+			homeExposure += m.weight * attack[m.home] * defence[m.away]
+		}
+		for i := range defence {
+			defence[i] = (conceded[i] + strengthPrior) / (exposure[i] + strengthPrior)
+		}
+
+		previous := home
+		if homeExposure > 0 {
+			home = homeGoals / homeExposure
+		}
+
+		// keep the geometric mean of attack at 1, defence takes the scale
+		var logSum float64
+		for _, a := range attack {
+			logSum += math.Log(a)
+		}
+		if n > 0 {
+			scale := math.Exp(logSum / float64(n))
+			for i := range attack {
+				attack[i] /= scale
+				defence[i] *= scale
+			}
+		}
+
+		if math.Abs(home-previous) < fitTolerance && iter > 0 {
+			break
+		}
+	}
+
+	// rho by grid search on the weighted low score correction likelihood
+	bestRho, bestLikelihood := 0.0, math.Inf(-1)
+	for rho := -0.25; rho <= 0.25+1e-9; rho += 0.005 {
+		likelihood := 0.0
+		for _, m := range data {
+			lambda := attack[m.home] * defence[m.away] * home
+			mu := attack[m.away] * defence[m.home]
+			tau := dixonColesTau(int(m.homeGoals), int(m.awayGoals), lambda, mu, rho)
+			if tau <= 0 {
+				likelihood = math.Inf(-1)
+				break
+			}
+			likelihood += m.weight * math.Log(tau)
+		}
+		if likelihood > bestLikelihood {
+			bestRho, bestLikelihood = rho, likelihood
+		}
+	}
+
+	model := &domain.MatchModel{
+		HomeAdvantage: home,
+		Rho:           bestRho,
+		DecayRate:     decayRate,
+		Strengths:     make(map[string]*domain.TeamStrength),
+		TrainedUntil:  asOf,
+		Matches:       len(data),
+	}
+	for i, teamID := range teamIDs {
+		model.Strengths[teamID] = &domain.TeamStrength{
+			TeamID:  teamID,
+			Attack:  attack[i],
+			Defence: defence[i],
+		}
+	}
+
+	return model
+}
+
+// predictWithModel score probabilities of a match between two teams, teams unknown
+// to the model are treated as league average
+func predictWithModel(model *domain.MatchModel, homeTeamID, awayTeamID string) *domain.MatchPrediction {
+	strength := func(teamID string) *domain.TeamStrength {
+		if s, ok := model.Strengths[teamID]; ok {
+			return s
+		}
+		return &domain.TeamStrength{TeamID: teamID, Attack: 1, Defence: 1}
+	}
+	homeStrength, awayStrength := strength(homeTeamID), strength(awayTeamID)
+
+	lambda := homeStrength.Attack * awayStrength.Defence * model.HomeAdvantage
+	mu := awayStrength.Attack * homeStrength.Defence
+
+	prediction := &domain.MatchPrediction{
+		HomeTeamID:        homeTeamID,
+		AwayTeamID:        awayTeamID,
+		ModelVersion:      model.Version,
+		ExpectedHomeGoals: lambda,
+		ExpectedAwayGoals: mu,
+		ScoreGrid:         make([][]float64, maxModelGoals+1),
+	}
+
+	var total float64
+	for x := 0; x <= maxModelGoals; x++ {
+		prediction.ScoreGrid[x] = make([]float64, maxModelGoals+1)
+		for y := 0; y <= maxModelGoals; y++ {
+			p := dixonColesTau(x, y, lambda, mu, model.Rho) * poissonProbability(x, lambda) * poissonProbability(y, mu)
+			if p < 0 {
+				p = 0
+			}
+			prediction.ScoreGrid[x][y] = p
+			total += p
+		}
+	}
+
+	// the grid is truncated at maxModelGoals, normalize so it sums to 1
+	for x := range prediction.ScoreGrid {
+		for y := range prediction.ScoreGrid[x] {
+			if total > 0 {
+				prediction.ScoreGrid[x][y] /= total
+			}
+			p := prediction.ScoreGrid[x][y]
+			switch {
+			case x > y:
+				prediction.HomeWin += p
+			case x == y:
+				prediction.Draw += p
+			default:
+				prediction.AwayWin += p
+			}
+		}
+	}
+
+	return prediction
+}
+
+// dixonColesTau low score dependence correction of the independent Poisson model
+func dixonColesTau(x, y int, lambda, mu, rho float64) float64 {
+	switch {
+	case x == 0 && y == 0:
+		return 1 - lambda*mu*rho
+	case x == 0 && y == 1:
+		return 1 + lambda*rho
+	case x == 1 && y == 0:
+		return 1 + mu*rho
+	case x == 1 && y == 1:
+		return 1 - rho
+	default:
+		return 1
+	}
+}
+
+// poissonProbability probability of k events with rate lambda
+func poissonProbability(k int, lambda float64) float64 {
+	if lambda <= 0 {
+		if k == 0 {
+			return 1
+		}
+		return 0
+	}
+	logP := float64(k)*math.Log(lambda) - lambda
+	for i := 2; i <= k; i++ {
+		logP -= math.Log(float64(i))
+	}
+	return math.Exp(logP)
+}
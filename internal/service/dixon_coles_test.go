@@ -0,0 +1,156 @@
+package service
+
+import (
+	"fmt"
+	"football-analytics/internal/domain"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+)
+
+// leagueFixture create a double round robin of completed matches where "strong" beats
+// everyone, "weak" loses to everyone and the rest draw
+func leagueFixture(start time.Time) []*domain.Match {
+	teams := []string{"strong", "mid1", "mid2", "weak"}
+	goals := map[string]int{"strong": 3, "mid1": 1, "mid2": 1, "weak": 0}
+
+	var matches []*domain.Match
+	day := 0
+	for round := 0; round < 3; round++ {
+		for _, home := range teams {
+			for _, away := range teams {
+				if home == away {
+					continue
+				}
+				matches = append(matches, &domain.Match{
+					ID:         home + "-" + away,
+					HomeTeamID: home,
+					AwayTeamID: away,
+					Date:       start.AddDate(0, 0, day),
+					HomeScore:  goals[home],
+					AwayScore:  goals[away],
+					Status:     "completed",
+				})
+				day++
+			}
+		}
+	}
+
+	return matches
+}
+
+func TestFitDixonColes(t *testing.T) {
+	start := time.Date(2024, 8, 1, 0, 0, 0, 0, time.UTC)
+	matches := leagueFixture(start)
+	// a scheduled match must not be used for fitting
+	matches = append(matches, &domain.Match{HomeTeamID: "weak", AwayTeamID: "strong", Date: start, Status: "scheduled", HomeScore: 9})
+
+	model := fitDixonColes(matches, start.AddDate(1, 0, 0), defaultDecayRate)
+
+	assert.Equal(t, 36, model.Matches)
+	assert.Len(t, model.Strengths, 4)
+	assert.Greater(t, model.Strengths["strong"].Attack, model.Strengths["mid1"].Attack)
+	assert.Greater(t, model.Strengths["mid1"].Attack, model.Strengths["weak"].Attack)
+	assert.Less(t, model.Strengths["strong"].Defence, model.Strengths["weak"].Defence)
+	assert.InDelta(t, model.Strengths["mid1"].Attack, model.Strengths["mid2"].Attack, 1e-3)
+}
+
+func TestPredictWithModel(t *testing.T) {
+	start := time.Date(2024, 8, 1, 0, 0, 0, 0, time.UTC)
+	model := fitDixonColes(leagueFixture(start), start.AddDate(1, 0, 0), defaultDecayRate)
+	model.Version = "test"
+
+	prediction := predictWithModel(model, "strong", "weak")
+
+	assert.Equal(t, "test", prediction.ModelVersion)
+	assert.InDelta(t, 1, prediction.HomeWin+prediction.Draw+prediction.AwayWin, 1e-9)
+	assert.Greater(t, prediction.HomeWin, prediction.AwayWin)
+	assert.Greater(t, prediction.ExpectedHomeGoals, prediction.ExpectedAwayGoals)
+
+	var total float64
+	for _, row := range prediction.ScoreGrid {
+		for _, p := range row {
+			assert.GreaterOrEqual(t, p, 0.0)
+			total += p
+		}
+	}
+	assert.InDelta(t, 1, total, 1e-9)
+
+	// unknown teams are league average
+	unknown := predictWithModel(model, "new1", "new2")
+	assert.InDelta(t, model.HomeAdvantage, unknown.ExpectedHomeGoals, 1e-9)
+	assert.InDelta(t, 1, unknown.ExpectedAwayGoals, 1e-9)
+}
+
+// memoryModelRepository keep saved match models in memory, the last saved is the latest
+type memoryModelRepository struct {
+	models []*domain.MatchModel
+}
+
+func (r *memoryModelRepository) Save(model *domain.MatchModel) error {
+	for _, saved := range r.models {
+		if saved.Version == model.Version {
+			return fmt.Errorf("duplicate model version %q", model.Version)
+		}
+	}
+	r.models = append(r.models, model)
+	return nil
+}
+
+func (r *memoryModelRepository) GetLatest() (*domain.MatchModel, error) {
+	if len(r.models) == 0 {
+		return nil, domain.ErrNotFound
+	}
+	return r.models[len(r.models)-1], nil
+}
+
+func (r *memoryModelRepository) GetByVersion(version string) (*domain.MatchModel, error) {
+	for _, model := range r.models {
+		if model.Version == version {
+			return model, nil
+		}
+	}
+	return nil, domain.ErrNotFound
+}
+
+func TestPredictScheduledMatches(t *testing.T) {
+	start := time.Date(2024, 8, 1, 0, 0, 0, 0, time.UTC)
+	model := fitDixonColes(leagueFixture(start), start.AddDate(1, 0, 0), defaultDecayRate)
+	modelRepo := &memoryModelRepository{models: []*domain.MatchModel{model}}
+	matchRepo := new(MockMatchRepository)
+	matchRepo.On("ListByDateRange", utcDate(2025, 5, 1), utcDate(2025, 5, 2).Add(-time.Nanosecond)).Return([]*domain.Match{
+		{ID: "played", HomeTeamID: "strong", AwayTeamID: "weak", Status: "completed"},
+		{ID: "next", HomeTeamID: "strong", AwayTeamID: "weak", Date: utcDate(2025, 5, 1).Add(20 * time.Hour), Status: "scheduled"},
+	}, nil)
+	analytics := NewAnalyticsService(nil, nil, matchRepo, nil, modelRepo, nil, nil, nil)
+
+	// the end date is included
+	predictions, err := analytics.PredictScheduledMatches("2025-05-01", "2025-05-01")
+
+	assert.NoError(t, err)
+	if assert.Len(t, predictions, 1) {
+		assert.Equal(t, "next", predictions[0].MatchID)
+	}
+
+	_, err = analytics.PredictScheduledMatches("2025-05-02", "2025-05-01")
+	assert.ErrorIs(t, err, domain.ErrInvalidTimeRange)
+}
+
+func TestFitModelVersionsAreUnique(t *testing.T) {
+	matchRepo := new(MockMatchRepository)
+	matchRepo.On("List").Return(leagueFixture(utcDate(2024, 8, 1)), nil)
+	modelRepo := &memoryModelRepository{}
+	models := NewMatchModelService(matchRepo, modelRepo)
+
+	// fits in the same second must not collide on the unique version
+	first, err := models.FitModel(utcDate(2025, 8, 1), domain.MatchModelOptions{})
+	assert.NoError(t, err)
+	second, err := models.FitModel(utcDate(2025, 8, 1), domain.MatchModelOptions{})
+	assert.NoError(t, err)
+
+	assert.NotEqual(t, first.Version, second.Version)
+	latest, err := models.GetLatestModel()
+	assert.NoError(t, err)
+	assert.Equal(t, second.ID, latest.ID)
+}
@@ -0,0 +1,283 @@
+package service
+
+import (
+	"errors"
+	"football-analytics/internal/domain"
+	"log"
+	"math"
+	"sort"
+	"time"
+
+	"github.com/google/uuid"
+)
+
+const (
+	// defaultSnapshotRange time range of the snapshots taken after stats writes and of history queries
+	defaultSnapshotRange = "season"
+	// defaultRatingChangeThreshold smallest rating change reported when the query has no threshold
+	defaultRatingChangeThreshold = 1.0
+)
+
+// HistoryService is interface for persisted player metrics snapshots, register it as observer
+// of the observed stats repository to snapshot a player after each write of their stats
+type HistoryService interface {
+	domain.StatsObserver
+	SnapshotPlayer(playerID string, date time.Time) (*domain.MetricsSnapshot, error)
+	Backfill(playerID string) (int, error)
+	GetTrend(playerID string, query domain.TrendQuery) (*domain.MetricsTrend, error)
+	DetectRatingChanges(query domain.RatingChangeQuery) ([]*domain.RatingChange, error)
+}
+
+type historyService struct {
+	snapshotRepo    domain.SnapshotRepository
+	playerStatsRepo domain.PlayerMatchStatsRepository
+	matchRepo       domain.MatchRepository
+	analytics       *analyticsService
+	clock           domain.Clock
+}
+
+// NewHistoryService create instance of HistoryService, a nil clock is the system clock
+func NewHistoryService(
+	snapshotRepo domain.SnapshotRepository,
+	playerStatsRepo domain.PlayerMatchStatsRepository,
+	playerRepo domain.PlayerRepository,
+	matchRepo domain.MatchRepository,
+	seasonRepo domain.SeasonRepository,
+	clock domain.Clock,
+) HistoryService {
+	if clock == nil {
+		clock = domain.SystemClock{}
+	}
+
+	return &historyService{
+		snapshotRepo:    snapshotRepo,
+		playerStatsRepo: playerStatsRepo,
+		matchRepo:       matchRepo,
+		analytics: &analyticsService{
+			playerStatsRepo: playerStatsRepo,
+			playerRepo:      playerRepo,
+			matchRepo:       matchRepo,
+			calendar:        newSeasonCalendar(seasonRepo),
+		},
+		clock: clock,
+	}
+}
+
+// SnapshotPlayer calculate the metrics of a player as they were at the end of date and store them
+func (s *historyService) SnapshotPlayer(playerID string, date time.Time) (*domain.MetricsSnapshot, error) {
+	return s.snapshot(playerID, snapshotDay(date), defaultSnapshotRange)
+}
+
+// Backfill snapshot a player at every date they played on, it returns the number of snapshots
+func (s *historyService) Backfill(playerID string) (int, error) {
+	rows, err := s.playerStatsRepo.ListByPlayerAndDateRange(playerID, time.Time{}, s.clock.Now())
+	if err != nil {
+		return 0, err
+	}
+
+	days := make(map[time.Time]bool)
+	for _, row := range rows {
+		days[snapshotDay(row.Match.Date)] = true
+	}
+
+	for day := range days {
+		if _, err := s.snapshot(playerID, day, defaultSnapshotRange); err != nil {
+			return 0, err
+		}
+	}
+
+	return len(days), nil
+}
+
+// StatsChanged snapshot the player again at the date of the changed match and at every later
+// snapshot date, their metrics include the changed row, a failed snapshot is logged
+func (s *historyService) StatsChanged(old, new *domain.PlayerMatchStats) {
+	stats := new
+	if stats == nil {
+		stats = old
+	}
+
+	match, err := s.matchRepo.GetByID(stats.MatchID)
+	if err != nil {
+		log.Printf("history: match %s of stats %s: %v", stats.MatchID, stats.ID, err)
+		return
+	}
+	day := snapshotDay(match.Date)
+
+	later, err := s.snapshotRepo.ListByPlayer(stats.PlayerID, defaultSnapshotRange, analyticsModelVersion, day.AddDate(0, 0, 1), time.Time{})
+	if err != nil {
+		log.Printf("history: snapshots of player %s: %v", stats.PlayerID, err)
+		return
+	}
+
+	days := []time.Time{day}
+	for _, snapshot := range later {
+		days = append(days, snapshot.Date)
+	}
+	for _, day := range days {
+		if _, err := s.snapshot(stats.PlayerID, day, defaultSnapshotRange); err != nil {
+			log.Printf("history: snapshot player %s at %s: %v", stats.PlayerID, day.Format(domain.DateLayout), err)
+		}
+	}
+}
+
+// GetTrend get the snapshots of a player in the query period with the direction of the rating,
+// only snapshots of the current model version are compared
+func (s *historyService) GetTrend(playerID string, query domain.TrendQuery) (*domain.MetricsTrend, error) {
+	timeRange, err := snapshotRange(query.TimeRange)
+	if err != nil {
+		return nil, err
+	}
+
+	snapshots, err := s.snapshotRepo.ListByPlayer(playerID, timeRange, analyticsModelVersion, query.StartDate, query.EndDate)
+	if err != nil {
+		return nil, err
+	}
+
+	trend := &domain.MetricsTrend{
+		PlayerID:     playerID,
+		TimeRange:    timeRange,
+		ModelVersion: analyticsModelVersion,
+		Snapshots:    snapshots,
+	}
+	trend.RatingChange, trend.SlopePerMonth = ratingTrend(snapshots)
+
+	return trend, nil
+}
+
+// DetectRatingChanges compare the snapshot of every player in effect at the query from date with the
+// one in effect at the to date, players whose rating moved by at least the threshold are returned
+// ordered by the size of the change, players without a snapshot at the from date are left out
+func (s *historyService) DetectRatingChanges(query domain.RatingChangeQuery) ([]*domain.RatingChange, error) {
+	if !query.To.After(query.From) {
+		return nil, errors.New("rating change query needs to be after from")
+	}
+
+	timeRange, err := snapshotRange(query.TimeRange)
+	if err != nil {
+		return nil, err
+	}
+
+	threshold := query.Threshold
+	if threshold <= 0 {
+		threshold = defaultRatingChangeThreshold
+	}
+
+	before, err := s.snapshotRepo.ListLatest(query.From, timeRange, analyticsModelVersion)
+	if err != nil {
+		return nil, err
+	}
+	after, err := s.snapshotRepo.ListLatest(query.To, timeRange, analyticsModelVersion)
+	if err != nil {
+		return nil, err
+	}
+
+	return ratingChanges(before, after, threshold), nil
+}
+
+// snapshot calculate the metrics of a player in a time range as of the end of day and store them
+func (s *historyService) snapshot(playerID string, day time.Time, timeRange string) (*domain.MetricsSnapshot, error) {
+	analytics := *s.analytics
+	analytics.clock = domain.FixedClock(day.AddDate(0, 0, 1))
+
+	metrics, err := analytics.CalculatePlayerPerformance(playerID, timeRange)
+	if err != nil {
+		return nil, err
+	}
+
+	snapshot := &domain.MetricsSnapshot{
+		ID:           uuid.New().String(),
+		PlayerID:     playerID,
+		Date:         day,
+		TimeRange:    timeRange,
+		ModelVersion: analyticsModelVersion,
+		Metrics:      metrics,
+		CreatedAt:    time.Now(),
+	}
+	if err := s.snapshotRepo.Save(snapshot); err != nil {
+		return nil, err
+	}
+
+	return snapshot, nil
+}
+
+// snapshotRange canonical form of a snapshot time range, default season
+func snapshotRange(timeRange string) (string, error) {
+	if timeRange == "" {
+		timeRange = defaultSnapshotRange
+	}
+	tr, err := domain.ParseTimeRange(timeRange)
+	if err != nil {
+		return "", err
+	}
+	return tr.String(), nil
+}
+
+// snapshotDay the UTC day of a match date
+func snapshotDay(date time.Time) time.Time {
+	date = date.UTC()
+	return time.Date(date.Year(), date.Month(), date.Day(), 0, 0, 0, 0, time.UTC)
+}
+
+// ratingTrend last minus first overall rating of the ordered snapshots and the least squares
+// slope of the rating per 30 days
+func ratingTrend(snapshots []*domain.MetricsSnapshot) (float64, float64) {
+	if len(snapshots) < 2 {
+		return 0, 0
+	}
+
+	change := snapshots[len(snapshots)-1].Metrics.OverallRating - snapshots[0].Metrics.OverallRating
+
+	var sumX, sumY float64
+	for _, snapshot := range snapshots {
+		sumX += snapshot.Date.Sub(snapshots[0].Date).Hours() / 24
+		sumY += snapshot.Metrics.OverallRating
+	}
+	n := float64(len(snapshots))
+	meanX, meanY := sumX/n, sumY/n
+
+	var covariance, variance float64
+	for _, snapshot := range snapshots {
+		x := snapshot.Date.Sub(snapshots[0].Date).Hours()/24 - meanX
+		covariance += x * (snapshot.Metrics.OverallRating - meanY)
+		variance += x * x
+	}
+	if variance == 0 {
+		return change, 0
+	}
+
+	return change, covariance / variance * 30
+}
+
+// ratingChanges pair the snapshots of each player and keep the changes of at least threshold,
+// largest first
+func ratingChanges(before, after []*domain.MetricsSnapshot, threshold float64) []*domain.RatingChange {
+	from := make(map[string]*domain.MetricsSnapshot)
+	for _, snapshot := range before {
+		from[snapshot.PlayerID] = snapshot
+	}
+
+	var changes []*domain.RatingChange
+	for _, to := range after {
+		previous, ok := from[to.PlayerID]
+		if !ok {
+			continue
+		}
+		change := to.Metrics.OverallRating - previous.Metrics.OverallRating
+		if math.Abs(change) < threshold {
+			continue
+		}
+		changes = append(changes, &domain.RatingChange{
+			PlayerID: to.PlayerID,
+			From:     previous,
+			To:       to,
+			Change:   change,
+		})
+	}
+
+	sort.SliceStable(changes, func(i, j int) bool {
+		return math.Abs(changes[i].Change) > math.Abs(changes[j].Change)
+	})
+
+	return changes
+}
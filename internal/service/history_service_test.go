@@ -0,0 +1,42 @@
+package service
+
+import (
+	"football-analytics/internal/domain"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestRatingTrend(t *testing.T) {
+	snapshot := func(playerID string, month time.Month, day int, rating float64) *domain.MetricsSnapshot {
+		return &domain.MetricsSnapshot{
+			PlayerID: playerID,
+			Date:     utcDate(2024, month, day),
+			Metrics:  &domain.PerformanceMetrics{PlayerID: playerID, OverallRating: rating},
+		}
+	}
+
+	change, slope := ratingTrend([]*domain.MetricsSnapshot{
+		snapshot("p1", time.September, 1, 10),
+		snapshot("p1", time.September, 16, 11),
+		snapshot("p1", time.October, 1, 12),
+	})
+	assert.InDelta(t, 2, change, 1e-9)
+	assert.InDelta(t, 2, slope, 1e-9)
+
+	change, slope = ratingTrend([]*domain.MetricsSnapshot{snapshot("p1", time.September, 1, 10)})
+	assert.Zero(t, change)
+	assert.Zero(t, slope)
+
+	changes := ratingChanges(
+		[]*domain.MetricsSnapshot{snapshot("p1", time.September, 1, 10), snapshot("p2", time.September, 1, 10), snapshot("p3", time.September, 1, 10)},
+		[]*domain.MetricsSnapshot{snapshot("p1", time.October, 1, 12), snapshot("p2", time.October, 1, 5), snapshot("p3", time.October, 1, 10.5), snapshot("p4", time.October, 1, 20)},
+		1,
+	)
+	if assert.Len(t, changes, 2) {
+		assert.Equal(t, "p2", changes[0].PlayerID)
+		assert.InDelta(t, -5, changes[0].Change, 1e-9)
+		assert.Equal(t, "p1", changes[1].PlayerID)
+	}
+}
@@ -0,0 +1,72 @@
+package service
+
+import (
+	"math/rand"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+// bruteForceAssignment minimal total cost over every assignment of rows to distinct columns
+func bruteForceAssignment(cost [][]float64) float64 {
+	best := -1.0
+	used := make([]bool, len(cost[0]))
+
+	var search func(row int, total float64)
+	search = func(row int, total float64) {
+		if row == len(cost) {
+			if best < 0 || total < best {
+				best = total
+			}
+			return
+		}
+		for j := range cost[row] {
+			if used[j] {
+				continue
+			}
+			used[j] = true
+			search(row+1, total+cost[row][j])
+			used[j] = false
+		}
+	}
+	search(0, 0)
+
+	return best
+}
+
+func TestHungarian(t *testing.T) {
+	cost := [][]float64{
+		{4, 1, 3},
+		{2, 0, 5},
+		{3, 2, 2},
+	}
+
+	assert.Equal(t, []int{1, 0, 2}, hungarian(cost))
+}
+
+func TestHungarianMatchesBruteForce(t *testing.T) {
+	random := rand.New(rand.NewSource(1))
+
+	for n := 1; n <= 5; n++ {
+		for m := n; m <= 7; m++ {
+			cost := make([][]float64, n)
+			for i := range cost {
+				cost[i] = make([]float64, m)
+				for j := range cost[i] {
+					cost[i][j] = float64(random.Intn(20))
+				}
+			}
+
+			assignment := hungarian(cost)
+
+			var total float64
+			seen := make(map[int]bool)
+			for i, j := range assignment {
+				assert.False(t, seen[j], "column assigned twice")
+				seen[j] = true
+				total += cost[i][j]
+			}
+			assert.Equal(t, bruteForceAssignment(cost), total)
+		}
+	}
+}
@@ -0,0 +1,72 @@
+package service
+
+import (
+	"fmt"
+	"football-analytics/internal/domain"
+)
+
+const (
+	defaultLeaderboardLimit = 10
+	maxLeaderboardLimit     = 100
+)
+
+// LeaderboardService is interface for metric leaderboards
+type LeaderboardService interface {
+	GetLeaderboard(query domain.LeaderboardQuery) ([]*domain.LeaderboardEntry, error)
+}
+
+type leaderboardService struct {
+	leaderboardRepo domain.LeaderboardRepository
+	calendar        *seasonCalendar
+	clock           domain.Clock
+}
+
+// NewLeaderboardService create instance of LeaderboardService
+func NewLeaderboardService(leaderboardRepo domain.LeaderboardRepository, seasonRepo domain.SeasonRepository, clock domain.Clock) LeaderboardService {
+	if clock == nil {
+		clock = domain.SystemClock{}
+	}
+
+	return &leaderboardService{
+		leaderboardRepo: leaderboardRepo,
+		calendar:        newSeasonCalendar(seasonRepo),
+		clock:           clock,
+	}
+}
+
+// GetLeaderboard get the top players by a metric
+func (s *leaderboardService) GetLeaderboard(query domain.LeaderboardQuery) ([]*domain.LeaderboardEntry, error) {
+	counting, ok := domain.LeaderboardMetrics[query.Metric]
+	if !ok {
+		return nil, fmt.Errorf("unknown leaderboard metric %q", query.Metric)
+	}
+	if query.Per90 && !counting {
+		return nil, fmt.Errorf("metric %q can not be expressed per 90 minutes", query.Metric)
+	}
+
+	if query.Limit == 0 {
+		query.Limit = defaultLeaderboardLimit
+	}
+	if query.Limit < 0 || query.Limit > maxLeaderboardLimit {
+		return nil, fmt.Errorf("leaderboard limit must be between 1 and %d", maxLeaderboardLimit)
+	}
+	if query.MinAge < 0 || query.MaxAge < 0 || query.MinMinutes < 0 {
+		return nil, fmt.Errorf("invalid leaderboard filter")
+	}
+
+	if query.Season != "" {
+		if !query.StartDate.IsZero() || !query.EndDate.IsZero() {
+			return nil, fmt.Errorf("leaderboard season can not be combined with dates")
+		}
+		start, end, label, err := s.calendar.labelRange(query.Season, query.Competition)
+		if err != nil {
+			return nil, err
+		}
+		query.StartDate, query.EndDate, query.Season = start, end, label
+	}
+	if query.AsOf.IsZero() {
+		query.AsOf = s.clock.Now()
+	}
+
+	return s.leaderboardRepo.Top(query)
+}
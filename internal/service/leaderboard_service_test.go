@@ -0,0 +1,53 @@
+package service
+
+import (
+	"football-analytics/internal/domain"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+// queryLeaderboardRepository record the query the leaderboard is read with
+type queryLeaderboardRepository struct {
+	query *domain.LeaderboardQuery
+}
+
+func (r *queryLeaderboardRepository) Top(query domain.LeaderboardQuery) ([]*domain.LeaderboardEntry, error) {
+	r.query = &query
+	return nil, nil
+}
+
+func TestGetLeaderboardSeason(t *testing.T) {
+	repo := &queryLeaderboardRepository{}
+	service := NewLeaderboardService(repo, &memorySeasonRepository{seasons: []*domain.Season{
+		{ID: "pl-2024", Competition: "Premier League", Label: "2024/25", StartDate: utcDate(2024, 8, 16), EndDate: utcDate(2025, 5, 26)},
+	}}, domain.FixedClock(utcDate(2025, 6, 1)))
+
+	_, err := service.GetLeaderboard(domain.LeaderboardQuery{Metric: "goals", Season: "2024/25", Competition: "Premier League"})
+	assert.NoError(t, err)
+	assert.Equal(t, utcDate(2024, 8, 16), repo.query.StartDate)
+	assert.Equal(t, utcDate(2025, 5, 26), repo.query.EndDate)
+	assert.Equal(t, "2024/25", repo.query.Season)
+	assert.Equal(t, defaultLeaderboardLimit, repo.query.Limit)
+	assert.Equal(t, utcDate(2025, 6, 1), repo.query.AsOf)
+}
+
+func TestGetLeaderboardInvalidQuery(t *testing.T) {
+	repo := &queryLeaderboardRepository{}
+	service := NewLeaderboardService(repo, nil, nil)
+
+	for _, tc := range []struct {
+		query domain.LeaderboardQuery
+		err   string
+	}{
+		{domain.LeaderboardQuery{Metric: "saves"}, `unknown leaderboard metric "saves"`},
+		{domain.LeaderboardQuery{Metric: "pass_accuracy", Per90: true}, `metric "pass_accuracy" can not be expressed per 90 minutes`},
+		{domain.LeaderboardQuery{Metric: "goals", Limit: maxLeaderboardLimit + 1}, "leaderboard limit must be between 1 and 100"},
+		{domain.LeaderboardQuery{Metric: "goals", MinAge: -1}, "invalid leaderboard filter"},
+		{domain.LeaderboardQuery{Metric: "goals", Season: "2024/25", StartDate: utcDate(2024, 9, 1)}, "leaderboard season can not be combined with dates"},
+	} {
+		_, err := service.GetLeaderboard(tc.query)
+		assert.EqualError(t, err, tc.err)
+	}
+	assert.Nil(t, repo.query)
+}
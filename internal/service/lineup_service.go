@@ -0,0 +1,307 @@
+package service
+
+import (
+	"fmt"
+	"football-analytics/internal/domain"
+	"sort"
+	"time"
+)
+
+const (
+	// secondaryPositionFactor rating multiplier of a player out of the natural position of a slot
+	secondaryPositionFactor = 0.85
+	// minStarterMinutes players limited to less minutes do not start
+	minStarterMinutes  = 60
+	lineupAlternatives = 3
+	// forbiddenCost cost of leaving an optional slot empty, requiredForbiddenCost of a required one so
+	// the assignment fills required slots first
+	forbiddenCost         = 1e9
+	requiredForbiddenCost = 1e12
+)
+
+// positionRatingWeights weight of each rating component at the natural position of a slot, components
+// left out weigh 1 and positions left out rate like the overall rating
+var positionRatingWeights = map[string]map[string]float64{
+	"Goalkeeper": {"goals_per_minute": 0, "assists_per_minute": 0, "shot_accuracy": 0, "pass_accuracy": 2},
+	"Defender":   {"goals_per_minute": 0.5, "assists_per_minute": 0.5, "shot_accuracy": 0.5, "defensive_efficiency": 3, "pass_accuracy": 1.5},
+	"Midfielder": {"assists_per_minute": 2, "pass_accuracy": 2, "stamina": 1.5},
+	"Forward":    {"goals_per_minute": 3, "shot_accuracy": 2, "assists_per_minute": 1.5, "defensive_efficiency": 0.5},
+}
+
+// LineupService is interface for picking the best starting XI of a team
+type LineupService interface {
+	BestLineup(request domain.LineupRequest) (*domain.Lineup, error)
+}
+
+type lineupService struct {
+	analytics  *analyticsService
+	discipline DisciplineService
+	workload   WorkloadService
+	playerRepo domain.PlayerRepository
+	matchRepo  domain.MatchRepository
+}
+
+// NewLineupService create instance of LineupService
+func NewLineupService(
+	discipline DisciplineService,
+	workload WorkloadService,
+	playerStatsRepo domain.PlayerMatchStatsRepository,
+	playerRepo domain.PlayerRepository,
+	matchRepo domain.MatchRepository,
+	seasonRepo domain.SeasonRepository,
+) LineupService {
+	return &lineupService{
+		analytics: &analyticsService{
+			playerStatsRepo: playerStatsRepo,
+			playerRepo:      playerRepo,
+			matchRepo:       matchRepo,
+			calendar:        newSeasonCalendar(seasonRepo),
+		},
+		discipline: discipline,
+		workload:   workload,
+		playerRepo: playerRepo,
+		matchRepo:  matchRepo,
+	}
+}
+
+// BestLineup assign available players of the team to the formation slots so that the total
+// rating is maximal, suspended, unavailable and workload-limited players are left out, players
+// are rated on their season up to the request date for the natural position of each slot
+func (s *lineupService) BestLineup(request domain.LineupRequest) (*domain.Lineup, error) {
+	formation, ok := domain.Formations[request.Formation]
+	if !ok {
+		return nil, fmt.Errorf("unknown formation %q", request.Formation)
+	}
+
+	players, excluded, err := s.availablePlayers(request)
+	if err != nil {
+		return nil, err
+	}
+
+	analytics := *s.analytics
+	analytics.clock = domain.FixedClock(request.Date)
+
+	metrics := make(map[string]*domain.PerformanceMetrics)
+	for _, player := range players {
+		playerMetrics, err := analytics.CalculatePlayerPerformance(player.ID, "season")
+		if err != nil {
+			return nil, err
+		}
+		metrics[player.ID] = playerMetrics
+	}
+
+	// score of every player at every slot, nil if the player can not play there
+	scores := make([][]*domain.LineupCandidate, len(formation.Slots))
+	maxScore := 0.0
+	for i, slot := range formation.Slots {
+		scores[i] = make([]*domain.LineupCandidate, len(players))
+		for j, player := range players {
+			factor := slotFactor(slot, player.Position)
+			if factor == 0 {
+				continue
+			}
+			scores[i][j] = &domain.LineupCandidate{
+				PlayerID: player.ID,
+				Name:     player.Name,
+				Position: player.Position,
+				Rating:   positionRating(metrics[player.ID], slot.Positions[0]) * factor,
+			}
+			if scores[i][j].Rating > maxScore {
+				maxScore = scores[i][j].Rating
+			}
+		}
+	}
+
+	required := request.RequiredSlots
+	if len(required) == 0 {
+		for _, slot := range formation.Slots {
+			required = append(required, slot.Name)
+		}
+	}
+
+	// minimize the rating given up against the best possible score, padded to a square matrix
+	// with forbidden columns so every slot gets a column even for small squads
+	columns := len(players)
+	if columns < len(formation.Slots) {
+		columns = len(formation.Slots)
+	}
+	cost := make([][]float64, len(formation.Slots))
+	for i, slot := range formation.Slots {
+		empty := forbiddenCost
+		if containsString(required, slot.Name) {
+			empty = requiredForbiddenCost
+		}
+		cost[i] = make([]float64, columns)
+		for j := range cost[i] {
+			cost[i][j] = empty
+			if j < len(players) && scores[i][j] != nil {
+				cost[i][j] = maxScore - scores[i][j].Rating
+			}
+		}
+	}
+	assignment := hungarian(cost)
+
+	lineup := &domain.Lineup{
+		TeamID:    request.TeamID,
+		Formation: formation.Name,
+		Date:      request.Date,
+		Excluded:  excluded,
+	}
+
+	selected := make(map[string]bool)
+	for i, slot := range formation.Slots {
+		lineupSlot := &domain.LineupSlot{Slot: slot.Name}
+		if j := assignment[i]; cost[i][j] < forbiddenCost {
+			lineupSlot.Player = scores[i][j]
+			lineup.TotalRating += scores[i][j].Rating
+			selected[scores[i][j].PlayerID] = true
+		}
+		lineup.Slots = append(lineup.Slots, lineupSlot)
+	}
+
+	for _, lineupSlot := range lineup.Slots {
+		if lineupSlot.Player == nil && containsString(required, lineupSlot.Slot) {
+			return nil, fmt.Errorf("no available player for required slot %s", lineupSlot.Slot)
+		}
+	}
+
+	// alternatives are the best eligible players left on the bench
+	for i, lineupSlot := range lineup.Slots {
+		var bench []*domain.LineupCandidate
+		for _, candidate := range scores[i] {
+			if candidate != nil && !selected[candidate.PlayerID] {
+				bench = append(bench, candidate)
+			}
+		}
+		sort.SliceStable(bench, func(a, b int) bool {
+			return bench[a].Rating > bench[b].Rating
+		})
+		if len(bench) > lineupAlternatives {
+			bench = bench[:lineupAlternatives]
+		}
+		lineupSlot.Alternatives = bench
+	}
+
+	return lineup, nil
+}
+
+// availablePlayers players of the team that can start at the request date and the ones left out with a reason
+func (s *lineupService) availablePlayers(request domain.LineupRequest) ([]*domain.Player, []*domain.ExcludedPlayer, error) {
+	allPlayers, err := s.playerRepo.List()
+	if err != nil {
+		return nil, nil, err
+	}
+
+	suspended, err := s.suspendedPlayers(request.TeamID, request.Date)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	var workloads map[string]*domain.PlayerWorkload
+	if request.MaxWorkloadRatio > 0 {
+		report, err := s.workload.GetTeamWorkloadReport(request.TeamID, request.Date)
+		if err != nil {
+			return nil, nil, err
+		}
+		workloads = make(map[string]*domain.PlayerWorkload)
+		for _, workload := range report.Players {
+			workloads[workload.PlayerID] = workload
+		}
+	}
+
+	var players []*domain.Player
+	var excluded []*domain.ExcludedPlayer
+	for _, player := range allPlayers {
+		if player.TeamID != request.TeamID {
+			continue
+		}
+
+		reason := ""
+		if containsString(request.Unavailable, player.ID) {
+			reason = "unavailable"
+		} else if suspended[player.ID] {
+			reason = "suspended"
+		} else if limit, ok := request.MaxMinutes[player.ID]; ok && limit < minStarterMinutes {
+			reason = fmt.Sprintf("limited to %d minutes", limit)
+		} else if workload, ok := workloads[player.ID]; ok && workload.WorkloadRatio > request.MaxWorkloadRatio {
+			reason = fmt.Sprintf("workload ratio %.2f", workload.WorkloadRatio)
+		}
+
+		if reason != "" {
+			excluded = append(excluded, &domain.ExcludedPlayer{PlayerID: player.ID, Name: player.Name, Reason: reason})
+			continue
+		}
+		players = append(players, player)
+	}
+
+	return players, excluded, nil
+}
+
+// suspendedPlayers players suspended for the first match of the team on or after date
+func (s *lineupService) suspendedPlayers(teamID string, date time.Time) (map[string]bool, error) {
+	matches, err := s.matchRepo.ListByTeamID(teamID)
+	if err != nil {
+		return nil, err
+	}
+
+	var next *domain.Match
+	for _, match := range matches {
+		if match.Status != "scheduled" || match.Date.Before(date) {
+			continue
+		}
+		if next == nil || match.Date.Before(next.Date) {
+			next = match
+		}
+	}
+
+	suspended := make(map[string]bool)
+	if next == nil {
+		return suspended, nil
+	}
+
+	report, err := s.discipline.GetMatchDisciplineReport(next.ID)
+	if err != nil {
+		return nil, err
+	}
+	for _, status := range report.Suspended {
+		suspended[status.PlayerID] = true
+	}
+
+	return suspended, nil
+}
+
+// slotFactor rating multiplier of a player position at a slot, 0 if the player can not play there
+func slotFactor(slot domain.FormationSlot, position string) float64 {
+	for i, p := range slot.Positions {
+		if p == position {
+			if i == 0 {
+				return 1
+			}
+			return secondaryPositionFactor
+		}
+	}
+	return 0
+}
+
+// positionRating rating of the metrics weighted for a position, the weighted mean of the rating
+// components brought to the common scale
+func positionRating(metrics *domain.PerformanceMetrics, position string) float64 {
+	weights, ok := positionRatingWeights[position]
+	if !ok {
+		return overallRating(metrics)
+	}
+
+	var total, totalWeight float64
+	for _, component := range ratingComponents {
+		weight, ok := weights[component.metric]
+		if !ok {
+			weight = 1
+		}
+		total += component.value(metrics) * component.scale * weight
+		totalWeight += weight
+	}
+	if totalWeight == 0 {
+		return 0
+	}
+	return total / totalWeight
+}
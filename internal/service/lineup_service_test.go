@@ -0,0 +1,129 @@
+package service
+
+import (
+	"fmt"
+	"football-analytics/internal/domain"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+)
+
+// newLineupFixture t1 playing a league match a week in September and November 2024
+func newLineupFixture() LineupService {
+	players := &memoryPlayerRepository{players: make(map[string]*domain.Player)}
+	for _, player := range []*domain.Player{
+		{ID: "gk", Name: "Keeper", TeamID: "t1", Position: "Goalkeeper"},
+		{ID: "fa", Name: "September", TeamID: "t1", Position: "Forward"},
+		{ID: "fb", Name: "November", TeamID: "t1", Position: "Forward"},
+	} {
+		players.players[player.ID] = player
+		players.list = append(players.list, player)
+	}
+
+	matches := &memoryMatchRepository{}
+	stats := &memoryStatsRepository{matches: make(map[string]*domain.Match), byPlayer: make(map[string][]*domain.PlayerMatchStats)}
+
+	var dates []time.Time
+	for i := 0; i < 4; i++ {
+		dates = append(dates, utcDate(2024, 9, 1).AddDate(0, 0, 7*i))
+	}
+	for i := 0; i < 4; i++ {
+		dates = append(dates, utcDate(2024, 11, 2).AddDate(0, 0, 7*i))
+	}
+
+	for i, date := range dates {
+		match := &domain.Match{ID: fmt.Sprintf("m%d", i+1), HomeTeamID: "t1", AwayTeamID: "t2", Competition: "League", Date: date, Round: i + 1, Status: "completed"}
+		matches.matches = append(matches.matches, match)
+		stats.matches[match.ID] = match
+
+		september := date.Month() == time.September
+		for _, playerID := range []string{"gk", "fa", "fb"} {
+			stat := &domain.PlayerMatchStats{ID: playerID + match.ID, PlayerID: playerID, MatchID: match.ID, MinutesPlayed: 90, PassAccuracy: 80, Shots: 2, ShotsOnTarget: 1, DistanceCovered: 10}
+			if (playerID == "fa" && september) || (playerID == "fb" && !september) {
+				stat.Goals = 2
+				stat.ShotsOnTarget = 2
+			}
+			if playerID == "fb" && i == len(dates)-1 {
+				stat.RedCards = 1
+			}
+			stats.byPlayer[playerID] = append(stats.byPlayer[playerID], stat)
+		}
+	}
+	matches.matches = append(matches.matches,
+		&domain.Match{ID: "next-oct", HomeTeamID: "t1", AwayTeamID: "t3", Competition: "Cup", Date: utcDate(2024, 10, 5), Status: "scheduled"},
+		&domain.Match{ID: "next-dec", HomeTeamID: "t1", AwayTeamID: "t3", Competition: "League", Date: utcDate(2024, 12, 7), Status: "scheduled"},
+	)
+
+	disciplineRepo := &memoryDisciplineRepository{rules: make(map[string]*domain.DisciplineRule)}
+	discipline := NewDisciplineService(disciplineRepo, stats, players, matches, nil)
+	return NewLineupService(discipline, nil, stats, players, matches, nil)
+}
+
+func lineupRatings(lineup *domain.Lineup) map[string]float64 {
+	ratings := make(map[string]float64)
+	for _, slot := range lineup.Slots {
+		if slot.Player != nil {
+			ratings[slot.Player.PlayerID] = slot.Player.Rating
+		}
+	}
+	return ratings
+}
+
+func TestBestLineupRatesAsOfRequestDate(t *testing.T) {
+	service := newLineupFixture()
+
+	october, err := service.BestLineup(domain.LineupRequest{TeamID: "t1", Formation: "4-3-3", Date: utcDate(2024, 10, 1), RequiredSlots: []string{"GK"}})
+	assert.NoError(t, err)
+	ratings := lineupRatings(october)
+	assert.Len(t, ratings, 3)
+	// only the September matches count on 2024-10-01
+	assert.Greater(t, ratings["fa"], ratings["fb"])
+
+	december, err := service.BestLineup(domain.LineupRequest{TeamID: "t1", Formation: "4-3-3", Date: utcDate(2024, 12, 1), RequiredSlots: []string{"GK"}})
+	assert.NoError(t, err)
+	ratings = lineupRatings(december)
+	assert.Less(t, ratings["fa"], lineupRatings(october)["fa"])
+	assert.NotContains(t, ratings, "fb")
+	assert.Len(t, december.Excluded, 1)
+	assert.Equal(t, "fb", december.Excluded[0].PlayerID)
+	assert.Equal(t, "suspended", december.Excluded[0].Reason)
+}
+
+func TestBestLineupRequiredSlot(t *testing.T) {
+	service := newLineupFixture()
+
+	_, err := service.BestLineup(domain.LineupRequest{TeamID: "t1", Formation: "4-3-3", Date: utcDate(2024, 10, 1), Unavailable: []string{"gk"}, RequiredSlots: []string{"GK"}})
+	assert.EqualError(t, err, "no available player for required slot GK")
+}
+
+func TestBestLineupFillsRequiredSlotBeforeBetterOptionalOne(t *testing.T) {
+	service := newLineupFixture()
+
+	// fa is the only player left and rates higher up front than in the required midfield slot
+	lineup, err := service.BestLineup(domain.LineupRequest{TeamID: "t1", Formation: "4-3-3", Date: utcDate(2024, 10, 1), Unavailable: []string{"gk", "fb"}, RequiredSlots: []string{"CM"}})
+
+	if !assert.NoError(t, err) {
+		return
+	}
+	for _, slot := range lineup.Slots {
+		if slot.Slot == "CM" {
+			if assert.NotNil(t, slot.Player) {
+				assert.Equal(t, "fa", slot.Player.PlayerID)
+			}
+		} else {
+			assert.Nil(t, slot.Player, slot.Slot)
+		}
+	}
+}
+
+func TestPositionRating(t *testing.T) {
+	// the scorer has the higher overall rating, the tackler rates higher at the back
+	scorer := &domain.PerformanceMetrics{GoalsPerMinute: 0.02, PassAccuracy: 80, DefensiveEfficiency: 1, Stamina: 10}
+	tackler := &domain.PerformanceMetrics{PassAccuracy: 80, DefensiveEfficiency: 9, Stamina: 10}
+
+	assert.Greater(t, overallRating(scorer), overallRating(tackler))
+	assert.Greater(t, positionRating(tackler, "Defender"), positionRating(scorer, "Defender"))
+	assert.Greater(t, positionRating(scorer, "Forward"), positionRating(tackler, "Forward"))
+	assert.Equal(t, overallRating(scorer), positionRating(scorer, "Unknown"))
+}
@@ -0,0 +1,72 @@
+package service
+
+import (
+	"fmt"
+	"football-analytics/internal/domain"
+	"time"
+
+	"github.com/google/uuid"
+)
+
+// MatchModelService is interface for fitting and storing match outcome models
+type MatchModelService interface {
+	FitModel(asOf time.Time, opts domain.MatchModelOptions) (*domain.MatchModel, error)
+	GetModel(version string) (*domain.MatchModel, error)
+	GetLatestModel() (*domain.MatchModel, error)
+}
+
+type matchModelService struct {
+	matchRepo domain.MatchRepository
+	modelRepo domain.MatchModelRepository
+}
+
+// NewMatchModelService create instance of MatchModelService
+func NewMatchModelService(matchRepo domain.MatchRepository, modelRepo domain.MatchModelRepository) MatchModelService {
+	return &matchModelService{
+		matchRepo: matchRepo,
+		modelRepo: modelRepo,
+	}
+}
+
+// FitModel fit a Dixon-Coles model on matches completed before asOf and store it as a new version
+func (s *matchModelService) FitModel(asOf time.Time, opts domain.MatchModelOptions) (*domain.MatchModel, error) {
+	decayRate := opts.DecayRate
+	if decayRate == 0 {
+		decayRate = defaultDecayRate
+	}
+	if decayRate < 0 {
+		return nil, fmt.Errorf("invalid decay rate %v", opts.DecayRate)
+	}
+
+	matches, err := s.matchRepo.List()
+	if err != nil {
+		return nil, err
+	}
+
+	model := fitDixonColes(matches, asOf, decayRate)
+	if model.Matches == 0 {
+		return nil, fmt.Errorf("no completed matches before %s", asOf.Format("2006-01-02"))
+	}
+
+	now := time.Now()
+	model.ID = uuid.New().String()
+	// the ID suffix keeps versions of models fitted in the same second unique
+	model.Version = fmt.Sprintf("dixon-coles-%s-%s", now.UTC().Format("20060102T150405"), model.ID[:8])
+	model.CreatedAt = now
+
+	if err := s.modelRepo.Save(model); err != nil {
+		return nil, err
+	}
+
+	return model, nil
+}
+
+// GetModel get match model by version
+func (s *matchModelService) GetModel(version string) (*domain.MatchModel, error) {
+	return s.modelRepo.GetByVersion(version)
+}
+
+// GetLatestModel get the most recently fitted match model
+func (s *matchModelService) GetLatestModel() (*domain.MatchModel, error) {
+	return s.modelRepo.GetLatest()
+}
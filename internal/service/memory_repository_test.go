@@ -0,0 +1,115 @@
+package service
+
+import (
+	"football-analytics/internal/domain"
+	"sort"
+	"time"
+)
+
+// memoryPlayerRepository keeps players by ID and in listing order
+type memoryPlayerRepository struct {
+	domain.PlayerRepository
+	players map[string]*domain.Player
+	list    []*domain.Player
+}
+
+func (r *memoryPlayerRepository) GetByID(id string) (*domain.Player, error) {
+	player, ok := r.players[id]
+	if !ok {
+		return nil, domain.ErrNotFound
+	}
+	return player, nil
+}
+
+func (r *memoryPlayerRepository) List() ([]*domain.Player, error) {
+	return r.list, nil
+}
+
+// memoryMatchRepository keeps matches in insertion order
+type memoryMatchRepository struct {
+	domain.MatchRepository
+	matches []*domain.Match
+}
+
+func (r *memoryMatchRepository) List() ([]*domain.Match, error) {
+	return r.matches, nil
+}
+
+func (r *memoryMatchRepository) ListByDateRange(start, end time.Time) ([]*domain.Match, error) {
+	var result []*domain.Match
+	for _, match := range r.matches {
+		if !match.Date.Before(start) && !match.Date.After(end) {
+			result = append(result, match)
+		}
+	}
+	return result, nil
+}
+
+func (r *memoryMatchRepository) GetByID(id string) (*domain.Match, error) {
+	for _, match := range r.matches {
+		if match.ID == id {
+			return match, nil
+		}
+	}
+	return nil, domain.ErrNotFound
+}
+
+func (r *memoryMatchRepository) ListByTeamID(teamID string) ([]*domain.Match, error) {
+	var result []*domain.Match
+	for _, match := range r.matches {
+		if match.HomeTeamID == teamID || match.AwayTeamID == teamID {
+			result = append(result, match)
+		}
+	}
+	return result, nil
+}
+
+// memoryStatsRepository keeps the stats of every player ordered by match date, like the player and date index
+type memoryStatsRepository struct {
+	domain.PlayerMatchStatsRepository
+	matches  map[string]*domain.Match
+	byPlayer map[string][]*domain.PlayerMatchStats
+}
+
+func (r *memoryStatsRepository) ListByPlayerID(playerID string) ([]*domain.PlayerMatchStats, error) {
+	return r.byPlayer[playerID], nil
+}
+
+func (r *memoryStatsRepository) ListByMatchIDs(matchIDs []string) ([]*domain.PlayerMatchStats, error) {
+	var result []*domain.PlayerMatchStats
+	for _, matchID := range matchIDs {
+		var rows []*domain.PlayerMatchStats
+		for _, playerRows := range r.byPlayer {
+			for _, stats := range playerRows {
+				if stats.MatchID == matchID {
+					rows = append(rows, stats)
+				}
+			}
+		}
+		sort.Slice(rows, func(i, j int) bool {
+			return rows[i].PlayerID < rows[j].PlayerID
+		})
+		result = append(result, rows...)
+	}
+	return result, nil
+}
+
+func (r *memoryStatsRepository) ListByPlayerAndDateRange(playerID string, start, end time.Time) ([]*domain.PlayerAppearance, error) {
+	var result []*domain.PlayerAppearance
+	for _, stats := range r.byPlayer[playerID] {
+		match := r.matches[stats.MatchID]
+		if !match.Date.Before(start) && !match.Date.After(end) {
+			result = append(result, &domain.PlayerAppearance{Match: match, Stats: stats})
+		}
+	}
+	return result, nil
+}
+
+func (r *memoryStatsRepository) ListByPlayersAndDateRange(playerIDs []string, start, end time.Time) ([]*domain.PlayerAppearance, error) {
+	var result []*domain.PlayerAppearance
+	for _, playerID := range playerIDs {
+		rows, _ := r.ListByPlayerAndDateRange(playerID, start, end)
+		result = append(result, rows...)
+	}
+	return result, nil
+}
@@ -0,0 +1,153 @@
+package service
+
+import (
+	"football-analytics/internal/domain"
+)
+
+type observedStatsRepository struct {
+	domain.PlayerMatchStatsRepository
+	observers []domain.StatsObserver
+}
+
+// NewObservedStatsRepository wrap a PlayerMatchStatsRepository so the observers are notified of every successful write
+func NewObservedStatsRepository(repo domain.PlayerMatchStatsRepository, observers ...domain.StatsObserver) domain.PlayerMatchStatsRepository {
+	return &observedStatsRepository{
+		PlayerMatchStatsRepository: repo,
+		observers:                  observers,
+	}
+}
+
+func (r *observedStatsRepository) Create(stats *domain.PlayerMatchStats) error {
+	if err := r.PlayerMatchStatsRepository.Create(stats); err != nil {
+		return err
+	}
+	r.notify(nil, stats)
+	return nil
+}
+
+func (r *observedStatsRepository) Update(stats *domain.PlayerMatchStats) error {
+	old, err := r.PlayerMatchStatsRepository.GetByID(stats.ID)
+	if err != nil {
+		return err
+	}
+	if err := r.PlayerMatchStatsRepository.Update(stats); err != nil {
+		return err
+	}
+	r.notify(old, stats)
+	return nil
+}
+
+func (r *observedStatsRepository) Delete(id string) error {
+	old, err := r.PlayerMatchStatsRepository.GetByID(id)
+	if err != nil {
+		return err
+	}
+	if err := r.PlayerMatchStatsRepository.Delete(id); err != nil {
+		return err
+	}
+	r.notify(old, nil)
+	return nil
+}
+
+func (r *observedStatsRepository) notify(old, new *domain.PlayerMatchStats) {
+	for _, observer := range r.observers {
+		observer.StatsChanged(old, new)
+	}
+}
+
+type observedMatchRepository struct {
+	domain.MatchRepository
+	observers []domain.MatchObserver
+}
+
+// NewObservedMatchRepository wrap a MatchRepository so the observers are notified of every successful write
+func NewObservedMatchRepository(repo domain.MatchRepository, observers ...domain.MatchObserver) domain.MatchRepository {
+	return &observedMatchRepository{
+		MatchRepository: repo,
+		observers:       observers,
+	}
+}
+
+func (r *observedMatchRepository) Create(match *domain.Match) error {
+	if err := r.MatchRepository.Create(match); err != nil {
+		return err
+	}
+	r.notify(nil, match)
+	return nil
+}
+
+func (r *observedMatchRepository) Update(match *domain.Match) error {
+	old, err := r.MatchRepository.GetByID(match.ID)
+	if err != nil {
+		return err
+	}
+	if err := r.MatchRepository.Update(match); err != nil {
+		return err
+	}
+	r.notify(old, match)
+	return nil
+}
+
+func (r *observedMatchRepository) Delete(id string) error {
+	old, err := r.MatchRepository.GetByID(id)
+	if err != nil {
+		return err
+	}
+	if err := r.MatchRepository.Delete(id); err != nil {
+		return err
+	}
+	r.notify(old, nil)
+	return nil
+}
+
+func (r *observedMatchRepository) notify(old, new *domain.Match) {
+	for _, observer := range r.observers {
+		observer.MatchChanged(old, new)
+	}
+}
+
+type seasonLinkedMatchRepository struct {
+	domain.MatchRepository
+	seasonRepo domain.SeasonRepository
+}
+
+// NewSeasonLinkedMatchRepository wrap a MatchRepository so created and updated matches are linked to the
+// season of their competition containing their date, matches outside every season are left unlinked
+func NewSeasonLinkedMatchRepository(repo domain.MatchRepository, seasonRepo domain.SeasonRepository) domain.MatchRepository {
+	return &seasonLinkedMatchRepository{
+		MatchRepository: repo,
+		seasonRepo:      seasonRepo,
+	}
+}
+
+func (r *seasonLinkedMatchRepository) Create(match *domain.Match) error {
+	if err := r.linkSeason(match); err != nil {
+		return err
+	}
+	return r.MatchRepository.Create(match)
+}
+
+func (r *seasonLinkedMatchRepository) Update(match *domain.Match) error {
+	if err := r.linkSeason(match); err != nil {
+		return err
+	}
+	return r.MatchRepository.Update(match)
+}
+
+// linkSeason set the season of the match from its competition and date, like the seasons repository
+// links the matches of a season when it is saved
+func (r *seasonLinkedMatchRepository) linkSeason(match *domain.Match) error {
+	seasons, err := r.seasonRepo.ListByCompetition(match.Competition)
+	if err != nil {
+		return err
+	}
+
+	match.SeasonID = ""
+	for _, season := range seasons {
+		if season.Contains(match.Date) {
+			match.SeasonID = season.ID
+			break
+		}
+	}
+	return nil
+}
@@ -0,0 +1,47 @@
+package service
+
+import (
+	"football-analytics/internal/domain"
+	"time"
+)
+
+// PredictMatchOutcome predict the score of a match between two teams with the latest match model
+func (s *analyticsService) PredictMatchOutcome(homeTeamID, awayTeamID string) (*domain.MatchPrediction, error) {
+	model, err := s.modelRepo.GetLatest()
+	if err != nil {
+		return nil, err
+	}
+
+	return predictWithModel(model, homeTeamID, awayTeamID), nil
+}
+
+// PredictScheduledMatches predict every scheduled match from the start to the end date, both included,
+// with the latest match model
+func (s *analyticsService) PredictScheduledMatches(startDateStr, endDateStr string) ([]*domain.MatchPrediction, error) {
+	tr, err := domain.DateRange(startDateStr, endDateStr)
+	if err != nil {
+		return nil, err
+	}
+
+	model, err := s.modelRepo.GetLatest()
+	if err != nil {
+		return nil, err
+	}
+
+	matches, err := s.matchRepo.ListByDateRange(tr.Start, tr.End.Add(-time.Nanosecond))
+	if err != nil {
+		return nil, err
+	}
+
+	var predictions []*domain.MatchPrediction
+	for _, match := range matches {
+		if match.Status != "scheduled" {
+			continue
+		}
+		prediction := predictWithModel(model, match.HomeTeamID, match.AwayTeamID)
+		prediction.MatchID = match.ID
+		predictions = append(predictions, prediction)
+	}
+
+	return predictions, nil
+}
@@ -0,0 +1,153 @@
+package service
+
+import (
+	"football-analytics/internal/domain"
+	"math"
+	"time"
+)
+
+// profileMetrics names of the per 90 minutes metrics in a player profile, in vector order
+var profileMetrics = []string{
+	"goals",
+	"assists",
+	"shots",
+	"shots_on_target",
+	"passes",
+	"tackles",
+	"interceptions",
+	"fouls",
+	"distance_covered",
+}
+
+// playerProfile per 90 minutes output of a player over a set of appearances
+type playerProfile struct {
+	playerID string
+	matches  int
+	minutes  int
+	per90    []float64
+}
+
+// buildProfile create the per 90 profile of a player, in profileMetrics order
+func buildProfile(playerID string, stats []*domain.PlayerMatchStats) *playerProfile {
+	profile := &playerProfile{
+		playerID: playerID,
+		matches:  len(stats),
+		per90:    make([]float64, len(profileMetrics)),
+	}
+
+	totals := make([]float64, len(profileMetrics))
+	for _, stat := range stats {
+		profile.minutes += stat.MinutesPlayed
+		totals[0] += float64(stat.Goals)
+		totals[1] += float64(stat.Assists)
+		totals[2] += float64(stat.Shots)
+		totals[3] += float64(stat.ShotsOnTarget)
+		totals[4] += float64(stat.Passes)
+		totals[5] += float64(stat.Tackles)
+		totals[6] += float64(stat.Interceptions)
+		totals[7] += float64(stat.Fouls)
+		totals[8] += stat.DistanceCovered
+	}
+
+	if profile.minutes > 0 {
+		for i, total := range totals {
+			profile.per90[i] = total / float64(profile.minutes) * 90
+		}
+	}
+
+	return profile
+}
+
+// standardize z-scores of every profile metric across profiles, a metric without
+// variance is 0 for every profile
+func standardize(profiles []*playerProfile) [][]float64 {
+	n := float64(len(profiles))
+	result := make([][]float64, len(profiles))
+	for i := range result {
+		result[i] = make([]float64, len(profileMetrics))
+	}
+	if n == 0 {
+		return result
+	}
+
+	for k := range profileMetrics {
+		var mean float64
+		for _, profile := range profiles {
+			mean += profile.per90[k]
+		}
+		mean /= n
+
+		var variance float64
+		for _, profile := range profiles {
+			variance += (profile.per90[k] - mean) * (profile.per90[k] - mean)
+		}
+		std := math.Sqrt(variance / n)
+		if std == 0 {
+			continue
+		}
+
+		for i, profile := range profiles {
+			result[i][k] = (profile.per90[k] - mean) / std
+		}
+	}
+
+	return result
+}
+
+// statsByPlayerInRange get stats of every player in matches between start and end
+func (s *analyticsService) statsByPlayerInRange(start, end time.Time) (map[string][]*domain.PlayerMatchStats, error) {
+	appearancesByPlayer, err := s.appearancesByPlayerInRange(start, end)
+	if err != nil {
+		return nil, err
+	}
+
+	result := make(map[string][]*domain.PlayerMatchStats)
+	for playerID, appearances := range appearancesByPlayer {
+		result[playerID] = appearanceStats(appearances)
+	}
+
+	return result, nil
+}
+
+// appearancesByPlayerInRange get appearances of every player in matches between start and end, ordered by match date
+func (s *analyticsService) appearancesByPlayerInRange(start, end time.Time) (map[string][]appearance, error) {
+	players, err := s.playerRepo.List()
+	if err != nil {
+		return nil, err
+	}
+
+	playerIDs := make([]string, 0, len(players))
+	for _, player := range players {
+		playerIDs = append(playerIDs, player.ID)
+	}
+
+	rows, err := s.playerStatsRepo.ListByPlayersAndDateRange(playerIDs, start, end)
+	if err != nil {
+		return nil, err
+	}
+
+	result := make(map[string][]appearance)
+	for _, app := range toAppearances(rows) {
+		result[app.stats.PlayerID] = append(result[app.stats.PlayerID], app)
+	}
+
+	return result, nil
+}
+
+// appearanceStats stats of the appearances
+func appearanceStats(appearances []appearance) []*domain.PlayerMatchStats {
+	stats := make([]*domain.PlayerMatchStats, 0, len(appearances))
+	for _, app := range appearances {
+		stats = append(stats, app.stats)
+	}
+	return stats
+}
+
+// ageAt age in full years of a player born on birthday at date t
+func ageAt(birthday, t time.Time) int {
+	age := t.Year() - birthday.Year()
+	if t.Month() < birthday.Month() || (t.Month() == birthday.Month() && t.Day() < birthday.Day()) {
+		age--
+	}
+	return age
+}
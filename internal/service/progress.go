@@ -0,0 +1,315 @@
+package service
+
+import (
+	"fmt"
+	"football-analytics/internal/domain"
+	"sort"
+	"time"
+)
+
+const (
+	defaultProgressSize  = 5
+	defaultProgressAlpha = 0.3
+)
+
+// appearance is a player stats row together with the match it was recorded in
+type appearance struct {
+	match *domain.Match
+	stats *domain.PlayerMatchStats
+}
+
+// GetPlayerProgressSeries get player progress over time, grouped by the window in opts, both dates are included
+func (s *analyticsService) GetPlayerProgressSeries(playerID string, startDateStr, endDateStr string, opts domain.ProgressOptions) ([]*domain.ProgressPoint, error) {
+	tr, err := domain.DateRange(startDateStr, endDateStr)
+	if err != nil {
+		return nil, err
+	}
+
+	appearances, _, err := s.playerRange(playerID, tr, s.clock.Now())
+	if err != nil {
+		return nil, err
+	}
+
+	return buildProgressSeries(playerID, appearances, opts, s.calendar)
+}
+
+// loadPlayerAppearances get stats of the player in matches between start and end, ordered by match date
+func loadPlayerAppearances(playerStatsRepo domain.PlayerMatchStatsRepository, playerID string, start, end time.Time) ([]appearance, error) {
+	rows, err := playerStatsRepo.ListByPlayerAndDateRange(playerID, start, end)
+	if err != nil {
+		return nil, err
+	}
+
+	return toAppearances(rows), nil
+}
+
+// loadStatsByMatch get the stats of every match in one query, by match ID
+func loadStatsByMatch(playerStatsRepo domain.PlayerMatchStatsRepository, matches []*domain.Match) (map[string][]*domain.PlayerMatchStats, error) {
+	matchIDs := make([]string, 0, len(matches))
+	for _, match := range matches {
+		matchIDs = append(matchIDs, match.ID)
+	}
+
+	stats, err := playerStatsRepo.ListByMatchIDs(matchIDs)
+	if err != nil {
+		return nil, err
+	}
+
+	statsByMatch := make(map[string][]*domain.PlayerMatchStats)
+	for _, stat := range stats {
+		statsByMatch[stat.MatchID] = append(statsByMatch[stat.MatchID], stat)
+	}
+	return statsByMatch, nil
+}
+
+// toAppearances convert joined repository rows, keeping their order
+func toAppearances(rows []*domain.PlayerAppearance) []appearance {
+	appearances := make([]appearance, 0, len(rows))
+	for _, row := range rows {
+		appearances = append(appearances, appearance{match: row.Match, stats: row.Stats})
+	}
+	return appearances
+}
+
+// joinAppearances pair stats with their match, stats of other matches are dropped, ordered by match date
+func joinAppearances(matches []*domain.Match, stats []*domain.PlayerMatchStats) []appearance {
+	matchesByID := make(map[string]*domain.Match)
+	for _, match := range matches {
+		matchesByID[match.ID] = match
+	}
+
+	var appearances []appearance
+	for _, stat := range stats {
+		if match, ok := matchesByID[stat.MatchID]; ok {
+			appearances = append(appearances, appearance{match: match, stats: stat})
+		}
+	}
+
+	sort.SliceStable(appearances, func(i, j int) bool {
+		return appearances[i].match.Date.Before(appearances[j].match.Date)
+	})
+
+	return appearances
+}
+
+// buildProgressSeries group appearances (ordered by date) into progress points, season windows
+// follow the calendar of each match's competition
+func buildProgressSeries(playerID string, appearances []appearance, opts domain.ProgressOptions, calendar *seasonCalendar) ([]*domain.ProgressPoint, error) {
+	size, alpha, err := progressDefaults(opts)
+	if err != nil {
+		return nil, err
+	}
+
+	switch opts.Window {
+	case domain.ProgressRolling:
+		return rollingProgress(playerID, appearances, size), nil
+	case domain.ProgressBlock:
+		return blockProgress(playerID, appearances, size), nil
+	case domain.ProgressWeek, domain.ProgressMonth, domain.ProgressSeason:
+		return calendarProgress(playerID, appearances, progressBucketOf(opts.Window, calendar))
+	case domain.ProgressEWMA:
+		return ewmaProgress(playerID, appearances, alpha), nil
+	default:
+		return nil, fmt.Errorf("unknown progress window %q", opts.Window)
+	}
+}
+
+// progressDefaults window size and smoothing factor of the options, zero values are the defaults
+func progressDefaults(opts domain.ProgressOptions) (int, float64, error) {
+	size := opts.Size
+	if size == 0 {
+		size = defaultProgressSize
+	}
+	if size < 0 {
+		return 0, 0, fmt.Errorf("invalid progress window size %d", opts.Size)
+	}
+
+	alpha := opts.Alpha
+	if alpha == 0 {
+		alpha = defaultProgressAlpha
+	}
+	if alpha < 0 || alpha > 1 {
+		return 0, 0, fmt.Errorf("invalid progress smoothing factor %v", opts.Alpha)
+	}
+
+	return size, alpha, nil
+}
+
+// progressBucketOf bucket of a match for the week, month or season windows
+func progressBucketOf(window domain.ProgressWindow, calendar *seasonCalendar) func(match *domain.Match) (progressBucket, error) {
+	if window == domain.ProgressSeason {
+		return func(match *domain.Match) (progressBucket, error) {
+			season, err := calendar.seasonOf(match)
+			if err != nil {
+				return progressBucket{}, err
+			}
+			return progressBucket{key: season.Label, start: season.StartDate, end: season.EndDate}, nil
+		}
+	}
+
+	return func(match *domain.Match) (progressBucket, error) {
+		start := bucketStartFor(match.Date, window)
+		return progressBucket{key: start.String(), start: start, end: nextBucketStart(start, window)}, nil
+	}
+}
+
+// rollingProgress one point per appearance over the last size appearances,
+// starting from the first full window
+func rollingProgress(playerID string, appearances []appearance, size int) []*domain.ProgressPoint {
+	var points []*domain.ProgressPoint
+	if len(appearances) == 0 {
+		return points
+	}
+
+	if len(appearances) < size {
+		return append(points, appearanceWindow(playerID, appearances))
+	}
+
+	for i := size; i <= len(appearances); i++ {
+		points = append(points, appearanceWindow(playerID, appearances[i-size:i]))
+	}
+
+	return points
+}
+
+// blockProgress one point per consecutive group of size appearances, the last group may be smaller
+func blockProgress(playerID string, appearances []appearance, size int) []*domain.ProgressPoint {
+	var points []*domain.ProgressPoint
+	for i := 0; i < len(appearances); i += size {
+		end := i + size
+		if end > len(appearances) {
+			end = len(appearances)
+		}
+		points = append(points, appearanceWindow(playerID, appearances[i:end]))
+	}
+
+	return points
+}
+
+// progressBucket is a calendar bucket of a progress series, appearances with the same key share a point
+type progressBucket struct {
+	key   string
+	start time.Time
+	end   time.Time
+}
+
+// calendarProgress one point per run of consecutive appearances in the same bucket, the window
+// spans the buckets of the run, seasons of competitions sharing a label are one bucket
+func calendarProgress(playerID string, appearances []appearance, bucketOf func(match *domain.Match) (progressBucket, error)) ([]*domain.ProgressPoint, error) {
+	var points []*domain.ProgressPoint
+	var current []*domain.PlayerMatchStats
+	var bucket progressBucket
+
+	flush := func() {
+		if len(current) == 0 {
+			return
+		}
+		points = append(points, &domain.ProgressPoint{
+			WindowStart: bucket.start,
+			WindowEnd:   bucket.end,
+			Appearances: len(current),
+			Metrics:     calculateMetricsFromStats(playerID, current),
+		})
+		current = nil
+	}
+
+	for _, app := range appearances {
+		next, err := bucketOf(app.match)
+		if err != nil {
+			return nil, err
+		}
+
+		if next.key != bucket.key {
+			flush()
+			bucket = next
+		} else {
+			if next.start.Before(bucket.start) {
+				bucket.start = next.start
+			}
+			if next.end.After(bucket.end) {
+				bucket.end = next.end
+			}
+		}
+		current = append(current, app.stats)
+	}
+	flush()
+
+	return points, nil
+}
+
+// ewmaProgress one point per appearance, each metric smoothed with the previous point
+func ewmaProgress(playerID string, appearances []appearance, alpha float64) []*domain.ProgressPoint {
+	var points []*domain.ProgressPoint
+	var smoothed *domain.PerformanceMetrics
+
+	for i, app := range appearances {
+		current := calculateMetricsFromStats(playerID, []*domain.PlayerMatchStats{app.stats})
+		if smoothed == nil {
+			smoothed = current
+		} else {
+			smoothed = blendMetrics(smoothed, current, alpha)
+		}
+
+		points = append(points, &domain.ProgressPoint{
+			WindowStart: appearances[0].match.Date,
+			WindowEnd:   app.match.Date,
+			Appearances: i + 1,
+			Metrics:     smoothed,
+		})
+	}
+
+	return points
+}
+
+// appearanceWindow metrics of a group of appearances, window bounds are the first and last match date
+func appearanceWindow(playerID string, window []appearance) *domain.ProgressPoint {
+	return &domain.ProgressPoint{
+		WindowStart: window[0].match.Date,
+		WindowEnd:   window[len(window)-1].match.Date,
+		Appearances: len(window),
+		Metrics:     calculateMetricsFromStats(playerID, appearanceStats(window)),
+	}
+}
+
+// blendMetrics exponentially weighted average of prev and current, alpha is the weight of current
+func blendMetrics(prev, current *domain.PerformanceMetrics, alpha float64) *domain.PerformanceMetrics {
+	blend := func(p, c float64) float64 {
+		return alpha*c + (1-alpha)*p
+	}
+
+	return &domain.PerformanceMetrics{
+		PlayerID:            current.PlayerID,
+		GoalsPerMinute:      blend(prev.GoalsPerMinute, current.GoalsPerMinute),
+		AssistsPerMinute:    blend(prev.AssistsPerMinute, current.AssistsPerMinute),
+		PassAccuracy:        blend(prev.PassAccuracy, current.PassAccuracy),
+		ShotAccuracy:        blend(prev.ShotAccuracy, current.ShotAccuracy),
+		DefensiveEfficiency: blend(prev.DefensiveEfficiency, current.DefensiveEfficiency),
+		Stamina:             blend(prev.Stamina, current.Stamina),
+		OverallRating:       blend(prev.OverallRating, current.OverallRating),
+	}
+}
+
+// bucketStartFor start of the week or month bucket containing t
+func bucketStartFor(t time.Time, window domain.ProgressWindow) time.Time {
+	t = t.UTC()
+	day := time.Date(t.Year(), t.Month(), t.Day(), 0, 0, 0, 0, time.UTC)
+
+	switch window {
+	case domain.ProgressWeek:
+		// weeks start on Monday
+		offset := (int(day.Weekday()) + 6) % 7
+		return day.AddDate(0, 0, -offset)
+	default: // domain.ProgressMonth
+		return time.Date(t.Year(), t.Month(), 1, 0, 0, 0, 0, time.UTC)
+	}
+}
+
+// nextBucketStart start of the week or month bucket following the one starting at start
+func nextBucketStart(start time.Time, window domain.ProgressWindow) time.Time {
+	switch window {
+	case domain.ProgressWeek:
+		return start.AddDate(0, 0, 7)
+	default: // domain.ProgressMonth
+		return start.AddDate(0, 1, 0)
+	}
+}
@@ -0,0 +1,136 @@
+package service
+
+import (
+	"context"
+	"football-analytics/internal/domain"
+	"sort"
+)
+
+// ratingDriverCount drivers listed on each side of a rating breakdown
+const ratingDriverCount = 3
+
+// ratingComponents metrics of the overall rating with the factor bringing each one to the common scale
+var ratingComponents = []struct {
+	metric string
+	scale  float64
+	value  func(metrics *domain.PerformanceMetrics) float64
+}{
+	{"goals_per_minute", 100, func(m *domain.PerformanceMetrics) float64 { return m.GoalsPerMinute }},
+	{"assists_per_minute", 50, func(m *domain.PerformanceMetrics) float64 { return m.AssistsPerMinute }},
+	{"pass_accuracy", 0.3, func(m *domain.PerformanceMetrics) float64 { return m.PassAccuracy }},
+	{"shot_accuracy", 0.2, func(m *domain.PerformanceMetrics) float64 { return m.ShotAccuracy }},
+	{"defensive_efficiency", 0.1, func(m *domain.PerformanceMetrics) float64 { return m.DefensiveEfficiency }},
+	{"stamina", 0.1, func(m *domain.PerformanceMetrics) float64 { return m.Stamina }},
+}
+
+// ExplainPlayerRating break the overall rating of a player down into its components and list the
+// components that move it most above and below the average of the player's position
+func (s *analyticsService) ExplainPlayerRating(playerID string, timeRange string) (*domain.RatingBreakdown, error) {
+	player, err := s.playerRepo.GetByID(playerID)
+	if err != nil {
+		return nil, err
+	}
+
+	metrics, err := s.CalculatePlayerPerformance(playerID, timeRange)
+	if err != nil {
+		return nil, err
+	}
+
+	players, err := s.playerRepo.List()
+	if err != nil {
+		return nil, err
+	}
+	var peerIDs []string
+	for _, peer := range players {
+		if peer.Position == player.Position {
+			peerIDs = append(peerIDs, peer.ID)
+		}
+	}
+
+	// failed peers are left out of the average
+	result, err := s.CalculatePlayersPerformance(context.Background(), peerIDs, domain.BatchOptions{TimeRange: timeRange})
+	if err != nil {
+		return nil, err
+	}
+
+	// players without appearances in the range do not pull the average down
+	var peers []*domain.PerformanceMetrics
+	for _, peerID := range peerIDs {
+		if peer, ok := result.Metrics[peerID]; ok && peer.OverallRating != 0 {
+			peers = append(peers, peer)
+		}
+	}
+
+	breakdown := ratingBreakdown(metrics, averageMetrics(peers))
+	breakdown.Position = player.Position
+	breakdown.TimeRange = timeRange
+
+	return breakdown, nil
+}
+
+// ratingBreakdown components of the rating of metrics and its drivers against the average metrics
+func ratingBreakdown(metrics, average *domain.PerformanceMetrics) *domain.RatingBreakdown {
+	breakdown := &domain.RatingBreakdown{
+		PlayerID:        metrics.PlayerID,
+		OverallRating:   metrics.OverallRating,
+		PositionAverage: overallRating(average),
+	}
+
+	weight := 1 / float64(len(ratingComponents))
+	var drivers []*domain.RatingDriver
+	for _, component := range ratingComponents {
+		raw := component.value(metrics)
+		normalized := raw * component.scale
+		breakdown.Components = append(breakdown.Components, &domain.RatingComponent{
+			Metric:       component.metric,
+			Raw:          raw,
+			Normalized:   normalized,
+			Weight:       weight,
+			Contribution: normalized * weight,
+		})
+
+		averageContribution := component.value(average) * component.scale * weight
+		drivers = append(drivers, &domain.RatingDriver{
+			Metric:          component.metric,
+			Contribution:    normalized * weight,
+			PositionAverage: averageContribution,
+			Difference:      normalized*weight - averageContribution,
+		})
+	}
+
+	sort.SliceStable(drivers, func(i, j int) bool {
+		return drivers[i].Difference > drivers[j].Difference
+	})
+	for _, driver := range drivers {
+		if driver.Difference > 0 && len(breakdown.PositiveDrivers) < ratingDriverCount {
+			breakdown.PositiveDrivers = append(breakdown.PositiveDrivers, driver)
+		}
+	}
+	for i := len(drivers) - 1; i >= 0; i-- {
+		if drivers[i].Difference < 0 && len(breakdown.NegativeDrivers) < ratingDriverCount {
+			breakdown.NegativeDrivers = append(breakdown.NegativeDrivers, drivers[i])
+		}
+	}
+
+	return breakdown
+}
+
+// averageMetrics mean of every metric, zero metrics for no input
+func averageMetrics(metrics []*domain.PerformanceMetrics) *domain.PerformanceMetrics {
+	average := &domain.PerformanceMetrics{}
+	if len(metrics) == 0 {
+		return average
+	}
+
+	n := float64(len(metrics))
+	for _, m := range metrics {
+		average.GoalsPerMinute += m.GoalsPerMinute / n
+		average.AssistsPerMinute += m.AssistsPerMinute / n
+		average.PassAccuracy += m.PassAccuracy / n
+		average.ShotAccuracy += m.ShotAccuracy / n
+		average.DefensiveEfficiency += m.DefensiveEfficiency / n
+		average.Stamina += m.Stamina / n
+		average.OverallRating += m.OverallRating / n
+	}
+	return average
+}
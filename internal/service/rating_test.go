@@ -0,0 +1,48 @@
+package service
+
+import (
+	"football-analytics/internal/domain"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestRatingBreakdown(t *testing.T) {
+	metrics := &domain.PerformanceMetrics{
+		PlayerID:            "p1",
+		GoalsPerMinute:      0.01,
+		AssistsPerMinute:    0.002,
+		PassAccuracy:        70,
+		ShotAccuracy:        0.5,
+		DefensiveEfficiency: 1,
+		Stamina:             100,
+	}
+	metrics.OverallRating = overallRating(metrics)
+	average := &domain.PerformanceMetrics{
+		GoalsPerMinute:      0.005,
+		AssistsPerMinute:    0.002,
+		PassAccuracy:        85,
+		ShotAccuracy:        0.4,
+		DefensiveEfficiency: 3,
+		Stamina:             100,
+	}
+
+	breakdown := ratingBreakdown(metrics, average)
+
+	var total float64
+	for _, component := range breakdown.Components {
+		total += component.Contribution
+	}
+	assert.InDelta(t, breakdown.OverallRating, total, 1e-9)
+	assert.Len(t, breakdown.Components, len(ratingComponents))
+
+	if assert.Len(t, breakdown.PositiveDrivers, 2) {
+		assert.Equal(t, "goals_per_minute", breakdown.PositiveDrivers[0].Metric)
+		assert.Equal(t, "shot_accuracy", breakdown.PositiveDrivers[1].Metric)
+	}
+	if assert.Len(t, breakdown.NegativeDrivers, 2) {
+		assert.Equal(t, "pass_accuracy", breakdown.NegativeDrivers[0].Metric)
+		assert.InDelta(t, -0.75, breakdown.NegativeDrivers[0].Difference, 1e-9)
+		assert.Equal(t, "defensive_efficiency", breakdown.NegativeDrivers[1].Metric)
+	}
+}
@@ -0,0 +1,419 @@
+package service
+
+import (
+	"errors"
+	"fmt"
+	"football-analytics/internal/domain"
+	"math"
+	"math/rand"
+	"sort"
+	"strings"
+	"time"
+
+	"github.com/google/uuid"
+)
+
+const (
+	defaultRoleCount      = 8
+	defaultRoleMinMinutes = 900
+	kmeansRestarts        = 10
+	kmeansIterations      = 100
+	// kmeansSeed fixed seed so the same data always gives the same roles
+	kmeansSeed = 37
+	// roleMatchDistance maximum distance between centroids of two runs to keep the role ID
+	roleMatchDistance = 1.5
+	// roleFeatureThreshold minimum centroid z-score for a metric to describe a role
+	roleFeatureThreshold = 0.5
+)
+
+// roleFeatureLabels describing word of a role that is high on a profile metric
+var roleFeatureLabels = map[string]string{
+	"goals":            "goalscoring",
+	"assists":          "creative",
+	"shots":            "shooting",
+	"shots_on_target":  "clinical",
+	"passes":           "ball-playing",
+	"tackles":          "tackling",
+	"interceptions":    "ball-winning",
+	"fouls":            "aggressive",
+	"distance_covered": "box-to-box",
+}
+
+// ClusterPlayerRoles cluster per 90 profiles of the season into roles and store the result,
+// roles close to the ones of the previous run for the season keep their ID
+func (s *analyticsService) ClusterPlayerRoles(opts domain.RoleOptions) (*domain.RoleModel, error) {
+	start, end, _, err := s.calendar.labelRange(opts.Season, "")
+	if err != nil {
+		return nil, err
+	}
+
+	k := opts.K
+	if k == 0 {
+		k = defaultRoleCount
+	}
+	minMinutes := opts.MinMinutes
+	if minMinutes == 0 {
+		minMinutes = defaultRoleMinMinutes
+	}
+	if k < 0 || minMinutes < 0 {
+		return nil, fmt.Errorf("invalid role options k=%d min_minutes=%d", opts.K, opts.MinMinutes)
+	}
+
+	players, err := s.playerRepo.List()
+	if err != nil {
+		return nil, err
+	}
+
+	statsByPlayer, err := s.statsByPlayerInRange(start, end)
+	if err != nil {
+		return nil, err
+	}
+
+	var members []*domain.Player
+	var profiles []*playerProfile
+	for _, player := range players {
+		profile := buildProfile(player.ID, statsByPlayer[player.ID])
+		if profile.minutes == 0 || profile.minutes < minMinutes {
+			continue
+		}
+		members = append(members, player)
+		profiles = append(profiles, profile)
+	}
+	if len(profiles) == 0 {
+		return nil, domain.ErrNoAppearances
+	}
+
+	previous, err := s.roleRepo.GetLatestBySeason(opts.Season)
+	if err != nil && !errors.Is(err, domain.ErrNotFound) {
+		return nil, err
+	}
+
+	model := buildRoleModel(members, profiles, k, previous)
+	model.ID = uuid.New().String()
+	model.Season = opts.Season
+	model.CreatedAt = time.Now()
+
+	if err := s.roleRepo.Save(model); err != nil {
+		return nil, err
+	}
+
+	return model, nil
+}
+
+// GetPlayerRole get the role of a player in the latest role model of the season, domain.ErrNotFound
+// when the season has not been clustered by ClusterPlayerRoles
+func (s *analyticsService) GetPlayerRole(playerID string, season string) (*domain.PlayerRole, error) {
+	model, err := s.roleRepo.GetLatestBySeason(season)
+	if err != nil {
+		return nil, err
+	}
+
+	for _, role := range model.Assignments {
+		if role.PlayerID == playerID {
+			return role, nil
+		}
+	}
+
+	return nil, domain.ErrNotFound
+}
+
+// buildRoleModel run k-means on the standardized profiles and describe the clusters
+func buildRoleModel(players []*domain.Player, profiles []*playerProfile, k int, previous *domain.RoleModel) *domain.RoleModel {
+	if k > len(profiles) {
+		k = len(profiles)
+	}
+
+	points := standardize(profiles)
+	centroids, labels, inertia := kmeans(points, k, rand.New(rand.NewSource(kmeansSeed)))
+	ids := stableRoleIDs(centroids, previous)
+
+	model := &domain.RoleModel{K: k, Inertia: inertia}
+	clusters := make([]*domain.RoleCluster, k)
+	positions := make([]map[string]int, k)
+	for c := range clusters {
+		clusters[c] = &domain.RoleCluster{
+			ID:       ids[c],
+			Centroid: make(map[string]float64),
+		}
+		for m, metric := range profileMetrics {
+			clusters[c].Centroid[metric] = centroids[c][m]
+		}
+		positions[c] = make(map[string]int)
+	}
+
+	sums := make([][]float64, k)
+	for c := range sums {
+		sums[c] = make([]float64, len(profileMetrics))
+	}
+	for i, c := range labels {
+		clusters[c].Players++
+		positions[c][players[i].Position]++
+		for m := range profileMetrics {
+			sums[c][m] += profiles[i].per90[m]
+		}
+	}
+
+	used := make(map[string]bool)
+	for c, cluster := range clusters {
+		cluster.Per90 = make(map[string]float64)
+		for m, metric := range profileMetrics {
+			if cluster.Players > 0 {
+				cluster.Per90[metric] = sums[c][m] / float64(cluster.Players)
+			}
+		}
+		cluster.Position = majorityPosition(positions[c])
+		cluster.Label = roleLabel(centroids[c], cluster.Position, used)
+	}
+
+	for i, point := range points {
+		c := labels[i]
+		model.Assignments = append(model.Assignments, &domain.PlayerRole{
+			PlayerID:   players[i].ID,
+			Name:       players[i].Name,
+			Position:   players[i].Position,
+			RoleID:     clusters[c].ID,
+			Label:      clusters[c].Label,
+			Confidence: roleConfidence(point, centroids, c),
+			Minutes:    profiles[i].minutes,
+		})
+	}
+
+	sort.SliceStable(clusters, func(i, j int) bool {
+		return clusters[i].Players > clusters[j].Players
+	})
+	model.Clusters = clusters
+
+	return model
+}
+
+// kmeans cluster points into k groups with k-means++ seeding, the best of several restarts is kept,
+// returns the centroids, the cluster of every point and the inertia
+func kmeans(points [][]float64, k int, rng *rand.Rand) ([][]float64, []int, float64) {
+	var bestCentroids [][]float64
+	var bestLabels []int
+	bestInertia := math.Inf(1)
+
+	for restart := 0; restart < kmeansRestarts; restart++ {
+		centroids := kmeansPlusPlus(points, k, rng)
+		labels := make([]int, len(points))
+
+		for iteration := 0; iteration < kmeansIterations; iteration++ {
+			changed := iteration == 0
+			for i, point := range points {
+				if nearest, _ := nearestCentroid(point, centroids); nearest != labels[i] {
+					labels[i] = nearest
+					changed = true
+				}
+			}
+			if !changed {
+				break
+			}
+
+			counts := make([]int, k)
+			next := make([][]float64, k)
+			for c := range next {
+				next[c] = make([]float64, len(points[0]))
+			}
+			for i, point := range points {
+				counts[labels[i]]++
+				for m, value := range point {
+					next[labels[i]][m] += value
+				}
+			}
+			for c := range next {
+				if counts[c] == 0 {
+					// an empty cluster restarts at the point furthest from its centroid
+					next[c] = append([]float64(nil), points[furthestPoint(points, centroids, labels)]...)
+					continue
+				}
+				for m := range next[c] {
+					next[c][m] /= float64(counts[c])
+				}
+			}
+			centroids = next
+		}
+
+		var inertia float64
+		for i, point := range points {
+			inertia += squaredDistance(point, centroids[labels[i]])
+		}
+		if inertia < bestInertia {
+			bestCentroids, bestLabels, bestInertia = centroids, labels, inertia
+		}
+	}
+
+	return bestCentroids, bestLabels, bestInertia
+}
+
+// kmeansPlusPlus pick k initial centroids, each with probability proportional to its
+// squared distance from the centroids already picked
+func kmeansPlusPlus(points [][]float64, k int, rng *rand.Rand) [][]float64 {
+	centroids := [][]float64{append([]float64(nil), points[rng.Intn(len(points))]...)}
+	distances := make([]float64, len(points))
+
+	for len(centroids) < k {
+		var total float64
+		for i, point := range points {
+			_, distances[i] = nearestCentroid(point, centroids)
+			total += distances[i]
+		}
+
+		next := len(points) - 1
+		if total > 0 {
+			target := rng.Float64() * total
+			for i, distance := range distances {
+				target -= distance
+				if target < 0 {
+					next = i
+					break
+				}
+			}
+		} else {
+			next = rng.Intn(len(points))
+		}
+		centroids = append(centroids, append([]float64(nil), points[next]...))
+	}
+
+	return centroids
+}
+
+// nearestCentroid index of and squared distance to the closest centroid
+func nearestCentroid(point []float64, centroids [][]float64) (int, float64) {
+	nearest, best := 0, math.Inf(1)
+	for c, centroid := range centroids {
+		if distance := squaredDistance(point, centroid); distance < best {
+			nearest, best = c, distance
+		}
+	}
+	return nearest, best
+}
+
+// furthestPoint index of the point furthest from the centroid of its cluster
+func furthestPoint(points [][]float64, centroids [][]float64, labels []int) int {
+	furthest, best := 0, -1.0
+	for i, point := range points {
+		if distance := squaredDistance(point, centroids[labels[i]]); distance > best {
+			furthest, best = i, distance
+		}
+	}
+	return furthest
+}
+
+func squaredDistance(a, b []float64) float64 {
+	var sum float64
+	for i := range a {
+		sum += (a[i] - b[i]) * (a[i] - b[i])
+	}
+	return sum
+}
+
+// stableRoleIDs ID of every centroid, the previous role a centroid is matched to
+// with minimal total distance is kept when it is close enough, otherwise a new ID is made
+func stableRoleIDs(centroids [][]float64, previous *domain.RoleModel) []string {
+	ids := make([]string, len(centroids))
+	for c := range ids {
+		ids[c] = uuid.New().String()
+	}
+	if previous == nil || len(previous.Clusters) == 0 {
+		return ids
+	}
+
+	// pad with unmatched columns so there are at least as many columns as centroids
+	columns := len(previous.Clusters)
+	if columns < len(centroids) {
+		columns = len(centroids)
+	}
+	cost := make([][]float64, len(centroids))
+	for c, centroid := range centroids {
+		cost[c] = make([]float64, columns)
+		for p := range cost[c] {
+			cost[c][p] = roleMatchDistance * roleMatchDistance
+			if p < len(previous.Clusters) {
+				old := make([]float64, len(profileMetrics))
+				for m, metric := range profileMetrics {
+					old[m] = previous.Clusters[p].Centroid[metric]
+				}
+				cost[c][p] = math.Min(cost[c][p], squaredDistance(centroid, old))
+			}
+		}
+	}
+
+	for c, p := range hungarian(cost) {
+		if p >= len(previous.Clusters) {
+			continue
+		}
+		if cost[c][p] < roleMatchDistance*roleMatchDistance {
+			ids[c] = previous.Clusters[p].ID
+		}
+	}
+
+	return ids
+}
+
+// roleLabel describe a centroid by its strongest metrics and majority position, a
+// label already in use gets the next strongest metric to tell the roles apart
+func roleLabel(centroid []float64, position string, used map[string]bool) string {
+	order := make([]int, len(centroid))
+	for i := range order {
+		order[i] = i
+	}
+	sort.SliceStable(order, func(i, j int) bool {
+		return centroid[order[i]] > centroid[order[j]]
+	})
+
+	var features []string
+	for _, m := range order {
+		if centroid[m] < roleFeatureThreshold {
+			break
+		}
+		features = append(features, roleFeatureLabels[profileMetrics[m]])
+	}
+
+	noun := strings.ToLower(position)
+	if noun == "" {
+		noun = "player"
+	}
+
+	label := "low-volume " + noun
+	for n := 1; n <= len(features); n++ {
+		label = strings.Join(features[:n], ", ") + " " + noun
+		if n >= 2 && !used[label] {
+			break
+		}
+	}
+	for n, base := 2, label; used[label]; n++ {
+		label = fmt.Sprintf("%s %d", base, n)
+	}
+
+	used[label] = true
+	return label
+}
+
+// majorityPosition most common position, ties broken by name
+func majorityPosition(counts map[string]int) string {
+	best, bestCount := "", 0
+	for position, count := range counts {
+		if count > bestCount || (count == bestCount && position < best) {
+			best, bestCount = position, count
+		}
+	}
+	return best
+}
+
+// roleConfidence soft assignment weight of the point's own cluster, a gaussian
+// kernel over the squared distances to every centroid
+func roleConfidence(point []float64, centroids [][]float64, cluster int) float64 {
+	distances := make([]float64, len(centroids))
+	nearest := math.Inf(1)
+	for c, centroid := range centroids {
+		distances[c] = squaredDistance(point, centroid)
+		nearest = math.Min(nearest, distances[c])
+	}
+
+	var total float64
+	for _, distance := range distances {
+		total += math.Exp(-(distance - nearest) / 2)
+	}
+
+	return math.Exp(-(distances[cluster]-nearest)/2) / total
+}
@@ -0,0 +1,109 @@
+package service
+
+import (
+	"football-analytics/internal/domain"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+// roleFixture two obvious groups, high scoring forwards and high tackling defenders
+func roleFixture() ([]*domain.Player, []*playerProfile) {
+	var players []*domain.Player
+	var profiles []*playerProfile
+	for i := 0; i < 6; i++ {
+		forward := i%2 == 0
+		player := &domain.Player{ID: string(rune('a' + i)), Position: "Defender"}
+		stat := &domain.PlayerMatchStats{MinutesPlayed: 900, Tackles: 40 + i, Interceptions: 30, Passes: 500}
+		if forward {
+			player.Position = "Forward"
+			stat = &domain.PlayerMatchStats{MinutesPlayed: 900, Goals: 8 + i, Shots: 40, ShotsOnTarget: 20, Passes: 200}
+		}
+		players = append(players, player)
+		profiles = append(profiles, buildProfile(player.ID, []*domain.PlayerMatchStats{stat}))
+	}
+	return players, profiles
+}
+
+func TestBuildRoleModel(t *testing.T) {
+	players, profiles := roleFixture()
+
+	model := buildRoleModel(players, profiles, 2, nil)
+
+	assert.Len(t, model.Clusters, 2)
+	roles := make(map[string]*domain.PlayerRole)
+	for _, role := range model.Assignments {
+		roles[role.PlayerID] = role
+		assert.Greater(t, role.Confidence, 0.9)
+	}
+	assert.Equal(t, roles["a"].RoleID, roles["c"].RoleID)
+	assert.Equal(t, roles["b"].RoleID, roles["d"].RoleID)
+	assert.NotEqual(t, roles["a"].RoleID, roles["b"].RoleID)
+	assert.Contains(t, roles["a"].Label, "forward")
+	assert.Contains(t, roles["b"].Label, "defender")
+}
+
+func TestBuildRoleModelKeepsRoleIDs(t *testing.T) {
+	players, profiles := roleFixture()
+
+	first := buildRoleModel(players, profiles, 2, nil)
+	second := buildRoleModel(players, profiles, 2, first)
+
+	ids := make(map[string]string)
+	for _, role := range first.Assignments {
+		ids[role.PlayerID] = role.RoleID
+	}
+	for _, role := range second.Assignments {
+		assert.Equal(t, ids[role.PlayerID], role.RoleID)
+	}
+}
+
+// memoryRoleRepository keep saved role models in memory, the last saved is the latest
+type memoryRoleRepository struct {
+	models []*domain.RoleModel
+}
+
+func (r *memoryRoleRepository) Save(model *domain.RoleModel) error {
+	r.models = append(r.models, model)
+	return nil
+}
+
+func (r *memoryRoleRepository) GetLatest() (*domain.RoleModel, error) {
+	if len(r.models) == 0 {
+		return nil, domain.ErrNotFound
+	}
+	return r.models[len(r.models)-1], nil
+}
+
+func (r *memoryRoleRepository) GetLatestBySeason(season string) (*domain.RoleModel, error) {
+	for i := len(r.models) - 1; i >= 0; i-- {
+		if r.models[i].Season == season {
+			return r.models[i], nil
+		}
+	}
+	return nil, domain.ErrNotFound
+}
+
+func TestGetPlayerRoleDoesNotCluster(t *testing.T) {
+	roleRepo := &memoryRoleRepository{}
+	service := &analyticsService{roleRepo: roleRepo}
+
+	_, err := service.GetPlayerRole("p1", "2024/25")
+
+	assert.ErrorIs(t, err, domain.ErrNotFound)
+	assert.Empty(t, roleRepo.models)
+}
+
+func TestGetPlayerRole(t *testing.T) {
+	players, profiles := roleFixture()
+	model := buildRoleModel(players, profiles, 2, nil)
+	model.Season = "2024/25"
+	service := &analyticsService{roleRepo: &memoryRoleRepository{models: []*domain.RoleModel{model}}}
+
+	role, err := service.GetPlayerRole(players[0].ID, "2024/25")
+	assert.NoError(t, err)
+	assert.Equal(t, players[0].ID, role.PlayerID)
+
+	_, err = service.GetPlayerRole(players[0].ID, "2023/24")
+	assert.ErrorIs(t, err, domain.ErrNotFound)
+}
@@ -0,0 +1,203 @@
+package service
+
+import (
+	"football-analytics/internal/domain"
+	"log"
+	"math"
+)
+
+// RollupService is interface for keeping player season rollups in step with the raw stats,
+// register it as observer of the observed stats and match repositories
+type RollupService interface {
+	domain.StatsObserver
+	domain.MatchObserver
+	Verify(repair bool) (*domain.RollupReport, error)
+}
+
+type rollupService struct {
+	rollupRepo      domain.RollupRepository
+	playerStatsRepo domain.PlayerMatchStatsRepository
+	matchRepo       domain.MatchRepository
+	calendar        *seasonCalendar
+}
+
+// NewRollupService create instance of RollupService
+func NewRollupService(
+	rollupRepo domain.RollupRepository,
+	playerStatsRepo domain.PlayerMatchStatsRepository,
+	matchRepo domain.MatchRepository,
+	seasonRepo domain.SeasonRepository,
+) RollupService {
+	return &rollupService{
+		rollupRepo:      rollupRepo,
+		playerStatsRepo: playerStatsRepo,
+		matchRepo:       matchRepo,
+		calendar:        newSeasonCalendar(seasonRepo),
+	}
+}
+
+// StatsChanged take the old row out of its rollup and add the new one, a failed update is
+// logged and left for Verify to repair
+func (s *rollupService) StatsChanged(old, new *domain.PlayerMatchStats) {
+	for _, change := range []struct {
+		stats *domain.PlayerMatchStats
+		sign  int
+	}{{old, -1}, {new, 1}} {
+		if change.stats == nil {
+			continue
+		}
+
+		match, err := s.matchRepo.GetByID(change.stats.MatchID)
+		if err != nil {
+			log.Printf("rollup: match %s of stats %s: %v", change.stats.MatchID, change.stats.ID, err)
+			continue
+		}
+
+		season, err := s.calendar.seasonOf(match)
+		if err != nil {
+			log.Printf("rollup: season of match %s: %v", match.ID, err)
+			continue
+		}
+
+		if err := s.rollupRepo.Apply(rollupDelta(change.stats, match, season.Label, change.sign)); err != nil {
+			log.Printf("rollup: apply stats %s: %v", change.stats.ID, err)
+		}
+	}
+}
+
+// MatchChanged move the stats of a match to another rollup when its season or competition changes
+func (s *rollupService) MatchChanged(old, new *domain.Match) {
+	if old == nil || new == nil {
+		// a new match has no stats yet and a match with stats can not be deleted
+		return
+	}
+	oldSeason, err := s.calendar.seasonOf(old)
+	if err != nil {
+		log.Printf("rollup: season of match %s: %v", old.ID, err)
+		return
+	}
+	newSeason, err := s.calendar.seasonOf(new)
+	if err != nil {
+		log.Printf("rollup: season of match %s: %v", new.ID, err)
+		return
+	}
+	if oldSeason.Label == newSeason.Label && old.Competition == new.Competition {
+		return
+	}
+
+	stats, err := s.playerStatsRepo.ListByMatchID(new.ID)
+	if err != nil {
+		log.Printf("rollup: stats of match %s: %v", new.ID, err)
+		return
+	}
+
+	for _, stat := range stats {
+		if err := s.rollupRepo.Apply(rollupDelta(stat, old, oldSeason.Label, -1)); err != nil {
+			log.Printf("rollup: apply stats %s: %v", stat.ID, err)
+		}
+		if err := s.rollupRepo.Apply(rollupDelta(stat, new, newSeason.Label, 1)); err != nil {
+			log.Printf("rollup: apply stats %s: %v", stat.ID, err)
+		}
+	}
+}
+
+// Verify compare every rollup row with the raw stats, with repair the drifted rows are rewritten
+func (s *rollupService) Verify(repair bool) (*domain.RollupReport, error) {
+	expected, err := s.rollupRepo.ComputeFromRaw()
+	if err != nil {
+		return nil, err
+	}
+
+	actual, err := s.rollupRepo.List()
+	if err != nil {
+		return nil, err
+	}
+
+	report := &domain.RollupReport{Checked: len(expected), Drifted: compareRollups(expected, actual)}
+
+	if repair {
+		for _, drift := range report.Drifted {
+			if drift.Expected == nil {
+				err = s.rollupRepo.Delete(drift.Actual.PlayerID, drift.Actual.Season, drift.Actual.Competition)
+			} else {
+				err = s.rollupRepo.Replace(drift.Expected)
+			}
+			if err != nil {
+				return nil, err
+			}
+		}
+		report.Repaired = true
+	}
+
+	return report, nil
+}
+
+// compareRollups rows that differ between the expected and the actual rollups, or exist on one side only
+func compareRollups(expected, actual []*domain.PlayerSeasonRollup) []*domain.RollupDrift {
+	key := func(r *domain.PlayerSeasonRollup) string {
+		return r.PlayerID + "|" + r.Season + "|" + r.Competition
+	}
+
+	actualByKey := make(map[string]*domain.PlayerSeasonRollup)
+	for _, rollup := range actual {
+		actualByKey[key(rollup)] = rollup
+	}
+
+	var drifted []*domain.RollupDrift
+	for _, want := range expected {
+		got, ok := actualByKey[key(want)]
+		delete(actualByKey, key(want))
+		if !ok || !sameRollupTotals(want, got) {
+			drifted = append(drifted, &domain.RollupDrift{Expected: want, Actual: got})
+		}
+	}
+	for _, rollup := range actual {
+		if _, ok := actualByKey[key(rollup)]; ok {
+			drifted = append(drifted, &domain.RollupDrift{Actual: rollup})
+		}
+	}
+
+	return drifted
+}
+
+// sameRollupTotals whether two rollups have the same totals, sums of decimals are compared with a tolerance
+func sameRollupTotals(a, b *domain.PlayerSeasonRollup) bool {
+	const tolerance = 1e-6
+	return a.MatchesPlayed == b.MatchesPlayed &&
+		a.MinutesPlayed == b.MinutesPlayed &&
+		a.Goals == b.Goals &&
+		a.Assists == b.Assists &&
+		a.Passes == b.Passes &&
+		a.Shots == b.Shots &&
+		a.ShotsOnTarget == b.ShotsOnTarget &&
+		a.Tackles == b.Tackles &&
+		a.Interceptions == b.Interceptions &&
+		a.Fouls == b.Fouls &&
+		a.YellowCards == b.YellowCards &&
+		a.RedCards == b.RedCards &&
+		math.Abs(a.PassAccuracySum-b.PassAccuracySum) < tolerance &&
+		math.Abs(a.DistanceCovered-b.DistanceCovered) < tolerance
+}
+
+// rollupDelta totals of one stats row in the rollup of its match and season, negated for sign -1
+func rollupDelta(stats *domain.PlayerMatchStats, match *domain.Match, season string, sign int) *domain.PlayerSeasonRollup {
+	return &domain.PlayerSeasonRollup{
+		PlayerID:        stats.PlayerID,
+		Season:          season,
+		Competition:     match.Competition,
+		MatchesPlayed:   sign,
+		MinutesPlayed:   sign * stats.MinutesPlayed,
+		Goals:           sign * stats.Goals,
+		Assists:         sign * stats.Assists,
+		Passes:          sign * stats.Passes,
+		PassAccuracySum: float64(sign) * stats.PassAccuracy,
+		Shots:           sign * stats.Shots,
+		ShotsOnTarget:   sign * stats.ShotsOnTarget,
+		Tackles:         sign * stats.Tackles,
+		Interceptions:   sign * stats.Interceptions,
+		Fouls:           sign * stats.Fouls,
+		YellowCards:     sign * stats.YellowCards,
+		RedCards:        sign * stats.RedCards,
+		DistanceCovered: float64(sign) * stats.DistanceCovered,
+	}
+}
@@ -0,0 +1,74 @@
+package service
+
+import (
+	"football-analytics/internal/domain"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+)
+
+// memoryRollupRepository apply deltas to rollups kept in memory
+type memoryRollupRepository struct {
+	domain.RollupRepository
+	rows map[string]*domain.PlayerSeasonRollup
+}
+
+func (r *memoryRollupRepository) Apply(delta *domain.PlayerSeasonRollup) error {
+	key := delta.PlayerID + "|" + delta.Season + "|" + delta.Competition
+	row, ok := r.rows[key]
+	if !ok {
+		row = &domain.PlayerSeasonRollup{PlayerID: delta.PlayerID, Season: delta.Season, Competition: delta.Competition}
+		r.rows[key] = row
+	}
+	row.MatchesPlayed += delta.MatchesPlayed
+	row.MinutesPlayed += delta.MinutesPlayed
+	row.Goals += delta.Goals
+	if row.MatchesPlayed <= 0 {
+		delete(r.rows, key)
+	}
+	return nil
+}
+
+func TestRollupServiceStatsChanged(t *testing.T) {
+	rollupRepo := &memoryRollupRepository{rows: make(map[string]*domain.PlayerSeasonRollup)}
+	matchRepo := new(MockMatchRepository)
+	matchRepo.On("GetByID", "m1").Return(&domain.Match{ID: "m1", Competition: "League", Date: time.Date(2024, 9, 1, 0, 0, 0, 0, time.UTC)}, nil)
+	matchRepo.On("GetByID", "m2").Return(&domain.Match{ID: "m2", Competition: "Cup", Date: time.Date(2025, 3, 1, 0, 0, 0, 0, time.UTC)}, nil)
+	service := NewRollupService(rollupRepo, nil, matchRepo, nil)
+
+	first := &domain.PlayerMatchStats{ID: "s1", PlayerID: "p1", MatchID: "m1", MinutesPlayed: 90, Goals: 1}
+	second := &domain.PlayerMatchStats{ID: "s2", PlayerID: "p1", MatchID: "m2", MinutesPlayed: 60}
+	service.StatsChanged(nil, first)
+	service.StatsChanged(nil, second)
+	corrected := *first
+	corrected.Goals = 2
+	service.StatsChanged(first, &corrected)
+
+	league := rollupRepo.rows["p1|2024/25|League"]
+	assert.Equal(t, 1, league.MatchesPlayed)
+	assert.Equal(t, 2, league.Goals)
+	assert.Equal(t, 60, rollupRepo.rows["p1|2024/25|Cup"].MinutesPlayed)
+
+	service.StatsChanged(second, nil)
+	assert.NotContains(t, rollupRepo.rows, "p1|2024/25|Cup")
+}
+
+func TestCompareRollups(t *testing.T) {
+	expected := []*domain.PlayerSeasonRollup{
+		{PlayerID: "p1", Season: "2024/25", Competition: "League", MatchesPlayed: 3, Goals: 2},
+		{PlayerID: "p2", Season: "2024/25", Competition: "League", MatchesPlayed: 1},
+	}
+	actual := []*domain.PlayerSeasonRollup{
+		{PlayerID: "p1", Season: "2024/25", Competition: "League", MatchesPlayed: 3, Goals: 1},
+		{PlayerID: "p3", Season: "2024/25", Competition: "League", MatchesPlayed: 1},
+	}
+
+	drifted := compareRollups(expected, actual)
+
+	if assert.Len(t, drifted, 3) {
+		assert.Equal(t, 1, drifted[0].Actual.Goals)
+		assert.Nil(t, drifted[1].Actual)
+		assert.Nil(t, drifted[2].Expected)
+	}
+}
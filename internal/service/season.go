@@ -0,0 +1,214 @@
+package service
+
+import (
+	"fmt"
+	"football-analytics/internal/domain"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// seasonCalendar resolve seasons from the seasons of each competition, competitions without
+// seasons (or every competition with a nil repository) fall back to seasons starting on August 1
+type seasonCalendar struct {
+	seasonRepo domain.SeasonRepository
+
+	// seasons read once by a memoized calendar, nil for a calendar reading the repository every time
+	seasons []*domain.Season
+}
+
+func newSeasonCalendar(seasonRepo domain.SeasonRepository) *seasonCalendar {
+	return &seasonCalendar{seasonRepo: seasonRepo}
+}
+
+// memoized copy of the calendar reading the seasons once, to resolve many subjects in one request
+func (c *seasonCalendar) memoized() (*seasonCalendar, error) {
+	seasons, err := c.list()
+	if err != nil {
+		return nil, err
+	}
+	if seasons == nil {
+		seasons = []*domain.Season{}
+	}
+
+	return &seasonCalendar{seasonRepo: c.seasonRepo, seasons: seasons}, nil
+}
+
+// currentStart start of the current season of the competition at now, for an empty competition
+// the earliest start of the current seasons of every competition
+func (c *seasonCalendar) currentStart(competition string, now time.Time) (time.Time, error) {
+	if competition != "" {
+		season, err := c.seasonAt(competition, now)
+		if err != nil {
+			return time.Time{}, err
+		}
+		return season.StartDate, nil
+	}
+
+	seasons, err := c.list()
+	if err != nil {
+		return time.Time{}, err
+	}
+
+	start := defaultSeason("", now).StartDate
+	for _, season := range currentSeasons(seasons, now) {
+		if season.StartDate.Before(start) {
+			start = season.StartDate
+		}
+	}
+	return start, nil
+}
+
+// seasonAt the season of the competition in play at t, the latest one started before t during the
+// off-season, or the default August season when the competition has no season started yet
+func (c *seasonCalendar) seasonAt(competition string, t time.Time) (*domain.Season, error) {
+	var seasons []*domain.Season
+	switch {
+	case c.seasons != nil:
+		for _, season := range c.seasons {
+			if season.Competition == competition {
+				seasons = append(seasons, season)
+			}
+		}
+	case c.seasonRepo != nil:
+		var err error
+		if seasons, err = c.seasonRepo.ListByCompetition(competition); err != nil {
+			return nil, err
+		}
+	}
+
+	return pickSeason(seasons, competition, t), nil
+}
+
+// seasonOf the season a match belongs to, the linked one or the one in play at its date
+func (c *seasonCalendar) seasonOf(match *domain.Match) (*domain.Season, error) {
+	if match.SeasonID != "" && c.seasons != nil {
+		for _, season := range c.seasons {
+			if season.ID == match.SeasonID {
+				return season, nil
+			}
+		}
+	} else if match.SeasonID != "" && c.seasonRepo != nil {
+		return c.seasonRepo.GetByID(match.SeasonID)
+	}
+	return c.seasonAt(match.Competition, match.Date)
+}
+
+// labelRange start, end and label of the seasons labelled label, of one competition or of every
+// one if competition is empty, a label like "2024/25" unknown to the calendar is the default August
+// season unless the competition has seasons of its own, any other unknown label is an error
+func (c *seasonCalendar) labelRange(label, competition string) (time.Time, time.Time, string, error) {
+	seasons, err := c.list()
+	if err != nil {
+		return time.Time{}, time.Time{}, "", err
+	}
+
+	var start, end time.Time
+	ownSeasons := false
+	for _, season := range seasons {
+		if competition != "" && season.Competition != competition {
+			continue
+		}
+		ownSeasons = true
+		if season.Label != label {
+			continue
+		}
+		if start.IsZero() || season.StartDate.Before(start) {
+			start = season.StartDate
+		}
+		if season.EndDate.After(end) {
+			end = season.EndDate
+		}
+	}
+	if !start.IsZero() {
+		return start, end, label, nil
+	}
+	if competition != "" && ownSeasons {
+		return time.Time{}, time.Time{}, "", fmt.Errorf("unknown season %q of %s", label, competition)
+	}
+
+	start, end, err = defaultSeasonRange(label)
+	if err != nil {
+		return time.Time{}, time.Time{}, "", err
+	}
+	return start, end, label, nil
+}
+
+func (c *seasonCalendar) list() ([]*domain.Season, error) {
+	if c.seasons != nil || c.seasonRepo == nil {
+		return c.seasons, nil
+	}
+	return c.seasonRepo.List()
+}
+
+// pickSeason the latest of the seasons started on or before t, or the default season if none did
+func pickSeason(seasons []*domain.Season, competition string, t time.Time) *domain.Season {
+	var current *domain.Season
+	for _, season := range seasons {
+		if season.StartDate.After(t) {
+			continue
+		}
+		if current == nil || season.StartDate.After(current.StartDate) {
+			current = season
+		}
+	}
+	if current == nil {
+		return defaultSeason(competition, t)
+	}
+	return current
+}
+
+// currentSeasons the current season of every competition at t as picked by pickSeason,
+// competitions whose last season ended more than a year before t are left out
+func currentSeasons(seasons []*domain.Season, t time.Time) []*domain.Season {
+	byCompetition := make(map[string][]*domain.Season)
+	var competitions []string
+	for _, season := range seasons {
+		if _, ok := byCompetition[season.Competition]; !ok {
+			competitions = append(competitions, season.Competition)
+		}
+		byCompetition[season.Competition] = append(byCompetition[season.Competition], season)
+	}
+
+	var current []*domain.Season
+	for _, competition := range competitions {
+		season := pickSeason(byCompetition[competition], competition, t)
+		if season.EndDate.After(t.AddDate(-1, 0, 0)) {
+			current = append(current, season)
+		}
+	}
+	return current
+}
+
+// defaultSeason the August to August season containing t, for competitions without a calendar
+func defaultSeason(competition string, t time.Time) *domain.Season {
+	t = t.UTC()
+	year := t.Year()
+	if t.Month() < 8 {
+		year--
+	}
+
+	start := time.Date(year, 8, 1, 0, 0, 0, 0, time.UTC)
+	return &domain.Season{
+		Competition: competition,
+		Label:       fmt.Sprintf("%d/%02d", year, (year+1)%100),
+		StartDate:   start,
+		EndDate:     start.AddDate(1, 0, 0),
+	}
+}
+
+// defaultSeasonRange start and end of the default season labelled like "2024/25", a single year
+// is not a default label as it can not tell an August season from a calendar year one
+func defaultSeasonRange(label string) (time.Time, time.Time, error) {
+	yearPart, _, _ := strings.Cut(label, "/")
+	year, err := strconv.Atoi(yearPart)
+	if err != nil || year < 1900 || year > 9999 {
+		return time.Time{}, time.Time{}, fmt.Errorf("unknown season %q", label)
+	}
+
+	start := time.Date(year, 8, 1, 0, 0, 0, 0, time.UTC)
+	if defaultSeason("", start).Label != label {
+		return time.Time{}, time.Time{}, fmt.Errorf("unknown season %q", label)
+	}
+	return start, start.AddDate(1, 0, 0), nil
+}
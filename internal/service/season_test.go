@@ -0,0 +1,131 @@
+package service
+
+import (
+	"football-analytics/internal/domain"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+)
+
+// memorySeasonRepository list seasons kept in memory
+type memorySeasonRepository struct {
+	domain.SeasonRepository
+	seasons []*domain.Season
+}
+
+func (r *memorySeasonRepository) List() ([]*domain.Season, error) {
+	return r.seasons, nil
+}
+
+func (r *memorySeasonRepository) ListByCompetition(competition string) ([]*domain.Season, error) {
+	var seasons []*domain.Season
+	for _, season := range r.seasons {
+		if season.Competition == competition {
+			seasons = append(seasons, season)
+		}
+	}
+	return seasons, nil
+}
+
+func utcDate(year int, month time.Month, day int) time.Time {
+	return time.Date(year, month, day, 0, 0, 0, 0, time.UTC)
+}
+
+func newTestCalendar() *seasonCalendar {
+	return newSeasonCalendar(&memorySeasonRepository{seasons: []*domain.Season{
+		{ID: "mls-2024", Competition: "MLS", Label: "2024", StartDate: utcDate(2024, 2, 21), EndDate: utcDate(2024, 12, 8)},
+		{ID: "mls-2025", Competition: "MLS", Label: "2025", StartDate: utcDate(2025, 2, 22), EndDate: utcDate(2025, 12, 7)},
+		{ID: "pl-2024", Competition: "Premier League", Label: "2024/25", StartDate: utcDate(2024, 8, 16), EndDate: utcDate(2025, 5, 26)},
+	}})
+}
+
+func TestSeasonCalendarCurrentStart(t *testing.T) {
+	calendar := newTestCalendar()
+
+	start, err := calendar.currentStart("MLS", utcDate(2025, 6, 1))
+	assert.NoError(t, err)
+	assert.Equal(t, utcDate(2025, 2, 22), start)
+
+	// during the off-season the last season is still the current one
+	start, err = calendar.currentStart("MLS", utcDate(2026, 1, 10))
+	assert.NoError(t, err)
+	assert.Equal(t, utcDate(2025, 2, 22), start)
+
+	// competitions without seasons start in August
+	start, err = calendar.currentStart("Eredivisie", utcDate(2025, 6, 1))
+	assert.NoError(t, err)
+	assert.Equal(t, utcDate(2024, 8, 1), start)
+
+	// every competition together starts with the earliest current season
+	start, err = calendar.currentStart("", utcDate(2025, 3, 1))
+	assert.NoError(t, err)
+	assert.Equal(t, utcDate(2024, 8, 1), start)
+}
+
+func TestSeasonCalendarLabelRange(t *testing.T) {
+	calendar := newTestCalendar()
+
+	start, end, label, err := calendar.labelRange("2025", "MLS")
+	assert.NoError(t, err)
+	assert.Equal(t, utcDate(2025, 2, 22), start)
+	assert.Equal(t, utcDate(2025, 12, 7), end)
+	assert.Equal(t, "2025", label)
+
+	start, end, label, err = calendar.labelRange("2023/24", "")
+	assert.NoError(t, err)
+	assert.Equal(t, utcDate(2023, 8, 1), start)
+	assert.Equal(t, utcDate(2024, 8, 1), end)
+	assert.Equal(t, "2023/24", label)
+
+	// competitions without seasons only know the default labels
+	start, _, _, err = calendar.labelRange("2023/24", "Eredivisie")
+	assert.NoError(t, err)
+	assert.Equal(t, utcDate(2023, 8, 1), start)
+
+	for _, unknown := range []struct{ label, competition string }{
+		{"last", ""},
+		{"2023", ""},
+		{"2025", "Eredivisie"},
+		{"2023/25", ""},
+		{"2025", "Premier League"},
+		{"2023/24", "MLS"},
+	} {
+		_, _, _, err = calendar.labelRange(unknown.label, unknown.competition)
+		assert.Error(t, err, "%s of %q", unknown.label, unknown.competition)
+	}
+}
+
+func TestSeasonCalendarSeasonOf(t *testing.T) {
+	calendar := newTestCalendar()
+
+	season, err := calendar.seasonOf(&domain.Match{Competition: "MLS", Date: utcDate(2024, 10, 5)})
+	assert.NoError(t, err)
+	assert.Equal(t, "2024", season.Label)
+
+	season, err = calendar.seasonOf(&domain.Match{Competition: "Premier League", Date: utcDate(2025, 1, 4)})
+	assert.NoError(t, err)
+	assert.Equal(t, "2024/25", season.Label)
+}
+
+func TestSeasonLinkedMatchRepository(t *testing.T) {
+	matchRepo := new(MockMatchRepository)
+	matchRepo.On("Create", mock.Anything).Return(nil)
+	matchRepo.On("Update", mock.Anything).Return(nil)
+	repo := NewSeasonLinkedMatchRepository(matchRepo, newTestCalendar().seasonRepo)
+
+	match := &domain.Match{ID: "m1", Competition: "MLS", Date: utcDate(2025, 3, 1)}
+	assert.NoError(t, repo.Create(match))
+	assert.Equal(t, "mls-2025", match.SeasonID)
+
+	// a match moved to the off-season leaves its season
+	match.Date = utcDate(2026, 1, 10)
+	assert.NoError(t, repo.Update(match))
+	assert.Empty(t, match.SeasonID)
+
+	other := &domain.Match{ID: "m2", Competition: "Eredivisie", Date: utcDate(2025, 3, 1)}
+	assert.NoError(t, repo.Create(other))
+	assert.Empty(t, other.SeasonID)
+	matchRepo.AssertNumberOfCalls(t, "Create", 2)
+}
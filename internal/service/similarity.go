@@ -0,0 +1,218 @@
+package service
+
+import (
+	"fmt"
+	"football-analytics/internal/domain"
+	"math"
+	"sort"
+)
+
+const (
+	defaultSimilarityTopK = 10
+	similarityDrivers     = 3
+)
+
+// FindSimilarPlayers find the players whose per 90 profile is closest to the player in the query
+func (s *analyticsService) FindSimilarPlayers(query domain.SimilarityQuery) ([]*domain.SimilarPlayer, error) {
+	measure := query.Measure
+	if measure == "" {
+		measure = domain.SimilarityCosine
+	}
+	if measure != domain.SimilarityCosine && measure != domain.SimilarityEuclidean {
+		return nil, fmt.Errorf("unknown similarity measure %q", query.Measure)
+	}
+
+	if query.StartDate.IsZero() || query.EndDate.IsZero() || query.EndDate.Before(query.StartDate) {
+		return nil, fmt.Errorf("%w: similarity needs a start date before its end date", domain.ErrInvalidTimeRange)
+	}
+
+	topK := query.TopK
+	if topK <= 0 {
+		topK = defaultSimilarityTopK
+	}
+
+	target, err := s.playerRepo.GetByID(query.PlayerID)
+	if err != nil {
+		return nil, err
+	}
+
+	statsByPlayer, err := s.statsByPlayerInRange(query.StartDate, query.EndDate)
+	if err != nil {
+		return nil, err
+	}
+
+	targetProfile := buildProfile(target.ID, statsByPlayer[target.ID])
+	if targetProfile.minutes == 0 {
+		return nil, domain.ErrNoAppearances
+	}
+
+	candidates, err := s.similarityCandidates(query, statsByPlayer)
+	if err != nil {
+		return nil, err
+	}
+
+	// the target profile is first, standardized together with the candidates
+	profiles := []*playerProfile{targetProfile}
+	for _, candidate := range candidates {
+		profiles = append(profiles, buildProfile(candidate.ID, statsByPlayer[candidate.ID]))
+	}
+	vectors := standardize(profiles)
+
+	var result []*domain.SimilarPlayer
+	for i, candidate := range candidates {
+		profile := profiles[i+1]
+		contributions := compareVectors(vectors[0], vectors[i+1], measure)
+
+		var score float64
+		if measure == domain.SimilarityCosine {
+			for _, c := range contributions {
+				score += c
+			}
+		} else {
+			var distance float64
+			for _, c := range contributions {
+				distance -= c
+			}
+			score = 1 / (1 + math.Sqrt(distance))
+		}
+
+		result = append(result, &domain.SimilarPlayer{
+			PlayerID: candidate.ID,
+			Name:     candidate.Name,
+			Position: candidate.Position,
+			Score:    score,
+			Minutes:  profile.minutes,
+			Drivers:  similarityDriversOf(targetProfile, profile, contributions),
+		})
+	}
+
+	sort.SliceStable(result, func(i, j int) bool {
+		return result[i].Score > result[j].Score
+	})
+	if len(result) > topK {
+		result = result[:topK]
+	}
+
+	return result, nil
+}
+
+// similarityCandidates players other than the target that pass the query filters
+func (s *analyticsService) similarityCandidates(query domain.SimilarityQuery, statsByPlayer map[string][]*domain.PlayerMatchStats) ([]*domain.Player, error) {
+	players, err := s.playerRepo.List()
+	if err != nil {
+		return nil, err
+	}
+
+	var leagueByTeam map[string]string
+	if len(query.Leagues) > 0 {
+		teams, err := s.teamRepo.List()
+		if err != nil {
+			return nil, err
+		}
+		leagueByTeam = make(map[string]string)
+		for _, team := range teams {
+			leagueByTeam[team.ID] = team.League
+		}
+	}
+
+	var candidates []*domain.Player
+	for _, player := range players {
+		if player.ID == query.PlayerID {
+			continue
+		}
+
+		stats := statsByPlayer[player.ID]
+		if len(stats) == 0 {
+			continue
+		}
+
+		minutes := 0
+		for _, stat := range stats {
+			minutes += stat.MinutesPlayed
+		}
+		if minutes == 0 || minutes < query.MinMinutes {
+			continue
+		}
+
+		if len(query.Positions) > 0 && !containsString(query.Positions, player.Position) {
+			continue
+		}
+
+		if leagueByTeam != nil && !containsString(query.Leagues, leagueByTeam[player.TeamID]) {
+			continue
+		}
+
+		if query.MinAge > 0 || query.MaxAge > 0 {
+			if player.Birthday.IsZero() {
+				continue
+			}
+			age := ageAt(player.Birthday, query.EndDate)
+			if (query.MinAge > 0 && age < query.MinAge) || (query.MaxAge > 0 && age > query.MaxAge) {
+				continue
+			}
+		}
+
+		candidates = append(candidates, player)
+	}
+
+	return candidates, nil
+}
+
+// compareVectors per metric contribution to the similarity of a and b,
+// for cosine the contributions sum to the cosine similarity and for euclidean
+// they are the negated squared differences
+func compareVectors(a, b []float64, measure domain.SimilarityMeasure) []float64 {
+	contributions := make([]float64, len(a))
+
+	if measure == domain.SimilarityEuclidean {
+		for k := range a {
+			contributions[k] = -(a[k] - b[k]) * (a[k] - b[k])
+		}
+		return contributions
+	}
+
+	var normA, normB float64
+	for k := range a {
+		normA += a[k] * a[k]
+		normB += b[k] * b[k]
+	}
+	if normA == 0 || normB == 0 {
+		return contributions
+	}
+
+	norm := math.Sqrt(normA) * math.Sqrt(normB)
+	for k := range a {
+		contributions[k] = a[k] * b[k] / norm
+	}
+
+	return contributions
+}
+
+// similarityDriversOf the metrics contributing most to the similarity of two profiles
+func similarityDriversOf(target, candidate *playerProfile, contributions []float64) []*domain.MetricContribution {
+	drivers := make([]*domain.MetricContribution, 0, len(profileMetrics))
+	for k, metric := range profileMetrics {
+		drivers = append(drivers, &domain.MetricContribution{
+			Metric:         metric,
+			PlayerValue:    target.per90[k],
+			CandidateValue: candidate.per90[k],
+			Contribution:   contributions[k],
+		})
+	}
+
+	sort.SliceStable(drivers, func(i, j int) bool {
+		return drivers[i].Contribution > drivers[j].Contribution
+	})
+
+	return drivers[:similarityDrivers]
+}
+
+// containsString check if values contains value
+func containsString(values []string, value string) bool {
+	for _, v := range values {
+		if v == value {
+			return true
+		}
+	}
+	return false
+}
@@ -0,0 +1,140 @@
+package service
+
+import (
+	"fmt"
+	"football-analytics/internal/domain"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+)
+
+// newSimilarityFixture players to compare with target over two matches in September 2024
+func newSimilarityFixture() *analyticsService {
+	players := &memoryPlayerRepository{players: make(map[string]*domain.Player)}
+	stats := &memoryStatsRepository{matches: make(map[string]*domain.Match), byPlayer: make(map[string][]*domain.PlayerMatchStats)}
+
+	for i, row := range []struct {
+		player  domain.Player
+		minutes int
+		per90   [4]int // goals, shots, passes, tackles
+	}{
+		{domain.Player{ID: "target", Position: "Forward"}, 90, [4]int{1, 4, 20, 1}},
+		{domain.Player{ID: "twin", Position: "Forward"}, 90, [4]int{1, 4, 22, 1}},
+		{domain.Player{ID: "mid", Position: "Midfielder"}, 90, [4]int{0, 1, 60, 3}},
+		{domain.Player{ID: "back", Position: "Defender"}, 90, [4]int{0, 0, 40, 6}},
+		{domain.Player{ID: "short", Position: "Forward"}, 45, [4]int{1, 4, 20, 1}},
+		{domain.Player{ID: "bench", Position: "Forward"}, 0, [4]int{}},
+	} {
+		player := row.player
+		players.players[player.ID] = &player
+		players.list = append(players.list, &player)
+		if row.minutes == 0 {
+			continue
+		}
+
+		for day := 0; day < 2; day++ {
+			if row.minutes < 90 && day > 0 {
+				break
+			}
+			match := &domain.Match{ID: fmt.Sprintf("m%d-%d", i, day), Date: utcDate(2024, 9, 1+7*day), Status: "completed"}
+			stats.matches[match.ID] = match
+			stats.byPlayer[player.ID] = append(stats.byPlayer[player.ID], &domain.PlayerMatchStats{
+				PlayerID:      player.ID,
+				MatchID:       match.ID,
+				MinutesPlayed: row.minutes,
+				Goals:         row.per90[0] * row.minutes / 90,
+				Shots:         row.per90[1] * row.minutes / 90,
+				Passes:        row.per90[2] * row.minutes / 90,
+				Tackles:       row.per90[3] * row.minutes / 90,
+			})
+		}
+	}
+
+	return &analyticsService{playerStatsRepo: stats, playerRepo: players}
+}
+
+func similarityQuery(measure domain.SimilarityMeasure) domain.SimilarityQuery {
+	return domain.SimilarityQuery{PlayerID: "target", StartDate: utcDate(2024, 9, 1), EndDate: utcDate(2024, 10, 1), Measure: measure, MinMinutes: 90}
+}
+
+func similarIDs(players []*domain.SimilarPlayer) []string {
+	var ids []string
+	for _, player := range players {
+		ids = append(ids, player.PlayerID)
+	}
+	return ids
+}
+
+func TestFindSimilarPlayersRanking(t *testing.T) {
+	service := newSimilarityFixture()
+
+	for _, measure := range []domain.SimilarityMeasure{domain.SimilarityCosine, domain.SimilarityEuclidean} {
+		similar, err := service.FindSimilarPlayers(similarityQuery(measure))
+		assert.NoError(t, err)
+
+		// the target, the player below the minutes and the one without minutes are left out
+		assert.Equal(t, []string{"twin", "mid", "back"}, similarIDs(similar), measure)
+		for i := 1; i < len(similar); i++ {
+			assert.GreaterOrEqual(t, similar[i-1].Score, similar[i].Score)
+		}
+		assert.Equal(t, 180, similar[0].Minutes)
+		assert.Len(t, similar[0].Drivers, similarityDrivers)
+	}
+
+	euclidean, _ := service.FindSimilarPlayers(similarityQuery(domain.SimilarityEuclidean))
+	assert.Greater(t, euclidean[0].Score, 0.0)
+	assert.LessOrEqual(t, euclidean[0].Score, 1.0)
+}
+
+func TestFindSimilarPlayersFilters(t *testing.T) {
+	service := newSimilarityFixture()
+
+	query := similarityQuery(domain.SimilarityCosine)
+	query.MinMinutes = 0
+	query.Positions = []string{"Forward"}
+	similar, err := service.FindSimilarPlayers(query)
+	assert.NoError(t, err)
+	assert.ElementsMatch(t, []string{"twin", "short"}, similarIDs(similar))
+
+	query = similarityQuery(domain.SimilarityCosine)
+	query.TopK = 1
+	similar, err = service.FindSimilarPlayers(query)
+	assert.NoError(t, err)
+	assert.Equal(t, []string{"twin"}, similarIDs(similar))
+}
+
+func TestFindSimilarPlayersErrors(t *testing.T) {
+	service := newSimilarityFixture()
+
+	query := similarityQuery("manhattan")
+	_, err := service.FindSimilarPlayers(query)
+	assert.EqualError(t, err, `unknown similarity measure "manhattan"`)
+
+	query = similarityQuery(domain.SimilarityCosine)
+	query.EndDate = time.Time{}
+	_, err = service.FindSimilarPlayers(query)
+	assert.ErrorIs(t, err, domain.ErrInvalidTimeRange)
+
+	query = similarityQuery(domain.SimilarityCosine)
+	query.StartDate, query.EndDate = query.EndDate, query.StartDate
+	_, err = service.FindSimilarPlayers(query)
+	assert.ErrorIs(t, err, domain.ErrInvalidTimeRange)
+
+	query = similarityQuery(domain.SimilarityCosine)
+	query.PlayerID = "bench"
+	_, err = service.FindSimilarPlayers(query)
+	assert.ErrorIs(t, err, domain.ErrNoAppearances)
+}
+
+func TestCompareVectors(t *testing.T) {
+	cosine := compareVectors([]float64{1, 0, 1}, []float64{1, 1, 0}, domain.SimilarityCosine)
+	assert.InDelta(t, 0.5, cosine[0], 1e-9)
+	assert.Zero(t, cosine[1])
+
+	// a zero vector is not similar to anything
+	assert.Equal(t, []float64{0, 0}, compareVectors([]float64{0, 0}, []float64{1, 1}, domain.SimilarityCosine))
+
+	euclidean := compareVectors([]float64{1, 2}, []float64{3, 2}, domain.SimilarityEuclidean)
+	assert.Equal(t, []float64{-4, 0}, euclidean)
+}
@@ -0,0 +1,266 @@
+package service
+
+import (
+	"fmt"
+	"football-analytics/internal/domain"
+	"sort"
+)
+
+// allSplitDimensions dimensions used when none are requested, in output order
+var allSplitDimensions = []domain.SplitDimension{
+	domain.SplitVenue,
+	domain.SplitCompetition,
+	domain.SplitResult,
+	domain.SplitOpponentTier,
+}
+
+// splitKeyOrder order of the fixed bucket keys, other keys are sorted by name after them
+var splitKeyOrder = map[string]int{
+	"home": 0, "away": 1,
+	"win": 0, "draw": 1, "loss": 2,
+	"top": 0, "middle": 1, "bottom": 2,
+}
+
+// GetPlayerSplits get player performance split by venue, competition, result and opponent tier
+func (s *analyticsService) GetPlayerSplits(playerID string, timeRange string, dimensions []domain.SplitDimension) (*domain.PerformanceSplits, error) {
+	if err := validateSplitDimensions(dimensions); err != nil {
+		return nil, err
+	}
+	tr, err := domain.ParseTimeRange(timeRange)
+	if err != nil {
+		return nil, err
+	}
+
+	player, err := s.playerRepo.GetByID(playerID)
+	if err != nil {
+		return nil, err
+	}
+
+	appearances, _, err := s.playerRange(playerID, tr, s.clock.Now())
+	if err != nil {
+		return nil, err
+	}
+	tiers, err := s.opponentTiers(appearances)
+	if err != nil {
+		return nil, err
+	}
+
+	teamOf := func(stat *domain.PlayerMatchStats) string {
+		if stat.TeamID != "" {
+			return stat.TeamID
+		}
+		return player.TeamID
+	}
+
+	return &domain.PerformanceSplits{
+		SubjectID: playerID,
+		TimeRange: timeRange,
+		Buckets:   splitAppearances(playerID, appearances, teamOf, dimensions, tiers),
+	}, nil
+}
+
+// GetTeamSplits get performance of the team's players split by venue, competition, result and opponent tier
+func (s *analyticsService) GetTeamSplits(teamID string, timeRange string, dimensions []domain.SplitDimension) (*domain.PerformanceSplits, error) {
+	if err := validateSplitDimensions(dimensions); err != nil {
+		return nil, err
+	}
+	tr, err := domain.ParseTimeRange(timeRange)
+	if err != nil {
+		return nil, err
+	}
+
+	players, err := s.playerRepo.List()
+	if err != nil {
+		return nil, err
+	}
+	playerTeams := make(map[string]string)
+	for _, player := range players {
+		playerTeams[player.ID] = player.TeamID
+	}
+
+	matches, err := s.teamRange(teamID, tr, s.clock.Now())
+	if err != nil {
+		return nil, err
+	}
+
+	teamOf := func(stat *domain.PlayerMatchStats) string {
+		if stat.TeamID != "" {
+			return stat.TeamID
+		}
+		return playerTeams[stat.PlayerID]
+	}
+
+	var teamMatches []*domain.Match
+	for _, match := range matches {
+		if match.HomeTeamID == teamID || match.AwayTeamID == teamID {
+			teamMatches = append(teamMatches, match)
+		}
+	}
+
+	// the stats of every match of the team are fetched in one query
+	statsByMatch, err := loadStatsByMatch(s.playerStatsRepo, teamMatches)
+	if err != nil {
+		return nil, err
+	}
+
+	var appearances []appearance
+	for _, match := range teamMatches {
+		for _, stat := range statsByMatch[match.ID] {
+			if teamOf(stat) == teamID {
+				appearances = append(appearances, appearance{match: match, stats: stat})
+			}
+		}
+	}
+	tiers, err := s.opponentTiers(appearances)
+	if err != nil {
+		return nil, err
+	}
+
+	return &domain.PerformanceSplits{
+		SubjectID: teamID,
+		TimeRange: timeRange,
+		Buckets:   splitAppearances(teamID, appearances, teamOf, dimensions, tiers),
+	}, nil
+}
+
+// opponentTiers tiers of the teams of every appearance's match in the table of its competition's
+// season on the match date, whatever the time range, teams without a match before it have no tier
+func (s *analyticsService) opponentTiers(appearances []appearance) (map[string]map[string]string, error) {
+	tiers := make(map[string]map[string]string)
+	if len(appearances) == 0 {
+		return tiers, nil
+	}
+
+	source := &tableStrengthSource{matchRepo: s.matchRepo, calendar: s.calendar}
+	scoped, err := source.scoped()
+	if err != nil {
+		return nil, err
+	}
+	tables := scoped.(*tableStrength)
+
+	for _, app := range appearances {
+		if _, ok := tiers[app.match.ID]; ok {
+			continue
+		}
+		table, err := tables.table(app.match.Competition, app.match.Date)
+		if err != nil {
+			return nil, err
+		}
+		tiers[app.match.ID] = table.tiers
+	}
+
+	return tiers, nil
+}
+
+// splitAppearances group appearances into buckets of every dimension, tiers are the ones of the
+// teams of each match by match ID, appearances where the side of the team is unknown are skipped
+func splitAppearances(
+	subjectID string,
+	appearances []appearance,
+	teamOf func(stat *domain.PlayerMatchStats) string,
+	dimensions []domain.SplitDimension,
+	tiers map[string]map[string]string,
+) []*domain.SplitBucket {
+	if len(dimensions) == 0 {
+		dimensions = allSplitDimensions
+	}
+
+	var buckets []*domain.SplitBucket
+	for _, dimension := range dimensions {
+		stats := make(map[string][]*domain.PlayerMatchStats)
+		matches := make(map[string]map[string]bool)
+
+		for _, app := range appearances {
+			teamID := teamOf(app.stats)
+			home := app.match.HomeTeamID == teamID
+			if !home && app.match.AwayTeamID != teamID {
+				continue
+			}
+
+			key := splitKey(dimension, app.match, home, tiers)
+			stats[key] = append(stats[key], app.stats)
+			if matches[key] == nil {
+				matches[key] = make(map[string]bool)
+			}
+			matches[key][app.match.ID] = true
+		}
+
+		var keys []string
+		for key := range stats {
+			keys = append(keys, key)
+		}
+		sort.Slice(keys, func(i, j int) bool {
+			oi, iFixed := splitKeyOrder[keys[i]]
+			oj, jFixed := splitKeyOrder[keys[j]]
+			if iFixed && jFixed {
+				return oi < oj
+			}
+			if iFixed != jFixed {
+				return iFixed
+			}
+			return keys[i] < keys[j]
+		})
+
+		for _, key := range keys {
+			buckets = append(buckets, &domain.SplitBucket{
+				Dimension: dimension,
+				Key:       key,
+				Matches:   len(matches[key]),
+				Metrics:   calculateMetricsFromStats(subjectID, stats[key]),
+			})
+		}
+	}
+
+	return buckets
+}
+
+// splitKey bucket of a match in a dimension, home tells which side the subject played on
+func splitKey(dimension domain.SplitDimension, match *domain.Match, home bool, tiers map[string]map[string]string) string {
+	switch dimension {
+	case domain.SplitVenue:
+		if home {
+			return "home"
+		}
+		return "away"
+	case domain.SplitCompetition:
+		return match.Competition
+	case domain.SplitResult:
+		goalsFor, goalsAgainst := match.HomeScore, match.AwayScore
+		if !home {
+			goalsFor, goalsAgainst = goalsAgainst, goalsFor
+		}
+		switch {
+		case goalsFor > goalsAgainst:
+			return "win"
+		case goalsFor < goalsAgainst:
+			return "loss"
+		default:
+			return "draw"
+		}
+	default:
+		opponent := match.AwayTeamID
+		if !home {
+			opponent = match.HomeTeamID
+		}
+		if tier, ok := tiers[match.ID][opponent]; ok {
+			return tier
+		}
+		return "unknown"
+	}
+}
+
+// validateSplitDimensions check every dimension is known
+func validateSplitDimensions(dimensions []domain.SplitDimension) error {
+	for _, dimension := range dimensions {
+		known := false
+		for _, d := range allSplitDimensions {
+			if d == dimension {
+				known = true
+			}
+		}
+		if !known {
+			return fmt.Errorf("unknown split dimension %q", dimension)
+		}
+	}
+	return nil
+}
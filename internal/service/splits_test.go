@@ -0,0 +1,119 @@
+package service
+
+import (
+	"football-analytics/internal/domain"
+	"math"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+// newSplitsFixture September 2024 results of six league teams and a cup match
+func newSplitsFixture() *analyticsService {
+	players := &memoryPlayerRepository{players: make(map[string]*domain.Player)}
+	for _, player := range []*domain.Player{
+		{ID: "p1", TeamID: "t1", Position: "Forward"},
+		{ID: "p2", TeamID: "t2", Position: "Forward"},
+		{ID: "p3", TeamID: "t2", Position: "Midfielder"},
+	} {
+		players.players[player.ID] = player
+		players.list = append(players.list, player)
+	}
+
+	matches := &memoryMatchRepository{}
+	stats := &memoryStatsRepository{matches: make(map[string]*domain.Match), byPlayer: make(map[string][]*domain.PlayerMatchStats)}
+	result := func(id, competition, home, away string, day, homeScore, awayScore int) *domain.Match {
+		match := &domain.Match{ID: id, HomeTeamID: home, AwayTeamID: away, Competition: competition, Date: utcDate(2024, 9, day), HomeScore: homeScore, AwayScore: awayScore, Status: "completed"}
+		matches.matches = append(matches.matches, match)
+		stats.matches[match.ID] = match
+		return match
+	}
+	played := func(playerID, teamID string, match *domain.Match, goals int) {
+		stats.byPlayer[playerID] = append(stats.byPlayer[playerID], &domain.PlayerMatchStats{PlayerID: playerID, MatchID: match.ID, TeamID: teamID, MinutesPlayed: 90, Goals: goals})
+	}
+
+	// table on the 15th: t5 and t1 top, t2 and t3 middle, t6 and t4 bottom, on the 22nd t2 is second and t1 third
+	m1 := result("m1", "League", "t1", "t2", 1, 2, 0)
+	result("m5", "League", "t2", "t4", 3, 2, 0)
+	result("m6", "League", "t5", "t6", 4, 1, 0)
+	m2 := result("m2", "League", "t3", "t1", 8, 1, 1)
+	m3 := result("m3", "League", "t1", "t5", 15, 0, 1)
+	m7 := result("m7", "Cup", "t1", "t4", 18, 1, 0)
+	m4 := result("m4", "League", "t6", "t1", 22, 0, 3)
+
+	played("p1", "t1", m1, 1)
+	played("p2", "t2", m1, 0)
+	played("p1", "t1", m2, 1)
+	played("p3", "t1", m2, 0)
+	played("p1", "t1", m3, 0)
+	played("p1", "", m7, 1)
+	played("p1", "t1", m4, 2)
+
+	return &analyticsService{
+		playerStatsRepo: stats,
+		playerRepo:      players,
+		matchRepo:       matches,
+		calendar:        newSeasonCalendar(nil),
+		clock:           domain.FixedClock(utcDate(2024, 10, 1)),
+	}
+}
+
+type splitSummary struct {
+	key     string
+	matches int
+	goals   float64 // goals per 90, rounded to 6 decimals
+}
+
+func roundSplit(value float64) float64 {
+	return math.Round(value*1e6) / 1e6
+}
+
+func summarizeSplits(buckets []*domain.SplitBucket) map[domain.SplitDimension][]splitSummary {
+	result := make(map[domain.SplitDimension][]splitSummary)
+	for _, bucket := range buckets {
+		result[bucket.Dimension] = append(result[bucket.Dimension], splitSummary{bucket.Key, bucket.Matches, roundSplit(bucket.Metrics.GoalsPerMinute * 90)})
+	}
+	return result
+}
+
+func TestGetPlayerSplits(t *testing.T) {
+	service := newSplitsFixture()
+
+	splits, err := service.GetPlayerSplits("p1", "all", nil)
+	assert.NoError(t, err)
+	summary := summarizeSplits(splits.Buckets)
+
+	assert.Equal(t, []splitSummary{{"home", 3, roundSplit(2.0 / 3)}, {"away", 2, 1.5}}, summary[domain.SplitVenue])
+	assert.Equal(t, []splitSummary{{"Cup", 1, 1}, {"League", 4, 1}}, summary[domain.SplitCompetition])
+	assert.Equal(t, []splitSummary{{"win", 3, roundSplit(4.0 / 3)}, {"draw", 1, 1}, {"loss", 1, 0}}, summary[domain.SplitResult])
+	// tiers come from the table on the match date, teams without a match before it have none
+	assert.Equal(t, []splitSummary{{"top", 1, 0}, {"bottom", 1, 2}, {"unknown", 3, 1}}, summary[domain.SplitOpponentTier])
+}
+
+func TestGetPlayerSplitsTiersIgnoreTimeRange(t *testing.T) {
+	service := newSplitsFixture()
+
+	// t6 is last of the season table on the 22nd, not middle of the two team table of the window
+	splits, err := service.GetPlayerSplits("p1", "last:1", []domain.SplitDimension{domain.SplitOpponentTier})
+	assert.NoError(t, err)
+	assert.Equal(t, []splitSummary{{"bottom", 1, 2}}, summarizeSplits(splits.Buckets)[domain.SplitOpponentTier])
+}
+
+func TestGetTeamSplits(t *testing.T) {
+	service := newSplitsFixture()
+
+	splits, err := service.GetTeamSplits("t1", "all", []domain.SplitDimension{domain.SplitVenue})
+	assert.NoError(t, err)
+	summary := summarizeSplits(splits.Buckets)
+
+	// p2 played against t1 at home, p3 for t1 away before moving
+	assert.Equal(t, []splitSummary{{"home", 3, roundSplit(2.0 / 3)}, {"away", 2, 1}}, summary[domain.SplitVenue])
+	assert.Len(t, splits.Buckets, 2)
+}
+
+func TestGetPlayerSplitsUnknownDimension(t *testing.T) {
+	service := newSplitsFixture()
+
+	_, err := service.GetPlayerSplits("p1", "all", []domain.SplitDimension{"weather"})
+	assert.EqualError(t, err, `unknown split dimension "weather"`)
+}
@@ -0,0 +1,98 @@
+package service
+
+import (
+	"football-analytics/internal/domain"
+	"sort"
+)
+
+// standingRow one team in a competition table
+type standingRow struct {
+	teamID       string
+	played       int
+	points       int
+	goalsFor     int
+	goalsAgainst int
+}
+
+// pointsPerGame points per match played, 0 for teams without matches
+func (r *standingRow) pointsPerGame() float64 {
+	if r.played == 0 {
+		return 0
+	}
+	return float64(r.points) / float64(r.played)
+}
+
+// buildStandings table of the completed matches, ordered by points per game, goal difference and goals scored
+func buildStandings(matches []*domain.Match) []*standingRow {
+	rows := make(map[string]*standingRow)
+	row := func(teamID string) *standingRow {
+		if r, ok := rows[teamID]; ok {
+			return r
+		}
+		rows[teamID] = &standingRow{teamID: teamID}
+		return rows[teamID]
+	}
+
+	for _, match := range matches {
+		if match.Status != "completed" {
+			continue
+		}
+		home, away := row(match.HomeTeamID), row(match.AwayTeamID)
+		home.played++
+		away.played++
+		home.goalsFor += match.HomeScore
+		home.goalsAgainst += match.AwayScore
+		away.goalsFor += match.AwayScore
+		away.goalsAgainst += match.HomeScore
+
+		switch {
+		case match.HomeScore > match.AwayScore:
+			home.points += 3
+		case match.HomeScore < match.AwayScore:
+			away.points += 3
+		default:
+			home.points++
+			away.points++
+		}
+	}
+
+	table := make([]*standingRow, 0, len(rows))
+	for _, r := range rows {
+		table = append(table, r)
+	}
+
+	sort.SliceStable(table, func(i, j int) bool {
+		a, b := table[i], table[j]
+		if a.pointsPerGame() != b.pointsPerGame() {
+			return a.pointsPerGame() > b.pointsPerGame()
+		}
+		if a.goalsFor-a.goalsAgainst != b.goalsFor-b.goalsAgainst {
+			return a.goalsFor-a.goalsAgainst > b.goalsFor-b.goalsAgainst
+		}
+		if a.goalsFor != b.goalsFor {
+			return a.goalsFor > b.goalsFor
+		}
+		return a.teamID < b.teamID
+	})
+
+	return table
+}
+
+// tableTiers top, middle or bottom third of every team of a table
+func tableTiers(table []*standingRow) map[string]string {
+	third := len(table) / 3
+
+	tiers := make(map[string]string)
+	for i, row := range table {
+		switch {
+		case i < third:
+			tiers[row.teamID] = "top"
+		case i >= len(table)-third:
+			tiers[row.teamID] = "bottom"
+		default:
+			tiers[row.teamID] = "middle"
+		}
+	}
+
+	return tiers
+}
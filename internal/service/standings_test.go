@@ -0,0 +1,46 @@
+package service
+
+import (
+	"football-analytics/internal/domain"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestBuildStandings(t *testing.T) {
+	matches := []*domain.Match{
+		{HomeTeamID: "a", AwayTeamID: "b", HomeScore: 1, AwayScore: 0, Status: "completed"},
+		{HomeTeamID: "c", AwayTeamID: "d", HomeScore: 3, AwayScore: 0, Status: "completed"},
+		{HomeTeamID: "b", AwayTeamID: "d", HomeScore: 2, AwayScore: 2, Status: "completed"},
+		{HomeTeamID: "e", AwayTeamID: "f", HomeScore: 1, AwayScore: 0, Status: "completed"},
+		{HomeTeamID: "a", AwayTeamID: "c", HomeScore: 5, AwayScore: 0, Status: "scheduled"},
+	}
+
+	var order []string
+	for _, row := range buildStandings(matches) {
+		order = append(order, row.teamID)
+	}
+	// a, c and e win their only match, ordered by goal difference then goals and id; scheduled matches do not count
+	assert.Equal(t, []string{"c", "a", "e", "b", "d", "f"}, order)
+
+	table := buildStandings(matches)
+	assert.Equal(t, 2, table[3].played)
+	assert.Equal(t, 1, table[3].points)
+	assert.InDelta(t, 0.5, table[3].pointsPerGame(), 1e-9)
+	assert.Zero(t, (&standingRow{}).pointsPerGame())
+}
+
+func TestTableTiers(t *testing.T) {
+	league := buildStandings([]*domain.Match{
+		{Competition: "League", HomeTeamID: "a", AwayTeamID: "b", HomeScore: 2, AwayScore: 0, Status: "completed"},
+		{Competition: "League", HomeTeamID: "c", AwayTeamID: "d", HomeScore: 1, AwayScore: 1, Status: "completed"},
+		{Competition: "League", HomeTeamID: "e", AwayTeamID: "f", HomeScore: 0, AwayScore: 1, Status: "completed"},
+	})
+	cup := buildStandings([]*domain.Match{
+		{Competition: "Cup", HomeTeamID: "a", AwayTeamID: "f", HomeScore: 0, AwayScore: 1, Status: "completed"},
+	})
+
+	assert.Equal(t, map[string]string{"a": "top", "f": "top", "c": "middle", "d": "middle", "b": "bottom", "e": "bottom"}, tableTiers(league))
+	// less than three teams have no top or bottom third
+	assert.Equal(t, map[string]string{"a": "middle", "f": "middle"}, tableTiers(cup))
+}
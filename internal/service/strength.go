@@ -0,0 +1,401 @@
+package service
+
+import (
+	"football-analytics/internal/domain"
+	"math"
+	"sync"
+	"time"
+)
+
+// minTableMatches teams with less matches in the season are rated average by the table strength source
+const minTableMatches = 3
+
+type tableStrengthSource struct {
+	matchRepo domain.MatchRepository
+	calendar  *seasonCalendar
+
+	mu sync.Mutex
+	// current tables of the latest calculation, nil before the first read
+	current *tableStrength
+}
+
+// NewTableStrengthSource create a StrengthSource rating teams by their points per game in the
+// season up to the rated date, relative to the average points per game of their competition,
+// the matches are read again for every calculation so new results are rated, single Strength
+// calls share the tables of the latest read
+func NewTableStrengthSource(matchRepo domain.MatchRepository, seasonRepo domain.SeasonRepository) domain.StrengthSource {
+	return &tableStrengthSource{matchRepo: matchRepo, calendar: newSeasonCalendar(seasonRepo)}
+}
+
+func (s *tableStrengthSource) Name() string {
+	return "table"
+}
+
+func (s *tableStrengthSource) Strength(teamID string, at time.Time) (float64, error) {
+	s.mu.Lock()
+	table := s.current
+	s.mu.Unlock()
+
+	if table == nil {
+		scoped, err := s.scoped()
+		if err != nil {
+			return 0, err
+		}
+		table = scoped.(*tableStrength)
+	}
+	return table.Strength(teamID, at)
+}
+
+// scoped read the matches and seasons once for the strengths of one calculation
+func (s *tableStrengthSource) scoped() (domain.StrengthSource, error) {
+	matches, err := s.matchRepo.List()
+	if err != nil {
+		return nil, err
+	}
+
+	calendar, err := s.calendar.memoized()
+	if err != nil {
+		return nil, err
+	}
+
+	table := &tableStrength{matches: matches, calendar: calendar, tables: make(map[tableKey]*strengthTable)}
+	s.mu.Lock()
+	s.current = table
+	s.mu.Unlock()
+	return table, nil
+}
+
+// scopedStrengthSource a StrengthSource that reads its data once for the strengths of a calculation
+type scopedStrengthSource interface {
+	scoped() (domain.StrengthSource, error)
+}
+
+// tableStrength table strengths over the matches read by one tableStrengthSource.scoped call
+type tableStrength struct {
+	matches  []*domain.Match
+	calendar *seasonCalendar
+
+	mu     sync.Mutex
+	tables map[tableKey]*strengthTable
+}
+
+// tableKey competition table up to a date
+type tableKey struct {
+	competition string
+	at          time.Time
+}
+
+// strengthTable standings of a competition up to a date with its average points per game,
+// 0 when no points were won yet, and the tier of every team
+type strengthTable struct {
+	rows    map[string]*standingRow
+	average float64
+	tiers   map[string]string
+}
+
+func (s *tableStrength) Name() string {
+	return "table"
+}
+
+func (s *tableStrength) Strength(teamID string, at time.Time) (float64, error) {
+	// the team's competition is the one of its latest match in the year before the rated date
+	var competition string
+	var latest time.Time
+	for _, match := range s.matches {
+		if match.Date.Before(at.AddDate(-1, 0, 0)) || !match.Date.Before(at) {
+			continue
+		}
+		if (match.HomeTeamID == teamID || match.AwayTeamID == teamID) && !match.Date.Before(latest) {
+			competition, latest = match.Competition, match.Date
+		}
+	}
+	table, err := s.table(competition, at)
+	if err != nil {
+		return 0, err
+	}
+
+	team, ok := table.rows[teamID]
+	if !ok || team.played < minTableMatches || table.average == 0 {
+		return 1, nil
+	}
+	return team.pointsPerGame() / table.average, nil
+}
+
+// table standings of the competition's season up to the date, built once for every competition and date
+func (s *tableStrength) table(competition string, at time.Time) (*strengthTable, error) {
+	key := tableKey{competition: competition, at: at}
+	s.mu.Lock()
+	table, ok := s.tables[key]
+	s.mu.Unlock()
+	if ok {
+		return table, nil
+	}
+
+	season, err := s.calendar.seasonAt(competition, at)
+	if err != nil {
+		return nil, err
+	}
+
+	var seasonMatches []*domain.Match
+	for _, match := range s.matches {
+		if match.Competition == competition && !match.Date.Before(season.StartDate) && match.Date.Before(at) {
+			seasonMatches = append(seasonMatches, match)
+		}
+	}
+
+	standings := buildStandings(seasonMatches)
+	table = &strengthTable{rows: make(map[string]*standingRow), tiers: tableTiers(standings)}
+	var totalPoints, totalPlayed int
+	for _, row := range standings {
+		totalPoints += row.points
+		totalPlayed += row.played
+		table.rows[row.teamID] = row
+	}
+	if totalPoints > 0 {
+		table.average = float64(totalPoints) / float64(totalPlayed)
+	}
+
+	s.mu.Lock()
+	s.tables[key] = table
+	s.mu.Unlock()
+	return table, nil
+}
+
+type modelStrengthSource struct {
+	model *domain.MatchModel
+}
+
+// NewModelStrengthSource create a StrengthSource rating teams by the attack and defence of a
+// fitted match model, teams unknown to the model are average
+func NewModelStrengthSource(model *domain.MatchModel) domain.StrengthSource {
+	return &modelStrengthSource{model: model}
+}
+
+func (s *modelStrengthSource) Name() string {
+	return "model:" + s.model.Version
+}
+
+func (s *modelStrengthSource) Strength(teamID string, at time.Time) (float64, error) {
+	strength, ok := s.model.Strengths[teamID]
+	if !ok || strength.Defence <= 0 {
+		return 1, nil
+	}
+	return math.Sqrt(strength.Attack / strength.Defence), nil
+}
+
+type ratingStrengthSource struct {
+	name    string
+	ratings map[string]float64
+	average float64
+}
+
+// NewRatingStrengthSource create a StrengthSource from fixed team ratings on a ratio scale,
+// ratings are divided by their average so an average team is 1.0 and unknown teams are average
+func NewRatingStrengthSource(name string, ratings map[string]float64) domain.StrengthSource {
+	var total float64
+	for _, rating := range ratings {
+		total += rating
+	}
+
+	average := 1.0
+	if len(ratings) > 0 && total > 0 {
+		average = total / float64(len(ratings))
+	}
+
+	return &ratingStrengthSource{name: name, ratings: ratings, average: average}
+}
+
+func (s *ratingStrengthSource) Name() string {
+	return s.name
+}
+
+func (s *ratingStrengthSource) Strength(teamID string, at time.Time) (float64, error) {
+	rating, ok := s.ratings[teamID]
+	if !ok {
+		return 1, nil
+	}
+	return rating / s.average, nil
+}
+
+// CalculateAdjustedPerformance calculate player performance with every match weighted by the opponent's strength
+func (s *analyticsService) CalculateAdjustedPerformance(playerID string, timeRange string, source domain.StrengthSource) (*domain.AdjustedPerformance, error) {
+	tr, err := domain.ParseTimeRange(timeRange)
+	if err != nil {
+		return nil, err
+	}
+
+	player, err := s.playerRepo.GetByID(playerID)
+	if err != nil {
+		return nil, err
+	}
+
+	appearances, _, err := s.playerRange(playerID, tr, s.clock.Now())
+	if err != nil {
+		return nil, err
+	}
+
+	teamOf := func(stat *domain.PlayerMatchStats) string {
+		if stat.TeamID != "" {
+			return stat.TeamID
+		}
+		return player.TeamID
+	}
+
+	return adjustAppearances(playerID, appearances, teamOf, source)
+}
+
+// CalculateAdjustedTeamPerformance calculate performance of the team's players with every match
+// weighted by the opponent's strength
+func (s *analyticsService) CalculateAdjustedTeamPerformance(teamID string, timeRange string, source domain.StrengthSource) (*domain.AdjustedPerformance, error) {
+	tr, err := domain.ParseTimeRange(timeRange)
+	if err != nil {
+		return nil, err
+	}
+
+	matches, err := s.teamRange(teamID, tr, s.clock.Now())
+	if err != nil {
+		return nil, err
+	}
+
+	players, err := s.playerRepo.List()
+	if err != nil {
+		return nil, err
+	}
+	playerTeams := make(map[string]string)
+	for _, player := range players {
+		playerTeams[player.ID] = player.TeamID
+	}
+
+	teamOf := func(stat *domain.PlayerMatchStats) string {
+		if stat.TeamID != "" {
+			return stat.TeamID
+		}
+		return playerTeams[stat.PlayerID]
+	}
+
+	var teamMatches []*domain.Match
+	for _, match := range matches {
+		if match.HomeTeamID == teamID || match.AwayTeamID == teamID {
+			teamMatches = append(teamMatches, match)
+		}
+	}
+
+	// the stats of every match of the team are fetched in one query
+	statsByMatch, err := loadStatsByMatch(s.playerStatsRepo, teamMatches)
+	if err != nil {
+		return nil, err
+	}
+
+	var appearances []appearance
+	for _, match := range teamMatches {
+		for _, stat := range statsByMatch[match.ID] {
+			if teamOf(stat) == teamID {
+				appearances = append(appearances, appearance{match: match, stats: stat})
+			}
+		}
+	}
+
+	return adjustAppearances(teamID, appearances, teamOf, source)
+}
+
+// adjustAppearances raw and opponent adjusted metrics of the appearances where the subject's side is known
+func adjustAppearances(
+	subjectID string,
+	appearances []appearance,
+	teamOf func(stat *domain.PlayerMatchStats) string,
+	source domain.StrengthSource,
+) (*domain.AdjustedPerformance, error) {
+	if scopedSource, ok := source.(scopedStrengthSource); ok {
+		var err error
+		if source, err = scopedSource.scoped(); err != nil {
+			return nil, err
+		}
+	}
+
+	var stats []*domain.PlayerMatchStats
+	var strengths []float64
+	scheduleMatches := make(map[string]float64)
+
+	for _, app := range appearances {
+		teamID := teamOf(app.stats)
+		var opponent string
+		switch teamID {
+		case app.match.HomeTeamID:
+			opponent = app.match.AwayTeamID
+		case app.match.AwayTeamID:
+			opponent = app.match.HomeTeamID
+		default:
+			continue
+		}
+
+		strength, err := source.Strength(opponent, app.match.Date)
+		if err != nil {
+			return nil, err
+		}
+
+		stats = append(stats, app.stats)
+		strengths = append(strengths, strength)
+		scheduleMatches[app.match.ID] = strength
+	}
+
+	result := &domain.AdjustedPerformance{
+		SubjectID: subjectID,
+		Source:    source.Name(),
+		Matches:   len(scheduleMatches),
+		Raw:       calculateMetricsFromStats(subjectID, stats),
+		Adjusted:  calculateAdjustedMetrics(subjectID, stats, strengths),
+	}
+
+	// every match counts once, however many players of a team played in it
+	for _, strength := range scheduleMatches {
+		result.StrengthOfSchedule += strength
+	}
+	if len(scheduleMatches) > 0 {
+		result.StrengthOfSchedule /= float64(len(scheduleMatches))
+	}
+
+	return result, nil
+}
+
+// calculateAdjustedMetrics calculate performance with the counting stats of every match (goals, assists
+// and defensive actions) multiplied by the opponent strength of that match, ratios like pass and shot
+// accuracy are not adjusted so they stay within their range, neither are minutes and distance covered
+func calculateAdjustedMetrics(subjectID string, stats []*domain.PlayerMatchStats, strengths []float64) *domain.PerformanceMetrics {
+	metrics := &domain.PerformanceMetrics{
+		PlayerID: subjectID,
+	}
+
+	if len(stats) == 0 {
+		return metrics
+	}
+
+	var totalMinutes, totalShots, totalShotsOnTarget int
+	var goals, assists, defensiveActions, totalPassAccuracy, totalDistance float64
+
+	for i, stat := range stats {
+		strength := strengths[i]
+		totalMinutes += stat.MinutesPlayed
+		totalShots += stat.Shots
+		totalShotsOnTarget += stat.ShotsOnTarget
+		totalPassAccuracy += stat.PassAccuracy
+		totalDistance += stat.DistanceCovered
+		goals += float64(stat.Goals) * strength
+		assists += float64(stat.Assists) * strength
+		defensiveActions += float64(stat.Tackles+stat.Interceptions) * strength
+	}
+
+	matchCount := float64(len(stats))
+	if totalMinutes > 0 {
+		metrics.GoalsPerMinute = goals / float64(totalMinutes)
+		metrics.AssistsPerMinute = assists / float64(totalMinutes)
+	}
+	if totalShots > 0 {
+		metrics.ShotAccuracy = float64(totalShotsOnTarget) / float64(totalShots)
+	}
+	metrics.PassAccuracy = totalPassAccuracy / matchCount
+	metrics.DefensiveEfficiency = defensiveActions / matchCount
+	metrics.Stamina = totalDistance / matchCount
+	metrics.OverallRating = overallRating(metrics)
+
+	return metrics
+}
@@ -0,0 +1,157 @@
+package service
+
+import (
+	"football-analytics/internal/domain"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+// listingMatchRepository count the reads of every match
+type listingMatchRepository struct {
+	memoryMatchRepository
+	lists int
+}
+
+func (r *listingMatchRepository) List() ([]*domain.Match, error) {
+	r.lists++
+	return r.memoryMatchRepository.List()
+}
+
+// newStrengthFixture September 2024 league results, t1 wins all three of its matches and t2 draws t3
+func newStrengthFixture() *listingMatchRepository {
+	league := func(id, home, away string, day, homeScore, awayScore int) *domain.Match {
+		return &domain.Match{ID: id, HomeTeamID: home, AwayTeamID: away, Competition: "League", Date: utcDate(2024, 9, day), HomeScore: homeScore, AwayScore: awayScore, Status: "completed"}
+	}
+	return &listingMatchRepository{memoryMatchRepository: memoryMatchRepository{matches: []*domain.Match{
+		league("m1", "t1", "t2", 1, 3, 0),
+		league("m2", "t1", "t3", 8, 2, 0),
+		league("m3", "t2", "t3", 8, 1, 1),
+		league("m4", "t1", "t4", 15, 1, 0),
+	}}}
+}
+
+func TestTableStrengthSource(t *testing.T) {
+	matches := newStrengthFixture()
+	source := NewTableStrengthSource(matches, nil)
+
+	// 9 points from 3 matches against 11 points from 8 team matches
+	strength, err := source.Strength("t1", utcDate(2024, 10, 1))
+	assert.NoError(t, err)
+	assert.InDelta(t, 3/(11.0/8), strength, 1e-9)
+
+	// t2 has played less than minTableMatches
+	strength, err = source.Strength("t2", utcDate(2024, 10, 1))
+	assert.NoError(t, err)
+	assert.Equal(t, 1.0, strength)
+
+	// single calls share the matches read by the first one
+	assert.Equal(t, 1, matches.lists)
+
+	// a new result is rated from the next calculation
+	matches.matches = append(matches.matches, &domain.Match{ID: "m5", HomeTeamID: "t2", AwayTeamID: "t4", Competition: "League", Date: utcDate(2024, 9, 22), HomeScore: 2, Status: "completed"})
+	strength, err = source.Strength("t2", utcDate(2024, 10, 1))
+	assert.NoError(t, err)
+	assert.Equal(t, 1.0, strength)
+
+	scoped, err := source.(scopedStrengthSource).scoped()
+	assert.NoError(t, err)
+	strength, err = scoped.Strength("t2", utcDate(2024, 10, 1))
+	assert.NoError(t, err)
+	assert.InDelta(t, (4.0/3)/(14.0/10), strength, 1e-9)
+	strength, err = source.Strength("t2", utcDate(2024, 10, 1))
+	assert.NoError(t, err)
+	assert.InDelta(t, (4.0/3)/(14.0/10), strength, 1e-9)
+	assert.Equal(t, 2, matches.lists)
+}
+
+func TestTableStrengthBuildsTableOncePerDate(t *testing.T) {
+	scoped, err := NewTableStrengthSource(newStrengthFixture(), nil).(scopedStrengthSource).scoped()
+	if !assert.NoError(t, err) {
+		return
+	}
+	table := scoped.(*tableStrength)
+
+	for _, teamID := range []string{"t1", "t2", "t3", "t4"} {
+		_, err := table.Strength(teamID, utcDate(2024, 10, 1))
+		assert.NoError(t, err)
+	}
+	_, err = table.Strength("t1", utcDate(2024, 9, 10))
+	assert.NoError(t, err)
+
+	assert.Len(t, table.tables, 2)
+}
+
+func TestAdjustAppearancesReadsTableOnce(t *testing.T) {
+	matches := newStrengthFixture()
+	source := NewTableStrengthSource(matches, nil)
+
+	var appearances []appearance
+	for _, match := range matches.matches[:3] {
+		appearances = append(appearances, appearance{match: match, stats: &domain.PlayerMatchStats{PlayerID: "p1", TeamID: match.HomeTeamID, MinutesPlayed: 90, Goals: 1}})
+	}
+	teamOf := func(stat *domain.PlayerMatchStats) string { return stat.TeamID }
+
+	result, err := adjustAppearances("p1", appearances, teamOf, source)
+	assert.NoError(t, err)
+	assert.Equal(t, "table", result.Source)
+	assert.Equal(t, 3, result.Matches)
+	assert.Equal(t, 1, matches.lists)
+
+	_, err = adjustAppearances("p1", appearances, teamOf, source)
+	assert.NoError(t, err)
+	assert.Equal(t, 2, matches.lists)
+}
+
+func TestCalculateAdjustedMetrics(t *testing.T) {
+	stats := []*domain.PlayerMatchStats{
+		{MinutesPlayed: 90, Goals: 1, Tackles: 2, Interceptions: 2, Shots: 2, ShotsOnTarget: 2, PassAccuracy: 95, DistanceCovered: 10},
+		{MinutesPlayed: 90, Goals: 1, Tackles: 1, Interceptions: 1, Shots: 2, ShotsOnTarget: 1, PassAccuracy: 85, DistanceCovered: 12},
+	}
+
+	raw := calculateMetricsFromStats("p1", stats)
+	adjusted := calculateAdjustedMetrics("p1", stats, []float64{1.5, 0.5})
+
+	// counting stats are weighted by the opponent strength
+	assert.InDelta(t, 2.0/180, adjusted.GoalsPerMinute, 1e-9)
+	assert.InDelta(t, (4*1.5+2*0.5)/2, adjusted.DefensiveEfficiency, 1e-9)
+
+	// ratios stay as they were, even against strong opponents
+	assert.Equal(t, raw.PassAccuracy, adjusted.PassAccuracy)
+	assert.Equal(t, raw.ShotAccuracy, adjusted.ShotAccuracy)
+	assert.Equal(t, raw.Stamina, adjusted.Stamina)
+
+	strong := calculateAdjustedMetrics("p1", stats[:1], []float64{2})
+	assert.Equal(t, 95.0, strong.PassAccuracy)
+	assert.Equal(t, 1.0, strong.ShotAccuracy)
+}
+
+func TestModelAndRatingStrengthSources(t *testing.T) {
+	model := NewModelStrengthSource(&domain.MatchModel{Version: "v1", Strengths: map[string]*domain.TeamStrength{
+		"t1": {TeamID: "t1", Attack: 1.8, Defence: 0.8},
+	}})
+	assert.Equal(t, "model:v1", model.Name())
+	strength, _ := model.Strength("t1", utcDate(2024, 10, 1))
+	assert.InDelta(t, 1.5, strength, 1e-9)
+	strength, _ = model.Strength("t9", utcDate(2024, 10, 1))
+	assert.Equal(t, 1.0, strength)
+
+	ratings := NewRatingStrengthSource("elo", map[string]float64{"t1": 1800, "t2": 1200})
+	strength, _ = ratings.Strength("t1", utcDate(2024, 10, 1))
+	assert.InDelta(t, 1.2, strength, 1e-9)
+	strength, _ = ratings.Strength("t9", utcDate(2024, 10, 1))
+	assert.Equal(t, 1.0, strength)
+}
+
+func TestCalculateAdjustedTeamPerformance(t *testing.T) {
+	service := newSplitsFixture()
+	stats := &batchStatsRepository{memoryStatsRepository: service.playerStatsRepo.(*memoryStatsRepository)}
+	service.playerStatsRepo = stats
+
+	result, err := service.CalculateAdjustedTeamPerformance("t1", "all", NewRatingStrengthSource("flat", nil))
+	assert.NoError(t, err)
+	assert.Equal(t, 1, stats.batches)
+	assert.Equal(t, 5, result.Matches)
+	assert.Equal(t, 1.0, result.StrengthOfSchedule)
+	assert.Equal(t, result.Raw.GoalsPerMinute, result.Adjusted.GoalsPerMinute)
+}
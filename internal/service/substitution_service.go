@@ -0,0 +1,446 @@
+package service
+
+import (
+	"errors"
+	"fmt"
+	"football-analytics/internal/domain"
+	"sort"
+	"time"
+
+	"github.com/google/uuid"
+)
+
+// matchMinutes length of a match, event minutes past it count as its last minute
+const matchMinutes = 90
+
+// SubstitutionService is interface for recording match events and measuring the impact of substitutions
+type SubstitutionService interface {
+	RecordEvent(event *domain.MatchEvent) (*domain.MatchEvent, error)
+	DeleteEvent(id string) error
+	GetTeamSubstitutionReport(teamID string, timeRange string) (*domain.TeamSubstitutionReport, error)
+	GetPlayerSubstitutionReport(playerID string, timeRange string) (*domain.PlayerSubstitutionReport, error)
+}
+
+type substitutionService struct {
+	eventRepo       domain.MatchEventRepository
+	playerStatsRepo domain.PlayerMatchStatsRepository
+	matchRepo       domain.MatchRepository
+	analytics       *analyticsService
+}
+
+// NewSubstitutionService create instance of SubstitutionService, a nil clock is the system clock
+func NewSubstitutionService(
+	eventRepo domain.MatchEventRepository,
+	playerStatsRepo domain.PlayerMatchStatsRepository,
+	playerRepo domain.PlayerRepository,
+	matchRepo domain.MatchRepository,
+	seasonRepo domain.SeasonRepository,
+	clock domain.Clock,
+) SubstitutionService {
+	if clock == nil {
+		clock = domain.SystemClock{}
+	}
+
+	return &substitutionService{
+		eventRepo:       eventRepo,
+		playerStatsRepo: playerStatsRepo,
+		matchRepo:       matchRepo,
+		analytics: &analyticsService{
+			playerStatsRepo: playerStatsRepo,
+			playerRepo:      playerRepo,
+			matchRepo:       matchRepo,
+			calendar:        newSeasonCalendar(seasonRepo),
+			clock:           clock,
+		},
+	}
+}
+
+// RecordEvent validate and store a match event, linked to the stats row of its player in the match
+func (s *substitutionService) RecordEvent(event *domain.MatchEvent) (*domain.MatchEvent, error) {
+	switch event.Type {
+	case domain.MatchEventGoal, domain.MatchEventSubstitution:
+	default:
+		return nil, fmt.Errorf("unknown match event type %q", event.Type)
+	}
+	if event.Minute < 0 {
+		return nil, fmt.Errorf("invalid event minute %d", event.Minute)
+	}
+	if event.PlayerID == "" {
+		return nil, errors.New("match event needs a player")
+	}
+	if event.Type == domain.MatchEventSubstitution && (event.RelatedPlayerID == "" || event.RelatedPlayerID == event.PlayerID) {
+		return nil, errors.New("substitution needs the player going off")
+	}
+
+	match, err := s.matchRepo.GetByID(event.MatchID)
+	if err != nil {
+		return nil, err
+	}
+	if event.TeamID != match.HomeTeamID && event.TeamID != match.AwayTeamID {
+		return nil, fmt.Errorf("team %s did not play match %s", event.TeamID, match.ID)
+	}
+
+	stats, err := s.playerStatsRepo.ListByMatchID(match.ID)
+	if err != nil {
+		return nil, err
+	}
+	for _, stat := range stats {
+		if stat.PlayerID == event.PlayerID {
+			event.StatsID = stat.ID
+		}
+	}
+
+	event.ID = uuid.New().String()
+	event.CreatedAt = time.Now()
+	event.UpdatedAt = event.CreatedAt
+
+	if err := s.eventRepo.Create(event); err != nil {
+		return nil, err
+	}
+
+	return event, nil
+}
+
+func (s *substitutionService) DeleteEvent(id string) error {
+	return s.eventRepo.Delete(id)
+}
+
+// GetTeamSubstitutionReport get the impact of a team's substitutions over its completed matches
+// with recorded events in the time range
+func (s *substitutionService) GetTeamSubstitutionReport(teamID string, timeRange string) (*domain.TeamSubstitutionReport, error) {
+	tr, err := domain.ParseTimeRange(timeRange)
+	if err != nil {
+		return nil, err
+	}
+
+	matches, err := s.analytics.teamRange(teamID, tr, s.analytics.clock.Now())
+	if err != nil {
+		return nil, err
+	}
+
+	var played []*domain.Match
+	for _, match := range matches {
+		if playedBy(match, teamID) {
+			played = append(played, match)
+		}
+	}
+
+	eventsByMatch, err := s.events(played)
+	if err != nil {
+		return nil, err
+	}
+
+	report := teamSubstitutionImpact(teamID, played, eventsByMatch)
+	report.TimeRange = timeRange
+
+	return report, nil
+}
+
+// GetPlayerSubstitutionReport compare the output of a player coming off the bench with their output as a
+// starter, appearances in matches without recorded events are left out
+func (s *substitutionService) GetPlayerSubstitutionReport(playerID string, timeRange string) (*domain.PlayerSubstitutionReport, error) {
+	tr, err := domain.ParseTimeRange(timeRange)
+	if err != nil {
+		return nil, err
+	}
+
+	if _, err := s.analytics.playerRepo.GetByID(playerID); err != nil {
+		return nil, err
+	}
+
+	appearances, _, err := s.analytics.playerRange(playerID, tr, s.analytics.clock.Now())
+	if err != nil {
+		return nil, err
+	}
+
+	teamOf, _, err := s.analytics.statsTeams()
+	if err != nil {
+		return nil, err
+	}
+
+	matches := make([]*domain.Match, 0, len(appearances))
+	for _, app := range appearances {
+		matches = append(matches, app.match)
+	}
+	eventsByMatch, err := s.events(matches)
+	if err != nil {
+		return nil, err
+	}
+
+	report := playerSubstitutionImpact(playerID, appearances, eventsByMatch, teamOf)
+	report.TimeRange = timeRange
+
+	return report, nil
+}
+
+// events events of the matches by match id, ordered by minute
+func (s *substitutionService) events(matches []*domain.Match) (map[string][]*domain.MatchEvent, error) {
+	eventsByMatch := make(map[string][]*domain.MatchEvent)
+	if len(matches) == 0 {
+		return eventsByMatch, nil
+	}
+
+	ids := make([]string, 0, len(matches))
+	for _, match := range matches {
+		ids = append(ids, match.ID)
+	}
+
+	events, err := s.eventRepo.ListByMatchIDs(ids)
+	if err != nil {
+		return nil, err
+	}
+	for _, event := range events {
+		eventsByMatch[event.MatchID] = append(eventsByMatch[event.MatchID], event)
+	}
+
+	return eventsByMatch, nil
+}
+
+// teamSubstitutionImpact substitution report of a team over its matches (ordered by date), matches
+// without events are left out and the ones whose goal events do not add up to the score are only
+// counted, coaches missing from the match are left out of the coach timings
+func teamSubstitutionImpact(teamID string, matches []*domain.Match, eventsByMatch map[string][]*domain.MatchEvent) *domain.TeamSubstitutionReport {
+	report := &domain.TeamSubstitutionReport{TeamID: teamID}
+
+	type coachTiming struct {
+		matches int
+		minutes []int
+		firsts  []int
+	}
+	coaches := make(map[string]*coachTiming)
+	var coachNames []string
+
+	for _, match := range matches {
+		events := eventsByMatch[match.ID]
+		if len(events) == 0 {
+			continue
+		}
+		if !goalEventsMatchScore(match, events) {
+			report.IncompleteMatches++
+			continue
+		}
+		report.Matches++
+
+		coach := match.HomeCoach
+		if match.AwayTeamID == teamID {
+			coach = match.AwayCoach
+		}
+		// a timing of a missing coach is discarded
+		timing, ok := coaches[coach]
+		if !ok {
+			timing = &coachTiming{}
+			coaches[coach] = timing
+			if coach != "" {
+				coachNames = append(coachNames, coach)
+			}
+		}
+		timing.matches++
+
+		var subs, goals []*domain.MatchEvent
+		for _, event := range events {
+			switch {
+			case event.Type == domain.MatchEventSubstitution && event.TeamID == teamID:
+				subs = append(subs, event)
+			case event.Type == domain.MatchEventGoal:
+				goals = append(goals, event)
+			}
+		}
+
+		// goals after a minute, for and against the team
+		goalsAfter := func(minute int) (int, int) {
+			var goalsFor, goalsAgainst int
+			for _, goal := range goals {
+				if eventMinute(goal) <= minute {
+					continue
+				}
+				if goal.TeamID == teamID {
+					goalsFor++
+				} else {
+					goalsAgainst++
+				}
+			}
+			return goalsFor, goalsAgainst
+		}
+
+		first := matchMinutes
+		if len(subs) > 0 {
+			first = eventMinute(subs[0])
+			timing.firsts = append(timing.firsts, first)
+		}
+		totalFor, totalAgainst := goalsAfter(-1)
+		afterFor, afterAgainst := goalsAfter(first)
+		report.GoalsForBefore += totalFor - afterFor
+		report.GoalsAgainstBefore += totalAgainst - afterAgainst
+		report.GoalsForAfter += afterFor
+		report.GoalsAgainstAfter += afterAgainst
+		report.MinutesBefore += first
+		report.MinutesAfter += matchMinutes - first
+
+		cameOn := make(map[string]bool)
+		for _, sub := range subs {
+			cameOn[sub.PlayerID] = true
+			timing.minutes = append(timing.minutes, eventMinute(sub))
+
+			impact := &domain.SubstitutionImpact{
+				MatchID:     match.ID,
+				Date:        match.Date,
+				TeamID:      teamID,
+				Coach:       coach,
+				Minute:      sub.Minute,
+				PlayerOnID:  sub.PlayerID,
+				PlayerOffID: sub.RelatedPlayerID,
+			}
+			impact.GoalsForAfter, impact.GoalsAgainstAfter = goalsAfter(eventMinute(sub))
+			report.Details = append(report.Details, impact)
+		}
+		report.Substitutions += len(subs)
+
+		for _, goal := range goals {
+			if goal.TeamID != teamID {
+				continue
+			}
+			if cameOn[goal.PlayerID] {
+				report.SubGoals++
+			}
+			if cameOn[goal.RelatedPlayerID] {
+				report.SubAssists++
+			}
+		}
+	}
+
+	if report.MinutesBefore > 0 {
+		report.GoalDiffPer90Before = float64(report.GoalsForBefore-report.GoalsAgainstBefore) / float64(report.MinutesBefore) * matchMinutes
+	}
+	if report.MinutesAfter > 0 {
+		report.GoalDiffPer90After = float64(report.GoalsForAfter-report.GoalsAgainstAfter) / float64(report.MinutesAfter) * matchMinutes
+	}
+
+	for _, name := range coachNames {
+		timing := coaches[name]
+		report.Coaches = append(report.Coaches, &domain.CoachSubstitutionTiming{
+			Coach:              name,
+			Matches:            timing.matches,
+			Substitutions:      len(timing.minutes),
+			AverageMinute:      meanMinute(timing.minutes),
+			MedianMinute:       medianMinute(timing.minutes),
+			AverageFirstMinute: meanMinute(timing.firsts),
+		})
+	}
+
+	return report
+}
+
+// goalEventsMatchScore tell if the goal events of a match add up to its final score
+func goalEventsMatchScore(match *domain.Match, events []*domain.MatchEvent) bool {
+	var home, away int
+	for _, event := range events {
+		if event.Type != domain.MatchEventGoal {
+			continue
+		}
+		switch event.TeamID {
+		case match.HomeTeamID:
+			home++
+		case match.AwayTeamID:
+			away++
+		}
+	}
+	return home == match.HomeScore && away == match.AwayScore
+}
+
+// playerSubstitutionImpact substitution report of a player over their appearances, appearances in
+// matches without events are left out
+func playerSubstitutionImpact(
+	playerID string,
+	appearances []appearance,
+	eventsByMatch map[string][]*domain.MatchEvent,
+	teamOf func(stat *domain.PlayerMatchStats) string,
+) *domain.PlayerSubstitutionReport {
+	report := &domain.PlayerSubstitutionReport{PlayerID: playerID}
+
+	var starterStats, subStats []*domain.PlayerMatchStats
+	var minutesOn []int
+	for _, app := range appearances {
+		events := eventsByMatch[app.match.ID]
+		if len(events) == 0 {
+			continue
+		}
+
+		var on *domain.MatchEvent
+		for _, event := range events {
+			if event.Type != domain.MatchEventSubstitution {
+				continue
+			}
+			if event.PlayerID == playerID && on == nil {
+				on = event
+			}
+			if event.RelatedPlayerID == playerID {
+				report.SubbedOff++
+			}
+		}
+
+		if on == nil {
+			report.Starts++
+			starterStats = append(starterStats, app.stats)
+			continue
+		}
+
+		report.SubAppearances++
+		subStats = append(subStats, app.stats)
+		minutesOn = append(minutesOn, eventMinute(on))
+
+		teamID := teamOf(app.stats)
+		if teamID == "" {
+			teamID = on.TeamID
+		}
+		for _, event := range events {
+			if event.Type != domain.MatchEventGoal || eventMinute(event) <= eventMinute(on) {
+				continue
+			}
+			if event.TeamID == teamID {
+				report.GoalsForAfterOn++
+			} else {
+				report.GoalsAgainstAfterOn++
+			}
+		}
+	}
+
+	starter := buildProfile(playerID, starterStats)
+	sub := buildProfile(playerID, subStats)
+	report.StarterMinutes, report.SubMinutes = starter.minutes, sub.minutes
+	report.StarterPer90, report.SubPer90 = profileMap(starter), profileMap(sub)
+	report.AverageMinuteOn = meanMinute(minutesOn)
+
+	return report
+}
+
+// eventMinute minute of an event within the match length
+func eventMinute(event *domain.MatchEvent) int {
+	if event.Minute > matchMinutes {
+		return matchMinutes
+	}
+	return event.Minute
+}
+
+func meanMinute(minutes []int) float64 {
+	if len(minutes) == 0 {
+		return 0
+	}
+	var total int
+	for _, minute := range minutes {
+		total += minute
+	}
+	return float64(total) / float64(len(minutes))
+}
+
+func medianMinute(minutes []int) float64 {
+	if len(minutes) == 0 {
+		return 0
+	}
+	sorted := append([]int(nil), minutes...)
+	sort.Ints(sorted)
+	middle := len(sorted) / 2
+	if len(sorted)%2 == 0 {
+		return float64(sorted[middle-1]+sorted[middle]) / 2
+	}
+	return float64(sorted[middle])
+}
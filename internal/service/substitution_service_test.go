@@ -0,0 +1,97 @@
+package service
+
+import (
+	"football-analytics/internal/domain"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestTeamSubstitutionImpact(t *testing.T) {
+	matches := []*domain.Match{
+		{ID: "m1", HomeTeamID: "t1", AwayTeamID: "t2", HomeCoach: "Coach A", Date: utcDate(2024, time.August, 10), HomeScore: 2},
+		{ID: "m2", HomeTeamID: "t3", AwayTeamID: "t1", AwayCoach: "Coach A", Date: utcDate(2024, time.August, 17), HomeScore: 2},
+		{ID: "m3", HomeTeamID: "t1", AwayTeamID: "t4", Date: utcDate(2024, time.August, 24)}, // no events
+		{ID: "m4", HomeTeamID: "t5", AwayTeamID: "t1", AwayCoach: "Coach A", Date: utcDate(2024, time.August, 31), HomeScore: 1},
+		{ID: "m5", HomeTeamID: "t1", AwayTeamID: "t6", Date: utcDate(2024, time.September, 7)},
+	}
+	events := map[string][]*domain.MatchEvent{
+		"m1": {
+			{MatchID: "m1", TeamID: "t1", Type: domain.MatchEventGoal, Minute: 20, PlayerID: "p1"},
+			{MatchID: "m1", TeamID: "t1", Type: domain.MatchEventSubstitution, Minute: 60, PlayerID: "p12", RelatedPlayerID: "p1"},
+			{MatchID: "m1", TeamID: "t2", Type: domain.MatchEventSubstitution, Minute: 65, PlayerID: "p30", RelatedPlayerID: "p31"},
+			{MatchID: "m1", TeamID: "t1", Type: domain.MatchEventGoal, Minute: 75, PlayerID: "p12", RelatedPlayerID: "p2"},
+			{MatchID: "m1", TeamID: "t1", Type: domain.MatchEventSubstitution, Minute: 80, PlayerID: "p13", RelatedPlayerID: "p2"},
+		},
+		"m2": {
+			{MatchID: "m2", TeamID: "t3", Type: domain.MatchEventGoal, Minute: 50, PlayerID: "p40"},
+			{MatchID: "m2", TeamID: "t1", Type: domain.MatchEventSubstitution, Minute: 70, PlayerID: "p14", RelatedPlayerID: "p3"},
+			{MatchID: "m2", TeamID: "t3", Type: domain.MatchEventGoal, Minute: 93, PlayerID: "p40"},
+		},
+		// the goal of m4 was not recorded
+		"m4": {
+			{MatchID: "m4", TeamID: "t1", Type: domain.MatchEventSubstitution, Minute: 46, PlayerID: "p15", RelatedPlayerID: "p4"},
+		},
+		// m5 has no coach
+		"m5": {
+			{MatchID: "m5", TeamID: "t1", Type: domain.MatchEventSubstitution, Minute: 90, PlayerID: "p16", RelatedPlayerID: "p5"},
+		},
+	}
+
+	report := teamSubstitutionImpact("t1", matches, events)
+
+	assert.Equal(t, 3, report.Matches)
+	assert.Equal(t, 1, report.IncompleteMatches)
+	assert.Equal(t, 4, report.Substitutions)
+	assert.Equal(t, 1, report.GoalsForBefore)
+	assert.Equal(t, 1, report.GoalsAgainstBefore)
+	assert.Equal(t, 1, report.GoalsForAfter)
+	assert.Equal(t, 1, report.GoalsAgainstAfter)
+	assert.Equal(t, 220, report.MinutesBefore)
+	assert.Equal(t, 50, report.MinutesAfter)
+	assert.Equal(t, 1, report.SubGoals)
+	assert.Equal(t, 0, report.SubAssists)
+
+	if assert.Len(t, report.Details, 4) {
+		assert.Equal(t, 1, report.Details[0].GoalsForAfter)
+		assert.Equal(t, 0, report.Details[1].GoalsForAfter)
+		assert.Equal(t, 1, report.Details[2].GoalsAgainstAfter)
+	}
+	if assert.Len(t, report.Coaches, 1) {
+		coach := report.Coaches[0]
+		assert.Equal(t, "Coach A", coach.Coach)
+		assert.Equal(t, 2, coach.Matches)
+		assert.InDelta(t, 70, coach.AverageMinute, 1e-9)
+		assert.InDelta(t, 70, coach.MedianMinute, 1e-9)
+		assert.InDelta(t, 65, coach.AverageFirstMinute, 1e-9)
+	}
+}
+
+func TestPlayerSubstitutionImpact(t *testing.T) {
+	teamOf := func(stat *domain.PlayerMatchStats) string { return stat.TeamID }
+	appearances := []appearance{
+		{match: &domain.Match{ID: "m1"}, stats: &domain.PlayerMatchStats{PlayerID: "p1", TeamID: "t1", MinutesPlayed: 90, Goals: 1}},
+		{match: &domain.Match{ID: "m2"}, stats: &domain.PlayerMatchStats{PlayerID: "p1", TeamID: "t1", MinutesPlayed: 30, Goals: 1}},
+		{match: &domain.Match{ID: "m3"}, stats: &domain.PlayerMatchStats{PlayerID: "p1", TeamID: "t1", MinutesPlayed: 90}},
+	}
+	events := map[string][]*domain.MatchEvent{
+		"m1": {{MatchID: "m1", TeamID: "t1", Type: domain.MatchEventSubstitution, Minute: 85, PlayerID: "p9", RelatedPlayerID: "p1"}},
+		"m2": {
+			{MatchID: "m2", TeamID: "t1", Type: domain.MatchEventSubstitution, Minute: 60, PlayerID: "p1", RelatedPlayerID: "p9"},
+			{MatchID: "m2", TeamID: "t1", Type: domain.MatchEventGoal, Minute: 70, PlayerID: "p1"},
+			{MatchID: "m2", TeamID: "t2", Type: domain.MatchEventGoal, Minute: 80, PlayerID: "p50"},
+		},
+	}
+
+	report := playerSubstitutionImpact("p1", appearances, events, teamOf)
+
+	assert.Equal(t, 1, report.Starts)
+	assert.Equal(t, 1, report.SubAppearances)
+	assert.Equal(t, 1, report.SubbedOff)
+	assert.InDelta(t, 60, report.AverageMinuteOn, 1e-9)
+	assert.Equal(t, 1, report.GoalsForAfterOn)
+	assert.Equal(t, 1, report.GoalsAgainstAfterOn)
+	assert.InDelta(t, 1, report.StarterPer90["goals"], 1e-9)
+	assert.InDelta(t, 3, report.SubPer90["goals"], 1e-9)
+}
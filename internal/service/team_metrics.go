@@ -0,0 +1,353 @@
+package service
+
+import (
+	"fmt"
+	"football-analytics/internal/domain"
+)
+
+// CalculateTeamPerformance calculate the performance of a team over its completed matches in a time range
+func (s *analyticsService) CalculateTeamPerformance(teamID string, timeRange string) (*domain.TeamMetrics, error) {
+	results, err := s.GetTeamMatchMetrics(teamID, timeRange)
+	if err != nil {
+		return nil, err
+	}
+
+	return aggregateTeamMetrics(teamID, results), nil
+}
+
+// GetTeamMatchMetrics get the output of a team in every completed match of a time range, ordered by date
+func (s *analyticsService) GetTeamMatchMetrics(teamID string, timeRange string) ([]*domain.TeamMatchMetrics, error) {
+	tr, err := domain.ParseTimeRange(timeRange)
+	if err != nil {
+		return nil, err
+	}
+
+	_, results, err := s.teamResults(teamID, tr)
+	return results, err
+}
+
+// GetTeamProgressSeries get team progress over time, grouped by the window in opts, both dates are
+// included, rolling and block windows count matches instead of appearances
+func (s *analyticsService) GetTeamProgressSeries(teamID string, startDateStr, endDateStr string, opts domain.ProgressOptions) ([]*domain.TeamProgressPoint, error) {
+	tr, err := domain.DateRange(startDateStr, endDateStr)
+	if err != nil {
+		return nil, err
+	}
+
+	matches, results, err := s.teamResults(teamID, tr)
+	if err != nil {
+		return nil, err
+	}
+
+	return buildTeamProgressSeries(teamID, matches, results, opts, s.calendar)
+}
+
+// CompareTeamPerformance compare the performance of teams in the same time range
+func (s *analyticsService) CompareTeamPerformance(teamIDs []string, timeRange string) (map[string]*domain.TeamMetrics, error) {
+	result := make(map[string]*domain.TeamMetrics)
+	for _, teamID := range teamIDs {
+		metrics, err := s.CalculateTeamPerformance(teamID, timeRange)
+		if err != nil {
+			return nil, err
+		}
+		result[teamID] = metrics
+	}
+
+	return result, nil
+}
+
+// teamResults completed matches of the team in the time range with its output in each, ordered by date
+func (s *analyticsService) teamResults(teamID string, tr domain.TimeRange) ([]*domain.Match, []*domain.TeamMatchMetrics, error) {
+	matches, err := s.teamRange(teamID, tr, s.clock.Now())
+	if err != nil {
+		return nil, nil, err
+	}
+
+	teamOf, _, err := s.statsTeams()
+	if err != nil {
+		return nil, nil, err
+	}
+
+	var played []*domain.Match
+	for _, match := range matches {
+		if match.Status == "completed" && (match.HomeTeamID == teamID || match.AwayTeamID == teamID) {
+			played = append(played, match)
+		}
+	}
+
+	// the stats of every match of the team are fetched in one query
+	statsByMatch, err := loadStatsByMatch(s.playerStatsRepo, played)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	results := make([]*domain.TeamMatchMetrics, 0, len(played))
+	for _, match := range played {
+		results = append(results, teamMatchMetrics(teamID, match, statsByMatch[match.ID], teamOf))
+	}
+
+	return played, results, nil
+}
+
+// statsTeams resolver of the team of a stats row, the row's own team or else the current team of
+// its player, with the players it was built from
+func (s *analyticsService) statsTeams() (func(stat *domain.PlayerMatchStats) string, []*domain.Player, error) {
+	players, err := s.playerRepo.List()
+	if err != nil {
+		return nil, nil, err
+	}
+	playerTeams := make(map[string]string)
+	for _, player := range players {
+		playerTeams[player.ID] = player.TeamID
+	}
+
+	return func(stat *domain.PlayerMatchStats) string {
+		if stat.TeamID != "" {
+			return stat.TeamID
+		}
+		return playerTeams[stat.PlayerID]
+	}, players, nil
+}
+
+// teamMatchMetrics output of the team in a match from the score and the player stats of both teams
+func teamMatchMetrics(teamID string, match *domain.Match, stats []*domain.PlayerMatchStats, teamOf func(stat *domain.PlayerMatchStats) string) *domain.TeamMatchMetrics {
+	result := &domain.TeamMatchMetrics{
+		MatchID:      match.ID,
+		TeamID:       teamID,
+		OpponentID:   match.AwayTeamID,
+		Date:         match.Date,
+		Home:         match.HomeTeamID == teamID,
+		GoalsFor:     match.HomeScore,
+		GoalsAgainst: match.AwayScore,
+		Possession:   match.HomePossession,
+	}
+	if !result.Home {
+		result.OpponentID = match.HomeTeamID
+		result.GoalsFor, result.GoalsAgainst = match.AwayScore, match.HomeScore
+		result.Possession = match.AwayPossession
+	}
+
+	switch {
+	case result.GoalsFor > result.GoalsAgainst:
+		result.Points = 3
+	case result.GoalsFor == result.GoalsAgainst:
+		result.Points = 1
+	}
+
+	var forRows, againstRows int
+	for _, stat := range stats {
+		switch teamOf(stat) {
+		case teamID:
+			forRows++
+			result.ShotsFor += stat.Shots
+			result.ShotsOnTargetFor += stat.ShotsOnTarget
+		case result.OpponentID:
+			againstRows++
+			result.ShotsAgainst += stat.Shots
+			result.ShotsOnTargetAgainst += stat.ShotsOnTarget
+		}
+	}
+	result.StatsRecorded = forRows > 0 && againstRows > 0
+	if !result.StatsRecorded {
+		result.ShotsFor, result.ShotsOnTargetFor, result.ShotsAgainst, result.ShotsOnTargetAgainst = 0, 0, 0, 0
+	}
+
+	return result
+}
+
+// aggregateTeamMetrics performance of a team over its match results
+func aggregateTeamMetrics(teamID string, results []*domain.TeamMatchMetrics) *domain.TeamMetrics {
+	metrics := &domain.TeamMetrics{
+		TeamID:  teamID,
+		Matches: len(results),
+	}
+
+	if len(results) == 0 {
+		return metrics
+	}
+
+	var goalsFor, goalsAgainst, points, cleanSheets int
+	var shotsFor, shotsOnTargetFor, shotsAgainst, shotsOnTargetAgainst int
+	var possession float64
+
+	for _, result := range results {
+		goalsFor += result.GoalsFor
+		goalsAgainst += result.GoalsAgainst
+		points += result.Points
+		switch result.Points {
+		case 3:
+			metrics.Wins++
+		case 1:
+			metrics.Draws++
+		default:
+			metrics.Losses++
+		}
+		if result.GoalsAgainst == 0 {
+			cleanSheets++
+		}
+
+		if result.StatsRecorded {
+			metrics.ShotMatches++
+			shotsFor += result.ShotsFor
+			shotsOnTargetFor += result.ShotsOnTargetFor
+			shotsAgainst += result.ShotsAgainst
+			shotsOnTargetAgainst += result.ShotsOnTargetAgainst
+		}
+		if result.Possession != nil {
+			metrics.PossessionMatches++
+			possession += *result.Possession
+		}
+	}
+
+	matchCount := float64(len(results))
+	metrics.GoalsForPerMatch = float64(goalsFor) / matchCount
+	metrics.GoalsAgainstPerMatch = float64(goalsAgainst) / matchCount
+	metrics.PointsPerGame = float64(points) / matchCount
+	metrics.CleanSheetRate = float64(cleanSheets) / matchCount
+
+	if metrics.ShotMatches > 0 {
+		metrics.ShotsForPerMatch = float64(shotsFor) / float64(metrics.ShotMatches)
+		metrics.ShotsAgainstPerMatch = float64(shotsAgainst) / float64(metrics.ShotMatches)
+	}
+	if shotsFor > 0 {
+		metrics.ShotAccuracyFor = float64(shotsOnTargetFor) / float64(shotsFor)
+	}
+	if shotsAgainst > 0 {
+		metrics.ShotAccuracyAgainst = float64(shotsOnTargetAgainst) / float64(shotsAgainst)
+	}
+	if metrics.PossessionMatches > 0 {
+		average := possession / float64(metrics.PossessionMatches)
+		metrics.Possession = &average
+	}
+
+	return metrics
+}
+
+// buildTeamProgressSeries group the results of a team (ordered by date, matches in the same order)
+// into progress points with the windows of the player progress series
+func buildTeamProgressSeries(
+	teamID string,
+	matches []*domain.Match,
+	results []*domain.TeamMatchMetrics,
+	opts domain.ProgressOptions,
+	calendar *seasonCalendar,
+) ([]*domain.TeamProgressPoint, error) {
+	size, alpha, err := progressDefaults(opts)
+	if err != nil {
+		return nil, err
+	}
+
+	window := func(results []*domain.TeamMatchMetrics) *domain.TeamProgressPoint {
+		return &domain.TeamProgressPoint{
+			WindowStart: results[0].Date,
+			WindowEnd:   results[len(results)-1].Date,
+			Matches:     len(results),
+			Metrics:     aggregateTeamMetrics(teamID, results),
+		}
+	}
+
+	var points []*domain.TeamProgressPoint
+	switch opts.Window {
+	case domain.ProgressRolling:
+		if len(results) > 0 && len(results) < size {
+			points = append(points, window(results))
+		}
+		for i := size; i <= len(results); i++ {
+			points = append(points, window(results[i-size:i]))
+		}
+	case domain.ProgressBlock:
+		for i := 0; i < len(results); i += size {
+			end := i + size
+			if end > len(results) {
+				end = len(results)
+			}
+			points = append(points, window(results[i:end]))
+		}
+	case domain.ProgressWeek, domain.ProgressMonth, domain.ProgressSeason:
+		bucketOf := progressBucketOf(opts.Window, calendar)
+		var current []*domain.TeamMatchMetrics
+		var bucket progressBucket
+		flush := func() {
+			if len(current) > 0 {
+				point := window(current)
+				point.WindowStart, point.WindowEnd = bucket.start, bucket.end
+				points = append(points, point)
+			}
+			current = nil
+		}
+		for i, match := range matches {
+			next, err := bucketOf(match)
+			if err != nil {
+				return nil, err
+			}
+			if next.key != bucket.key {
+				flush()
+				bucket = next
+			}
+			current = append(current, results[i])
+		}
+		flush()
+	case domain.ProgressEWMA:
+		var smoothed *domain.TeamMetrics
+		for i, result := range results {
+			current := aggregateTeamMetrics(teamID, []*domain.TeamMatchMetrics{result})
+			if smoothed == nil {
+				smoothed = current
+			} else {
+				smoothed = blendTeamMetrics(smoothed, current, alpha)
+			}
+			smoothed.Matches = i + 1
+			points = append(points, &domain.TeamProgressPoint{
+				WindowStart: results[0].Date,
+				WindowEnd:   result.Date,
+				Matches:     i + 1,
+				Metrics:     smoothed,
+			})
+		}
+	default:
+		return nil, fmt.Errorf("unknown progress window %q", opts.Window)
+	}
+
+	return points, nil
+}
+
+// blendTeamMetrics exponentially weighted average of the rates of prev and current, alpha is the
+// weight of current, shot and possession rates of a match without them keep the previous value
+func blendTeamMetrics(prev, current *domain.TeamMetrics, alpha float64) *domain.TeamMetrics {
+	blend := func(p, c float64) float64 {
+		return alpha*c + (1-alpha)*p
+	}
+
+	blended := *prev
+	blended.Wins += current.Wins
+	blended.Draws += current.Draws
+	blended.Losses += current.Losses
+	blended.GoalsForPerMatch = blend(prev.GoalsForPerMatch, current.GoalsForPerMatch)
+	blended.GoalsAgainstPerMatch = blend(prev.GoalsAgainstPerMatch, current.GoalsAgainstPerMatch)
+	blended.PointsPerGame = blend(prev.PointsPerGame, current.PointsPerGame)
+	blended.CleanSheetRate = blend(prev.CleanSheetRate, current.CleanSheetRate)
+
+	if current.ShotMatches > 0 {
+		blended.ShotMatches += current.ShotMatches
+		if prev.ShotMatches == 0 {
+			blended.ShotsForPerMatch, blended.ShotsAgainstPerMatch = current.ShotsForPerMatch, current.ShotsAgainstPerMatch
+			blended.ShotAccuracyFor, blended.ShotAccuracyAgainst = current.ShotAccuracyFor, current.ShotAccuracyAgainst
+		} else {
+			blended.ShotsForPerMatch = blend(prev.ShotsForPerMatch, current.ShotsForPerMatch)
+			blended.ShotsAgainstPerMatch = blend(prev.ShotsAgainstPerMatch, current.ShotsAgainstPerMatch)
+			blended.ShotAccuracyFor = blend(prev.ShotAccuracyFor, current.ShotAccuracyFor)
+			blended.ShotAccuracyAgainst = blend(prev.ShotAccuracyAgainst, current.ShotAccuracyAgainst)
+		}
+	}
+
+	if current.Possession != nil {
+		blended.PossessionMatches += current.PossessionMatches
+		possession := *current.Possession
+		if prev.Possession != nil {
+			possession = blend(*prev.Possession, possession)
+		}
+		blended.Possession = &possession
+	}
+
+	return &blended
+}
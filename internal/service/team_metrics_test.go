@@ -0,0 +1,101 @@
+package service
+
+import (
+	"football-analytics/internal/domain"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+)
+
+func TestAggregateTeamMetrics(t *testing.T) {
+	possession := 60.0
+	teamOf := func(stat *domain.PlayerMatchStats) string { return stat.TeamID }
+
+	matches := []*domain.Match{
+		{ID: "m1", HomeTeamID: "t1", AwayTeamID: "t2", HomeScore: 2, AwayScore: 0, HomePossession: &possession, Date: utcDate(2024, time.August, 10)},
+		{ID: "m2", HomeTeamID: "t3", AwayTeamID: "t1", HomeScore: 1, AwayScore: 1, Date: utcDate(2024, time.August, 17)},
+		{ID: "m3", HomeTeamID: "t1", AwayTeamID: "t4", HomeScore: 0, AwayScore: 3, Date: utcDate(2024, time.August, 24)},
+	}
+	stats := map[string][]*domain.PlayerMatchStats{
+		"m1": {
+			{TeamID: "t1", Shots: 6, ShotsOnTarget: 3},
+			{TeamID: "t1", Shots: 4, ShotsOnTarget: 1},
+			{TeamID: "t2", Shots: 5, ShotsOnTarget: 1},
+		},
+		// only the team's own stats, shots are not counted
+		"m2": {{TeamID: "t1", Shots: 3, ShotsOnTarget: 3}},
+	}
+
+	var results []*domain.TeamMatchMetrics
+	for _, match := range matches {
+		results = append(results, teamMatchMetrics("t1", match, stats[match.ID], teamOf))
+	}
+
+	assert.True(t, results[0].Home)
+	assert.False(t, results[1].Home)
+	assert.Equal(t, "t3", results[1].OpponentID)
+	assert.Equal(t, 1, results[1].GoalsFor)
+	assert.False(t, results[1].StatsRecorded)
+	assert.Zero(t, results[1].ShotsFor)
+
+	metrics := aggregateTeamMetrics("t1", results)
+	assert.Equal(t, 3, metrics.Matches)
+	assert.Equal(t, 1, metrics.Wins)
+	assert.Equal(t, 1, metrics.Draws)
+	assert.Equal(t, 1, metrics.Losses)
+	assert.InDelta(t, 1.0, metrics.GoalsForPerMatch, 1e-9)
+	assert.InDelta(t, 4.0/3, metrics.GoalsAgainstPerMatch, 1e-9)
+	assert.InDelta(t, 4.0/3, metrics.PointsPerGame, 1e-9)
+	assert.InDelta(t, 1.0/3, metrics.CleanSheetRate, 1e-9)
+	assert.Equal(t, 1, metrics.ShotMatches)
+	assert.InDelta(t, 10, metrics.ShotsForPerMatch, 1e-9)
+	assert.InDelta(t, 0.4, metrics.ShotAccuracyFor, 1e-9)
+	assert.InDelta(t, 0.2, metrics.ShotAccuracyAgainst, 1e-9)
+	if assert.NotNil(t, metrics.Possession) {
+		assert.InDelta(t, 60, *metrics.Possession, 1e-9)
+		assert.Equal(t, 1, metrics.PossessionMatches)
+	}
+
+	points, err := buildTeamProgressSeries("t1", matches, results, domain.ProgressOptions{Window: domain.ProgressRolling, Size: 2}, newSeasonCalendar(nil))
+	assert.NoError(t, err)
+	if assert.Len(t, points, 2) {
+		assert.InDelta(t, 2, points[0].Metrics.PointsPerGame, 1e-9)
+		assert.InDelta(t, 0.5, points[1].Metrics.PointsPerGame, 1e-9)
+	}
+}
+
+func TestGetTeamMatchMetricsReadsStatsOnce(t *testing.T) {
+	matches := []*domain.Match{
+		{ID: "m1", HomeTeamID: "t1", AwayTeamID: "t2", HomeScore: 1, Date: utcDate(2024, time.September, 1), Status: "completed"},
+		{ID: "m2", HomeTeamID: "t3", AwayTeamID: "t4", HomeScore: 2, Date: utcDate(2024, time.September, 1), Status: "completed"},
+		{ID: "m3", HomeTeamID: "t2", AwayTeamID: "t1", AwayScore: 2, Date: utcDate(2024, time.September, 8), Status: "completed"},
+		{ID: "m4", HomeTeamID: "t1", AwayTeamID: "t3", Date: utcDate(2024, time.September, 15), Status: "scheduled"},
+	}
+
+	matchRepo := new(MockMatchRepository)
+	matchRepo.On("ListByDateRange", mock.Anything, mock.Anything).Return(matches, nil)
+	playerRepo := new(MockPlayerRepository)
+	playerRepo.On("List").Return([]*domain.Player{{ID: "p1", TeamID: "t1"}, {ID: "p2", TeamID: "t2"}}, nil)
+	statsRepo := new(MockPlayerMatchStatsRepository)
+	statsRepo.On("ListByMatchIDs", []string{"m1", "m3"}).Return([]*domain.PlayerMatchStats{
+		{PlayerID: "p1", MatchID: "m1", Shots: 4, ShotsOnTarget: 2},
+		{PlayerID: "p2", MatchID: "m1", Shots: 1},
+		{PlayerID: "p1", MatchID: "m3", Shots: 3, ShotsOnTarget: 3},
+	}, nil).Once()
+
+	service := NewAnalyticsService(statsRepo, playerRepo, matchRepo, nil, nil, nil, nil, domain.FixedClock(utcDate(2024, time.October, 1)))
+
+	results, err := service.GetTeamMatchMetrics("t1", "all")
+	assert.NoError(t, err)
+	assert.Len(t, results, 2)
+	assert.Equal(t, 4, results[0].ShotsFor)
+	assert.Equal(t, 1, results[0].ShotsAgainst)
+	assert.True(t, results[0].StatsRecorded)
+	// p2 did not record stats in m3
+	assert.False(t, results[1].StatsRecorded)
+	assert.Equal(t, 3, results[1].Points)
+	statsRepo.AssertExpectations(t)
+	statsRepo.AssertNotCalled(t, "ListByMatchID", mock.Anything)
+}
@@ -0,0 +1,218 @@
+package service
+
+import (
+	"football-analytics/internal/domain"
+	"sort"
+	"time"
+)
+
+// timeWindow is a time range resolved for one subject, the matches from start to end (both
+// included) of the competition, or of every competition if it is empty
+type timeWindow struct {
+	start       time.Time
+	end         time.Time
+	competition string
+}
+
+// includes report whether a match is in the window
+func (w timeWindow) includes(match *domain.Match) bool {
+	return !match.Date.Before(w.start) && !match.Date.After(w.end) &&
+		(w.competition == "" || match.Competition == w.competition)
+}
+
+// filter keep the rows in the window, keeping their order
+func (w timeWindow) filter(rows []*domain.PlayerAppearance) []*domain.PlayerAppearance {
+	var kept []*domain.PlayerAppearance
+	for _, row := range rows {
+		if w.includes(row.Match) {
+			kept = append(kept, row)
+		}
+	}
+	return kept
+}
+
+// span dates covering every match the time range can select at now for any subject, nothing after
+// now is selected so ranges resolved with a fixed clock are reproducible, the window of a subject
+// is narrowed down from its matches in the span by playerWindow and teamWindow
+func (c *seasonCalendar) span(tr domain.TimeRange, now time.Time) (time.Time, time.Time, error) {
+	start, end := time.Time{}, now
+
+	switch tr.Kind {
+	case domain.TimeRangeRelative:
+		start = now.AddDate(0, -tr.Months, -tr.Days)
+	case domain.TimeRangeDates:
+		start = tr.Start
+		if !tr.End.IsZero() && tr.End.Before(now) {
+			end = tr.End.Add(-time.Nanosecond)
+		}
+	case domain.TimeRangeSeason:
+		if tr.Season != "" {
+			seasonStart, seasonEnd, _, err := c.labelRange(tr.Season, tr.Competition)
+			if err != nil {
+				return time.Time{}, time.Time{}, err
+			}
+			start = seasonStart
+			if seasonEnd.Before(now) {
+				end = seasonEnd.Add(-time.Nanosecond)
+			}
+			break
+		}
+
+		var err error
+		if start, err = c.currentStart(tr.Competition, now); err != nil {
+			return time.Time{}, time.Time{}, err
+		}
+		if tr.Competition == "" && start.After(now.AddDate(-1, 0, 0)) {
+			// a year of matches to find the competition, and so the season, of every subject
+			start = now.AddDate(-1, 0, 0)
+		}
+	}
+
+	return start, end, nil
+}
+
+// playerWindow resolve the time range for a player from their appearances in its span, ordered by
+// date, the current season is the one of the competition the player played most minutes in over
+// the past year and the last matches are the player's own appearances
+func (c *seasonCalendar) playerWindow(tr domain.TimeRange, rows []*domain.PlayerAppearance, now time.Time) (timeWindow, error) {
+	start, end, err := c.span(tr, now)
+	if err != nil {
+		return timeWindow{}, err
+	}
+	window := timeWindow{start: start, end: end, competition: tr.Competition}
+
+	switch {
+	case tr.Kind == domain.TimeRangeSeason && tr.Season == "" && tr.Competition == "":
+		minutes := make(map[string]int)
+		for _, row := range rows {
+			if !row.Match.Date.Before(now.AddDate(-1, 0, 0)) {
+				minutes[row.Match.Competition] += row.Stats.MinutesPlayed
+			}
+		}
+		window.start, err = c.currentStart(mainCompetition(minutes), now)
+	case tr.Kind == domain.TimeRangeMatches:
+		var dates []time.Time
+		for _, row := range window.filter(rows) {
+			dates = append(dates, row.Match.Date)
+		}
+		window.start = lastMatchesStart(dates, tr.Matches, now)
+	}
+
+	return window, err
+}
+
+// teamWindow resolve the time range for a team from its matches, the current season is the one of
+// the competition the team played most matches in over the past year and the last matches are the
+// team's completed ones
+func (c *seasonCalendar) teamWindow(tr domain.TimeRange, matches []*domain.Match, now time.Time) (timeWindow, error) {
+	start, end, err := c.span(tr, now)
+	if err != nil {
+		return timeWindow{}, err
+	}
+	window := timeWindow{start: start, end: end, competition: tr.Competition}
+
+	switch {
+	case tr.Kind == domain.TimeRangeSeason && tr.Season == "" && tr.Competition == "":
+		played := make(map[string]int)
+		for _, match := range matches {
+			if !match.Date.Before(now.AddDate(-1, 0, 0)) && !match.Date.After(now) {
+				played[match.Competition]++
+			}
+		}
+		window.start, err = c.currentStart(mainCompetition(played), now)
+	case tr.Kind == domain.TimeRangeMatches:
+		var dates []time.Time
+		for _, match := range sortedMatches(matches) {
+			if match.Status == "completed" && window.includes(match) {
+				dates = append(dates, match.Date)
+			}
+		}
+		window.start = lastMatchesStart(dates, tr.Matches, now)
+	}
+
+	return window, err
+}
+
+// lastMatchesStart date of the n-th last of the ordered dates, now if there are none
+func lastMatchesStart(dates []time.Time, n int, now time.Time) time.Time {
+	if len(dates) == 0 {
+		return now
+	}
+	if len(dates) < n {
+		return dates[0]
+	}
+	return dates[len(dates)-n]
+}
+
+// mainCompetition the competition with the highest count, ties go to the first name, empty if there is none
+func mainCompetition(counts map[string]int) string {
+	main, best := "", 0
+	for competition, count := range counts {
+		if count > best || (count == best && count > 0 && competition < main) {
+			main, best = competition, count
+		}
+	}
+	return main
+}
+
+// playerRange get the appearances of the player in the time range, ordered by match date
+func (s *analyticsService) playerRange(playerID string, tr domain.TimeRange, now time.Time) ([]appearance, timeWindow, error) {
+	start, end, err := s.calendar.span(tr, now)
+	if err != nil {
+		return nil, timeWindow{}, err
+	}
+
+	rows, err := s.playerStatsRepo.ListByPlayerAndDateRange(playerID, start, end)
+	if err != nil {
+		return nil, timeWindow{}, err
+	}
+
+	window, err := s.calendar.playerWindow(tr, rows, now)
+	if err != nil {
+		return nil, timeWindow{}, err
+	}
+
+	return toAppearances(window.filter(rows)), window, nil
+}
+
+// teamRange get every match of the time range resolved for the team, ordered by date
+func (s *analyticsService) teamRange(teamID string, tr domain.TimeRange, now time.Time) ([]*domain.Match, error) {
+	window := timeWindow{competition: tr.Competition}
+	if tr.Kind == domain.TimeRangeMatches || (tr.Kind == domain.TimeRangeSeason && tr.Season == "" && tr.Competition == "") {
+		teamMatches, err := s.matchRepo.ListByTeamID(teamID)
+		if err != nil {
+			return nil, err
+		}
+		if window, err = s.calendar.teamWindow(tr, teamMatches, now); err != nil {
+			return nil, err
+		}
+	} else {
+		start, end, err := s.calendar.span(tr, now)
+		if err != nil {
+			return nil, err
+		}
+		window.start, window.end = start, end
+	}
+
+	matches, err := s.matchRepo.ListByDateRange(window.start, window.end)
+	if err != nil {
+		return nil, err
+	}
+
+	var selected []*domain.Match
+	for _, match := range sortedMatches(matches) {
+		if window.includes(match) {
+			selected = append(selected, match)
+		}
+	}
+	return selected, nil
+}
+
+// sortedMatches copy of the matches ordered by date
+func sortedMatches(matches []*domain.Match) []*domain.Match {
+	sorted := append([]*domain.Match(nil), matches...)
+	sort.SliceStable(sorted, func(i, j int) bool {
+		return sorted[i].Date.Before(sorted[j].Date)
+	})
+	return sorted
+}
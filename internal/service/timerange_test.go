@@ -0,0 +1,46 @@
+package service
+
+import (
+	"football-analytics/internal/domain"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestPlayerWindow(t *testing.T) {
+	calendar := newTestCalendar()
+	now := utcDate(2025, 6, 1)
+	row := func(competition string, date, minutes int) *domain.PlayerAppearance {
+		return &domain.PlayerAppearance{
+			Match: &domain.Match{Competition: competition, Date: utcDate(2025, 1, 1).AddDate(0, 0, date)},
+			Stats: &domain.PlayerMatchStats{MinutesPlayed: minutes},
+		}
+	}
+	rows := []*domain.PlayerAppearance{
+		row("Premier League", -60, 90),
+		row("MLS", 60, 90),
+		row("Cup", 70, 90),
+		row("MLS", 80, 90),
+		row("MLS", 90, 90),
+	}
+
+	// the current season follows the competition with the most minutes
+	tr, _ := domain.ParseTimeRange("season")
+	window, err := calendar.playerWindow(tr, rows, now)
+	assert.NoError(t, err)
+	assert.Equal(t, utcDate(2025, 2, 22), window.start)
+	assert.Len(t, window.filter(rows), 4)
+
+	tr, _ = domain.ParseTimeRange("last:2,competition:MLS")
+	window, err = calendar.playerWindow(tr, rows, now)
+	assert.NoError(t, err)
+	if kept := window.filter(rows); assert.Len(t, kept, 2) {
+		assert.Equal(t, rows[3], kept[0])
+	}
+
+	// nothing after now is selected, whatever the range asks for
+	tr, _ = domain.ParseTimeRange("2025-01-01..2025-12-31")
+	window, err = calendar.playerWindow(tr, rows, utcDate(2025, 3, 15))
+	assert.NoError(t, err)
+	assert.Len(t, window.filter(rows), 2)
+}
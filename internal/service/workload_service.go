@@ -0,0 +1,162 @@
+package service
+
+import (
+	"football-analytics/internal/domain"
+	"sort"
+	"time"
+)
+
+const (
+	highWorkloadRatio = 1.5
+	lowWorkloadRatio  = 0.8
+	congestionWindow  = 72 * time.Hour
+)
+
+// WorkloadService is interface for player workload and fatigue monitoring
+type WorkloadService interface {
+	GetPlayerWorkload(playerID string, date time.Time) (*domain.PlayerWorkload, error)
+	GetTeamWorkloadReport(teamID string, date time.Time) (*domain.TeamWorkloadReport, error)
+}
+
+type workloadService struct {
+	playerStatsRepo domain.PlayerMatchStatsRepository
+	playerRepo      domain.PlayerRepository
+}
+
+// NewWorkloadService create instance of WorkloadService
+func NewWorkloadService(
+	playerStatsRepo domain.PlayerMatchStatsRepository,
+	playerRepo domain.PlayerRepository,
+) WorkloadService {
+	return &workloadService{
+		playerStatsRepo: playerStatsRepo,
+		playerRepo:      playerRepo,
+	}
+}
+
+// GetPlayerWorkload get workload of a player in the 28 days up to and including date
+func (s *workloadService) GetPlayerWorkload(playerID string, date time.Time) (*domain.PlayerWorkload, error) {
+	player, err := s.playerRepo.GetByID(playerID)
+	if err != nil {
+		return nil, err
+	}
+
+	start, end := workloadRange(date)
+	appearances, err := loadPlayerAppearances(s.playerStatsRepo, playerID, start, end)
+	if err != nil {
+		return nil, err
+	}
+
+	return calculateWorkload(player, appearances, end), nil
+}
+
+// GetTeamWorkloadReport get workload of every player of the team at date, highest workload ratio first
+func (s *workloadService) GetTeamWorkloadReport(teamID string, date time.Time) (*domain.TeamWorkloadReport, error) {
+	allPlayers, err := s.playerRepo.List()
+	if err != nil {
+		return nil, err
+	}
+
+	start, end := workloadRange(date)
+
+	var squad []*domain.Player
+	var playerIDs []string
+	for _, player := range allPlayers {
+		if player.TeamID == teamID {
+			squad = append(squad, player)
+			playerIDs = append(playerIDs, player.ID)
+		}
+	}
+
+	// the appearances of the whole squad are fetched in one query
+	rows, err := s.playerStatsRepo.ListByPlayersAndDateRange(playerIDs, start, end)
+	if err != nil {
+		return nil, err
+	}
+	appearancesByPlayer := make(map[string][]appearance)
+	for _, app := range toAppearances(rows) {
+		appearancesByPlayer[app.stats.PlayerID] = append(appearancesByPlayer[app.stats.PlayerID], app)
+	}
+
+	report := &domain.TeamWorkloadReport{
+		TeamID: teamID,
+		Date:   date,
+	}
+
+	for _, player := range squad {
+		report.Players = append(report.Players, calculateWorkload(player, appearancesByPlayer[player.ID], end))
+	}
+
+	sort.SliceStable(report.Players, func(i, j int) bool {
+		return report.Players[i].WorkloadRatio > report.Players[j].WorkloadRatio
+	})
+
+	return report, nil
+}
+
+// workloadRange the 28 days up to the end of date
+func workloadRange(date time.Time) (time.Time, time.Time) {
+	d := date.UTC()
+	end := time.Date(d.Year(), d.Month(), d.Day(), 0, 0, 0, 0, time.UTC).AddDate(0, 0, 1)
+	return end.AddDate(0, 0, -28), end
+}
+
+// calculateWorkload workload of a player from appearances (ordered by date) before end
+func calculateWorkload(player *domain.Player, appearances []appearance, end time.Time) *domain.PlayerWorkload {
+	workload := &domain.PlayerWorkload{
+		PlayerID: player.ID,
+		Name:     player.Name,
+		Position: player.Position,
+		Date:     end.AddDate(0, 0, -1),
+	}
+
+	var recent []time.Time
+	for _, app := range appearances {
+		date := app.match.Date
+		if !date.Before(end) || date.Before(end.AddDate(0, 0, -28)) {
+			continue
+		}
+
+		workload.Minutes28d += app.stats.MinutesPlayed
+		workload.Distance28d += app.stats.DistanceCovered
+		workload.Matches28d++
+		workload.LastMatchDate = date
+
+		if !date.Before(end.AddDate(0, 0, -14)) {
+			workload.Minutes14d += app.stats.MinutesPlayed
+		}
+		if !date.Before(end.AddDate(0, 0, -7)) {
+			workload.Minutes7d += app.stats.MinutesPlayed
+			workload.Distance7d += app.stats.DistanceCovered
+			workload.Matches7d++
+			recent = append(recent, date)
+		}
+	}
+
+	workload.AcuteLoad = float64(workload.Minutes7d)
+	workload.ChronicLoad = float64(workload.Minutes28d) / 4
+	if workload.ChronicLoad > 0 {
+		workload.WorkloadRatio = workload.AcuteLoad / workload.ChronicLoad
+	}
+	if workload.Distance28d > 0 {
+		workload.DistanceRatio = workload.Distance7d / (workload.Distance28d / 4)
+	}
+
+	for i := 1; i < len(recent); i++ {
+		if recent[i].Sub(recent[i-1]) < congestionWindow {
+			workload.Flags = append(workload.Flags, domain.FlagMatchesWithin72h)
+			break
+		}
+	}
+	if workload.Matches7d >= 3 {
+		workload.Flags = append(workload.Flags, domain.FlagThreeMatchesIn7d)
+	}
+	if workload.WorkloadRatio > highWorkloadRatio {
+		workload.Flags = append(workload.Flags, domain.FlagHighWorkloadRatio)
+	}
+	if workload.ChronicLoad > 0 && workload.WorkloadRatio < lowWorkloadRatio {
+		workload.Flags = append(workload.Flags, domain.FlagLowWorkloadRatio)
+	}
+
+	return workload
+}
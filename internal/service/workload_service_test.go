@@ -0,0 +1,89 @@
+package service
+
+import (
+	"fmt"
+	"football-analytics/internal/domain"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+)
+
+// newWorkloadFixture squad of t1 with different match loads up to 2024-10-07
+func newWorkloadFixture() WorkloadService {
+	players := &memoryPlayerRepository{players: make(map[string]*domain.Player)}
+	for _, id := range []string{"idle", "rested", "regular", "congested"} {
+		player := &domain.Player{ID: id, Name: id, TeamID: "t1", Position: "Midfielder"}
+		players.players[id] = player
+		players.list = append(players.list, player)
+	}
+	players.list = append(players.list, &domain.Player{ID: "other", TeamID: "t2"})
+
+	stats := &memoryStatsRepository{matches: make(map[string]*domain.Match), byPlayer: make(map[string][]*domain.PlayerMatchStats)}
+	play := func(playerID string, dates ...time.Time) {
+		for _, date := range dates {
+			match := &domain.Match{ID: fmt.Sprintf("%s-%s", playerID, date.Format(domain.DateLayout)), Date: date, Status: "completed"}
+			stats.matches[match.ID] = match
+			stats.byPlayer[playerID] = append(stats.byPlayer[playerID], &domain.PlayerMatchStats{PlayerID: playerID, MatchID: match.ID, MinutesPlayed: 90, DistanceCovered: 10})
+		}
+	}
+	play("congested", utcDate(2024, 10, 1), utcDate(2024, 10, 3), utcDate(2024, 10, 6))
+	play("regular", utcDate(2024, 9, 10), utcDate(2024, 9, 17), utcDate(2024, 9, 24), utcDate(2024, 10, 1))
+	play("rested", utcDate(2024, 9, 12), utcDate(2024, 9, 19))
+	// played before the 28 day window
+	play("idle", utcDate(2024, 9, 1))
+
+	return NewWorkloadService(stats, players)
+}
+
+func TestGetPlayerWorkload(t *testing.T) {
+	service := newWorkloadFixture()
+
+	congested, err := service.GetPlayerWorkload("congested", utcDate(2024, 10, 7))
+	assert.NoError(t, err)
+	assert.Equal(t, 3, congested.Matches7d)
+	assert.Equal(t, 270, congested.Minutes28d)
+	assert.InDelta(t, 270/67.5, congested.WorkloadRatio, 1e-9)
+	assert.InDelta(t, 30/7.5, congested.DistanceRatio, 1e-9)
+	assert.Equal(t, utcDate(2024, 10, 6), congested.LastMatchDate)
+	assert.Equal(t, []domain.WorkloadFlag{domain.FlagMatchesWithin72h, domain.FlagThreeMatchesIn7d, domain.FlagHighWorkloadRatio}, congested.Flags)
+
+	regular, err := service.GetPlayerWorkload("regular", utcDate(2024, 10, 7))
+	assert.NoError(t, err)
+	assert.Equal(t, 4, regular.Matches28d)
+	assert.Equal(t, 180, regular.Minutes14d)
+	assert.InDelta(t, 1, regular.WorkloadRatio, 1e-9)
+	assert.Empty(t, regular.Flags)
+
+	rested, err := service.GetPlayerWorkload("rested", utcDate(2024, 10, 7))
+	assert.NoError(t, err)
+	assert.Equal(t, 0.0, rested.WorkloadRatio)
+	assert.Equal(t, []domain.WorkloadFlag{domain.FlagLowWorkloadRatio}, rested.Flags)
+}
+
+func TestGetPlayerWorkloadZeroChronicLoad(t *testing.T) {
+	service := newWorkloadFixture()
+
+	// no minutes in the 28 days: the ratio is 0 and not flagged as low
+	idle, err := service.GetPlayerWorkload("idle", utcDate(2024, 10, 7))
+	assert.NoError(t, err)
+	assert.Equal(t, 0, idle.Matches28d)
+	assert.Equal(t, 0.0, idle.ChronicLoad)
+	assert.Equal(t, 0.0, idle.WorkloadRatio)
+	assert.Equal(t, 0.0, idle.DistanceRatio)
+	assert.Empty(t, idle.Flags)
+}
+
+func TestGetTeamWorkloadReport(t *testing.T) {
+	service := newWorkloadFixture()
+
+	report, err := service.GetTeamWorkloadReport("t1", utcDate(2024, 10, 7))
+	assert.NoError(t, err)
+
+	var order []string
+	for _, workload := range report.Players {
+		order = append(order, workload.PlayerID)
+	}
+	// highest ratio first, equal ratios keep the squad order
+	assert.Equal(t, []string{"congested", "regular", "idle", "rested"}, order)
+}
@@ -0,0 +1 @@
+DROP TABLE IF EXISTS match_models;
\ No newline at end of file
@@ -0,0 +1,13 @@
+CREATE TABLE match_models (
+    id UUID PRIMARY KEY DEFAULT uuid_generate_v4(),
+    version VARCHAR(100) NOT NULL UNIQUE,
+    home_advantage DOUBLE PRECISION NOT NULL,
+    rho DOUBLE PRECISION NOT NULL,
+    decay_rate DOUBLE PRECISION NOT NULL,
+    strengths JSONB NOT NULL,
+    trained_until TIMESTAMP WITH TIME ZONE NOT NULL,
+    matches INTEGER NOT NULL,
+    created_at TIMESTAMP WITH TIME ZONE DEFAULT NOW()
+);
+
+CREATE INDEX idx_match_models_created_at ON match_models(created_at);
@@ -0,0 +1,4 @@
+DROP TABLE IF EXISTS suspension_served_matches;
+DROP TABLE IF EXISTS suspensions;
+DROP TABLE IF EXISTS discipline_rules;
+ALTER TABLE matches DROP COLUMN IF EXISTS round;
\ No newline at end of file
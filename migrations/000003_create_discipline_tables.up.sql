@@ -0,0 +1,36 @@
+ALTER TABLE matches ADD COLUMN round INTEGER DEFAULT 0;
+
+CREATE TABLE discipline_rules (
+    id UUID PRIMARY KEY DEFAULT uuid_generate_v4(),
+    competition VARCHAR(100) NOT NULL UNIQUE,
+    yellow_threshold INTEGER NOT NULL,
+    yellow_ban_matches INTEGER NOT NULL DEFAULT 1,
+    straight_red_ban_matches INTEGER NOT NULL DEFAULT 1,
+    second_yellow_ban_matches INTEGER NOT NULL DEFAULT 1,
+    reset_round INTEGER NOT NULL DEFAULT 0,
+    created_at TIMESTAMP WITH TIME ZONE DEFAULT NOW(),
+    updated_at TIMESTAMP WITH TIME ZONE DEFAULT NOW()
+);
+
+CREATE TABLE suspensions (
+    id UUID PRIMARY KEY DEFAULT uuid_generate_v4(),
+    player_id UUID REFERENCES players(id),
+    competition VARCHAR(100) NOT NULL,
+    trigger_match_id UUID REFERENCES matches(id),
+    trigger_date TIMESTAMP WITH TIME ZONE NOT NULL,
+    reason VARCHAR(30) NOT NULL,
+    matches INTEGER NOT NULL,
+    served INTEGER NOT NULL DEFAULT 0,
+    created_at TIMESTAMP WITH TIME ZONE DEFAULT NOW(),
+    updated_at TIMESTAMP WITH TIME ZONE DEFAULT NOW(),
+    UNIQUE(player_id, trigger_match_id, reason)
+);
+
+CREATE TABLE suspension_served_matches (
+    suspension_id UUID REFERENCES suspensions(id) ON DELETE CASCADE,
+    match_id UUID REFERENCES matches(id),
+    created_at TIMESTAMP WITH TIME ZONE DEFAULT NOW(),
+    PRIMARY KEY (suspension_id, match_id)
+);
+
+CREATE INDEX idx_suspensions_player_id ON suspensions(player_id);
@@ -0,0 +1 @@
+ALTER TABLE player_match_stats DROP COLUMN IF EXISTS team_id;
\ No newline at end of file
@@ -0,0 +1,9 @@
+ALTER TABLE player_match_stats ADD COLUMN team_id UUID REFERENCES teams(id);
+
+-- backfill with the current team of the player when it played in the match
+UPDATE player_match_stats s
+SET team_id = p.team_id
+FROM players p, matches m
+WHERE p.id = s.player_id AND m.id = s.match_id AND p.team_id IN (m.home_team_id, m.away_team_id);
+
+CREATE INDEX idx_player_match_stats_team_id ON player_match_stats(team_id);
@@ -0,0 +1,2 @@
+DROP TABLE IF EXISTS player_roles;
+DROP TABLE IF EXISTS role_models;
@@ -0,0 +1,21 @@
+CREATE TABLE role_models (
+    id UUID PRIMARY KEY DEFAULT uuid_generate_v4(),
+    season VARCHAR(20) NOT NULL,
+    k INTEGER NOT NULL,
+    inertia DOUBLE PRECISION NOT NULL,
+    clusters JSONB NOT NULL,
+    created_at TIMESTAMP WITH TIME ZONE DEFAULT NOW()
+);
+
+CREATE TABLE player_roles (
+    role_model_id UUID REFERENCES role_models(id) ON DELETE CASCADE,
+    player_id UUID REFERENCES players(id),
+    role_id UUID NOT NULL,
+    label VARCHAR(200) NOT NULL,
+    confidence DOUBLE PRECISION NOT NULL,
+    minutes INTEGER NOT NULL,
+    PRIMARY KEY (role_model_id, player_id)
+);
+
+CREATE INDEX idx_role_models_season ON role_models(season, created_at);
+CREATE INDEX idx_player_roles_player_id ON player_roles(player_id);
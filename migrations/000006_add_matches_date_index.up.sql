@@ -0,0 +1 @@
+CREATE INDEX idx_matches_date ON matches(date);
@@ -0,0 +1,39 @@
+CREATE TABLE player_season_rollups (
+    player_id UUID REFERENCES players(id),
+    season VARCHAR(20) NOT NULL,
+    competition VARCHAR(100) NOT NULL DEFAULT '',
+    matches_played INTEGER NOT NULL DEFAULT 0,
+    minutes_played INTEGER NOT NULL DEFAULT 0,
+    goals INTEGER NOT NULL DEFAULT 0,
+    assists INTEGER NOT NULL DEFAULT 0,
+    passes INTEGER NOT NULL DEFAULT 0,
+    pass_accuracy_sum DOUBLE PRECISION NOT NULL DEFAULT 0,
+    shots INTEGER NOT NULL DEFAULT 0,
+    shots_on_target INTEGER NOT NULL DEFAULT 0,
+    tackles INTEGER NOT NULL DEFAULT 0,
+    interceptions INTEGER NOT NULL DEFAULT 0,
+    fouls INTEGER NOT NULL DEFAULT 0,
+    yellow_cards INTEGER NOT NULL DEFAULT 0,
+    red_cards INTEGER NOT NULL DEFAULT 0,
+    distance_covered DOUBLE PRECISION NOT NULL DEFAULT 0,
+    updated_at TIMESTAMP WITH TIME ZONE DEFAULT NOW(),
+    PRIMARY KEY (player_id, season, competition)
+);
+
+CREATE INDEX idx_player_season_rollups_season ON player_season_rollups(season, competition);
+
+-- seasons start on August 1, labelled like 2024/25
+INSERT INTO player_season_rollups (player_id, season, competition, matches_played, minutes_played, goals, assists,
+    passes, pass_accuracy_sum, shots, shots_on_target, tackles, interceptions, fouls, yellow_cards, red_cards, distance_covered)
+SELECT s.player_id,
+    y.year || '/' || LPAD(((y.year + 1) % 100)::text, 2, '0'),
+    COALESCE(m.competition, ''),
+    COUNT(*), SUM(s.minutes_played), SUM(s.goals), SUM(s.assists), SUM(s.passes), SUM(s.pass_accuracy), SUM(s.shots),
+    SUM(s.shots_on_target), SUM(s.tackles), SUM(s.interceptions), SUM(s.fouls), SUM(s.yellow_cards), SUM(s.red_cards),
+    SUM(s.distance_covered)
+FROM player_match_stats s
+JOIN matches m ON m.id = s.match_id
+CROSS JOIN LATERAL (
+    SELECT EXTRACT(YEAR FROM m.date AT TIME ZONE 'UTC')::int - CASE WHEN EXTRACT(MONTH FROM m.date AT TIME ZONE 'UTC') < 8 THEN 1 ELSE 0 END AS year
+) y
+GROUP BY s.player_id, y.year, COALESCE(m.competition, '');
@@ -0,0 +1,3 @@
+DROP INDEX IF EXISTS idx_matches_season;
+ALTER TABLE matches DROP COLUMN IF EXISTS season_id;
+DROP TABLE IF EXISTS seasons;
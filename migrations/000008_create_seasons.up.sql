@@ -0,0 +1,36 @@
+CREATE TABLE seasons (
+    id UUID PRIMARY KEY DEFAULT uuid_generate_v4(),
+    competition VARCHAR(100) NOT NULL,
+    label VARCHAR(20) NOT NULL,
+    start_date TIMESTAMP WITH TIME ZONE NOT NULL,
+    end_date TIMESTAMP WITH TIME ZONE NOT NULL,
+    created_at TIMESTAMP WITH TIME ZONE DEFAULT NOW(),
+    updated_at TIMESTAMP WITH TIME ZONE DEFAULT NOW(),
+    UNIQUE (competition, label),
+    CHECK (end_date > start_date)
+);
+
+CREATE INDEX idx_seasons_competition_start ON seasons(competition, start_date);
+
+ALTER TABLE matches ADD COLUMN season_id UUID REFERENCES seasons(id);
+
+CREATE INDEX idx_matches_season ON matches(season_id);
+
+-- existing competitions get the August to August seasons they were analysed with so far,
+-- competitions with another calendar are corrected by updating their seasons and relinking matches
+INSERT INTO seasons (competition, label, start_date, end_date)
+SELECT y.competition,
+    y.year || '/' || LPAD(((y.year + 1) % 100)::text, 2, '0'),
+    make_timestamptz(y.year, 8, 1, 0, 0, 0, 'UTC'),
+    make_timestamptz(y.year + 1, 8, 1, 0, 0, 0, 'UTC')
+FROM (
+    SELECT DISTINCT COALESCE(m.competition, '') AS competition,
+        EXTRACT(YEAR FROM m.date AT TIME ZONE 'UTC')::int
+            - CASE WHEN EXTRACT(MONTH FROM m.date AT TIME ZONE 'UTC') < 8 THEN 1 ELSE 0 END AS year
+    FROM matches m
+) y;
+
+UPDATE matches m
+SET season_id = se.id
+FROM seasons se
+WHERE se.competition = COALESCE(m.competition, '') AND m.date >= se.start_date AND m.date < se.end_date;
@@ -0,0 +1,18 @@
+CREATE TABLE metrics_snapshots (
+    id UUID PRIMARY KEY DEFAULT uuid_generate_v4(),
+    player_id UUID NOT NULL REFERENCES players(id),
+    date DATE NOT NULL,
+    time_range VARCHAR(100) NOT NULL,
+    model_version VARCHAR(20) NOT NULL,
+    goals_per_minute DOUBLE PRECISION NOT NULL DEFAULT 0,
+    assists_per_minute DOUBLE PRECISION NOT NULL DEFAULT 0,
+    pass_accuracy DOUBLE PRECISION NOT NULL DEFAULT 0,
+    shot_accuracy DOUBLE PRECISION NOT NULL DEFAULT 0,
+    defensive_efficiency DOUBLE PRECISION NOT NULL DEFAULT 0,
+    stamina DOUBLE PRECISION NOT NULL DEFAULT 0,
+    overall_rating DOUBLE PRECISION NOT NULL DEFAULT 0,
+    created_at TIMESTAMP WITH TIME ZONE DEFAULT NOW(),
+    UNIQUE (player_id, date, time_range, model_version)
+);
+
+CREATE INDEX idx_metrics_snapshots_date ON metrics_snapshots(time_range, model_version, date);
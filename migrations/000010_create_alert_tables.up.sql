@@ -0,0 +1,32 @@
+CREATE TABLE alert_rules (
+    id UUID PRIMARY KEY DEFAULT uuid_generate_v4(),
+    name VARCHAR(100) NOT NULL,
+    metric VARCHAR(50) NOT NULL,
+    operator VARCHAR(2) NOT NULL,
+    threshold DOUBLE PRECISION NOT NULL,
+    window_matches INTEGER NOT NULL DEFAULT 5,
+    player_id UUID REFERENCES players(id),
+    team_id UUID REFERENCES teams(id),
+    notifiers TEXT[] NOT NULL DEFAULT '{}',
+    disabled BOOLEAN NOT NULL DEFAULT FALSE,
+    created_at TIMESTAMP WITH TIME ZONE DEFAULT NOW(),
+    updated_at TIMESTAMP WITH TIME ZONE DEFAULT NOW()
+);
+
+CREATE TABLE alerts (
+    id UUID PRIMARY KEY DEFAULT uuid_generate_v4(),
+    rule_id UUID NOT NULL REFERENCES alert_rules(id) ON DELETE CASCADE,
+    rule_name VARCHAR(100) NOT NULL,
+    player_id UUID NOT NULL REFERENCES players(id),
+    match_id UUID REFERENCES matches(id),
+    metric VARCHAR(50) NOT NULL,
+    value DOUBLE PRECISION NOT NULL,
+    threshold DOUBLE PRECISION NOT NULL,
+    message TEXT NOT NULL,
+    state VARCHAR(20) NOT NULL DEFAULT 'open',
+    triggered_at TIMESTAMP WITH TIME ZONE NOT NULL,
+    acknowledged_at TIMESTAMP WITH TIME ZONE
+);
+
+CREATE INDEX idx_alerts_rule_player ON alerts(rule_id, player_id);
+CREATE INDEX idx_alerts_state ON alerts(state, triggered_at);
@@ -0,0 +1,2 @@
+ALTER TABLE matches DROP COLUMN IF EXISTS away_possession;
+ALTER TABLE matches DROP COLUMN IF EXISTS home_possession;
@@ -0,0 +1,2 @@
+ALTER TABLE matches ADD COLUMN home_possession DOUBLE PRECISION;
+ALTER TABLE matches ADD COLUMN away_possession DOUBLE PRECISION;
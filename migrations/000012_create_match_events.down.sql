@@ -0,0 +1,4 @@
+DROP TABLE IF EXISTS match_events;
+
+ALTER TABLE matches DROP COLUMN IF EXISTS away_coach;
+ALTER TABLE matches DROP COLUMN IF EXISTS home_coach;
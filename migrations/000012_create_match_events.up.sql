@@ -0,0 +1,17 @@
+ALTER TABLE matches ADD COLUMN home_coach VARCHAR(255);
+ALTER TABLE matches ADD COLUMN away_coach VARCHAR(255);
+
+CREATE TABLE match_events (
+    id UUID PRIMARY KEY DEFAULT uuid_generate_v4(),
+    match_id UUID NOT NULL REFERENCES matches(id) ON DELETE CASCADE,
+    team_id UUID NOT NULL REFERENCES teams(id),
+    type VARCHAR(20) NOT NULL,
+    minute INTEGER NOT NULL CHECK (minute >= 0),
+    player_id UUID NOT NULL REFERENCES players(id),
+    related_player_id UUID REFERENCES players(id),
+    stats_id UUID REFERENCES player_match_stats(id) ON DELETE SET NULL,
+    created_at TIMESTAMP WITH TIME ZONE DEFAULT NOW(),
+    updated_at TIMESTAMP WITH TIME ZONE DEFAULT NOW()
+);
+
+CREATE INDEX idx_match_events_match ON match_events(match_id, minute);
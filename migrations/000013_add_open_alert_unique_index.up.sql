@@ -0,0 +1 @@
+CREATE UNIQUE INDEX idx_alerts_open_rule_player ON alerts(rule_id, player_id) WHERE state = 'open';